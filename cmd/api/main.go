@@ -0,0 +1,104 @@
+// Command api serves jobs.db over a read-only HTTP API and, when
+// API_GRPC_ADDR is set, the gRPC JobService (see pkg/api).
+package main
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"github.com/entreya/job-aggregation/pkg/api"
+	"github.com/entreya/job-aggregation/pkg/logger"
+)
+
+func main() {
+	env := os.Getenv("ENV")
+	if env == "" {
+		env = "development"
+	}
+	log := logger.Init(env)
+
+	addr := envOr("API_ADDR", ":8080")
+	srv, err := api.New(api.Config{
+		DBPath:       envOr("API_DB", "jobs.db"),
+		MetadataPath: envOr("API_METADATA", "metadata.json"),
+		Logger:       log,
+	})
+	if err != nil {
+		log.Error("failed to open database",
+			slog.String("error", err.Error()),
+		)
+		os.Exit(1)
+	}
+	defer srv.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if grpcAddr := os.Getenv("API_GRPC_ADDR"); grpcAddr != "" {
+		lis, err := net.Listen("tcp", grpcAddr)
+		if err != nil {
+			log.Error("failed to listen for gRPC",
+				slog.String("addr", grpcAddr),
+				slog.String("error", err.Error()),
+			)
+			srv.Close()
+			os.Exit(1)
+		}
+		grpcServer := grpc.NewServer()
+		srv.RegisterJobService(grpcServer)
+		go func() {
+			<-ctx.Done()
+			// Stop rather than GracefulStop: WatchJobs streams never end on
+			// their own.
+			grpcServer.Stop()
+		}()
+		go func() {
+			log.Info("grpc listening", slog.String("addr", grpcAddr))
+			if err := grpcServer.Serve(lis); err != nil {
+				log.Error("grpc server failed",
+					slog.String("error", err.Error()),
+				)
+			}
+		}()
+	}
+
+	httpServer := &http.Server{
+		Addr:              addr,
+		Handler:           srv.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+		WriteTimeout:      30 * time.Second,
+		IdleTimeout:       2 * time.Minute,
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		httpServer.Shutdown(shutdownCtx)
+	}()
+
+	log.Info("api listening", slog.String("addr", addr))
+	if err := httpServer.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		log.Error("api server failed",
+			slog.String("error", err.Error()),
+		)
+		srv.Close()
+		os.Exit(1)
+	}
+}
+
+// envOr returns the environment variable key, or def when it is unset.
+func envOr(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
@@ -0,0 +1,212 @@
+// Command digest manages keyword subscriptions and sends each subscriber a
+// digest of the new jobs matching them.
+//
+//	digest add -subscriber a@example.org -keywords engineer,clerk -qualification graduate
+//	digest list
+//	digest remove -id 3
+//	digest run
+//
+// Subscriptions live in their own database (SUBSCRIPTIONS_DB, default
+// subscriptions.db), never in the published jobs.db.
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/entreya/job-aggregation/pkg/db"
+	"github.com/entreya/job-aggregation/pkg/digest"
+	"github.com/entreya/job-aggregation/pkg/logger"
+	"github.com/entreya/job-aggregation/pkg/notify"
+)
+
+const usage = `usage: digest <command> [flags]
+
+commands:
+  add     add a subscription
+  list    list subscriptions
+  remove  remove a subscription
+  run     send digests of new matching jobs
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	env := os.Getenv("ENV")
+	if env == "" {
+		env = "development"
+	}
+	log := logger.Init(env)
+
+	subsPath := os.Getenv("SUBSCRIPTIONS_DB")
+	if subsPath == "" {
+		subsPath = "subscriptions.db"
+	}
+	subs, err := db.OpenSubscriptions(subsPath)
+	if err != nil {
+		log.Error("failed to open subscriptions database",
+			slog.String("error", err.Error()),
+		)
+		os.Exit(1)
+	}
+	defer subs.Close()
+
+	args := os.Args[2:]
+	switch os.Args[1] {
+	case "add":
+		err = addCommand(subs, args)
+	case "list":
+		err = listCommand(subs)
+	case "remove":
+		err = removeCommand(subs, args)
+	case "run":
+		err = runCommand(log, subs, args)
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		subs.Close()
+		os.Exit(2)
+	}
+	if err != nil {
+		log.Error("digest "+os.Args[1]+" failed",
+			slog.String("error", err.Error()),
+		)
+		subs.Close()
+		os.Exit(1)
+	}
+}
+
+func addCommand(subs *db.SubscriptionDB, args []string) error {
+	fs := flag.NewFlagSet("add", flag.ExitOnError)
+	subscriber := fs.String("subscriber", "", "email address to send digests to (required)")
+	name := fs.String("name", "", "label shown in the digest")
+	keywords := fs.String("keywords", "", "comma-separated keywords matched against title, department and advert")
+	departments := fs.String("departments", "", "comma-separated departments")
+	locations := fs.String("locations", "", "comma-separated locations")
+	qualification := fs.String("qualification", "", "highest qualification: 10th, 12th, diploma, graduate or postgraduate")
+	format := fs.String("format", digest.FormatHTML, "digest format: html, text or json")
+	fs.Parse(args)
+
+	if *subscriber == "" {
+		return errors.New("-subscriber is required")
+	}
+	qual, err := digest.ParseQualification(*qualification)
+	if err != nil {
+		return err
+	}
+	if !digest.ValidFormat(*format) {
+		return fmt.Errorf("unknown format %q", *format)
+	}
+
+	id, err := subs.AddSubscription(db.Subscription{
+		Subscriber:    *subscriber,
+		Name:          *name,
+		Keywords:      splitList(*keywords),
+		Departments:   splitList(*departments),
+		Locations:     splitList(*locations),
+		Qualification: qual.String(),
+		Format:        *format,
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("added subscription %d\n", id)
+	return nil
+}
+
+func listCommand(subs *db.SubscriptionDB) error {
+	list, err := subs.Subscriptions()
+	if err != nil {
+		return err
+	}
+	for _, s := range list {
+		fmt.Printf("%d\t%s\t%s\tkeywords=%s departments=%s locations=%s qualification=%s format=%s\n",
+			s.ID, s.Subscriber, s.Name,
+			strings.Join(s.Keywords, ","), strings.Join(s.Departments, ","), strings.Join(s.Locations, ","),
+			s.Qualification, s.Format)
+	}
+	return nil
+}
+
+func removeCommand(subs *db.SubscriptionDB, args []string) error {
+	fs := flag.NewFlagSet("remove", flag.ExitOnError)
+	id := fs.Int64("id", 0, "subscription ID (required)")
+	fs.Parse(args)
+
+	if err := subs.DeleteSubscription(*id); errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("no subscription %d", *id)
+	} else if err != nil {
+		return err
+	}
+	fmt.Printf("removed subscription %d\n", *id)
+	return nil
+}
+
+func runCommand(log *slog.Logger, subs *db.SubscriptionDB, args []string) error {
+	fs := flag.NewFlagSet("run", flag.ExitOnError)
+	jobsPath := fs.String("db", "jobs.db", "jobs database")
+	outDir := fs.String("out", "digests", "directory to write rendered digests to; empty to skip")
+	fs.Parse(args)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// The digest only reads jobs.db; opening it read-only reports a wrong
+	// path or an out-of-date schema instead of creating or migrating it.
+	jobs, err := db.OpenReadOnly(*jobsPath)
+	if err != nil {
+		return err
+	}
+	defer jobs.Close()
+
+	r := &digestRun{
+		log:    log,
+		jobs:   jobs,
+		subs:   subs,
+		outDir: *outDir,
+		send:   emailFromEnv(log),
+		now:    time.Now,
+	}
+	return r.run(ctx)
+}
+
+// emailFromEnv sends digests through the SMTP server configured for
+// notifications (NOTIFY_SMTP_*), or returns nil when there is none.
+func emailFromEnv(log *slog.Logger) sendFunc {
+	addr := os.Getenv("NOTIFY_SMTP_ADDR")
+	if addr == "" {
+		log.Info("NOTIFY_SMTP_ADDR not set; digests are only written to disk")
+		return nil
+	}
+	sink := &notify.EmailSink{
+		Addr:     addr,
+		Username: os.Getenv("NOTIFY_SMTP_USERNAME"),
+		Password: os.Getenv("NOTIFY_SMTP_PASSWORD"),
+		From:     os.Getenv("NOTIFY_EMAIL_FROM"),
+	}
+	return func(ctx context.Context, to, subject, contentType string, body []byte) error {
+		return sink.SendMessage(ctx, []string{to}, subject, contentType, body)
+	}
+}
+
+// splitList splits a comma-separated list, dropping empty entries.
+func splitList(s string) []string {
+	var out []string
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/entreya/job-aggregation/pkg/db"
+	"github.com/entreya/job-aggregation/pkg/digest"
+)
+
+// sendFunc delivers one rendered digest to a subscriber.
+type sendFunc func(ctx context.Context, to, subject, contentType string, body []byte) error
+
+// digestRun generates and delivers one round of digests. main wires the
+// real databases and SMTP; tests substitute fakes.
+type digestRun struct {
+	log    *slog.Logger
+	jobs   db.Store
+	subs   *db.SubscriptionDB
+	outDir string   // Rendered digests are written here when set
+	send   sendFunc // Digests are emailed when set
+	now    func() time.Time
+}
+
+// run renders a digest for every subscription with new matching jobs,
+// writes and sends it, and advances the subscription so the same jobs are
+// not sent again. A subscription whose digest could not be delivered is
+// left as it was and retried on the next run.
+func (r *digestRun) run(ctx context.Context) error {
+	jobs, err := r.jobs.AllJobs()
+	if err != nil {
+		return err
+	}
+	subs, err := r.subs.Subscriptions()
+	if err != nil {
+		return err
+	}
+
+	until := r.now()
+	digests := digest.Generate(subs, jobs, until)
+	r.log.Info("digests generated",
+		slog.Int("subscriptions", len(subs)),
+		slog.Int("digests", len(digests)),
+	)
+
+	var errs []error
+	for _, d := range digests {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := r.deliver(ctx, d); err != nil {
+			r.log.Warn("digest not delivered",
+				slog.Int64("subscription", d.Subscription.ID),
+				slog.String("error", err.Error()),
+			)
+			errs = append(errs, fmt.Errorf("subscription %d: %w", d.Subscription.ID, err))
+			continue
+		}
+		if err := r.subs.MarkDigested(d.Subscription.ID, until.Unix()); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// deliver renders d, writes it to outDir and emails it.
+func (r *digestRun) deliver(ctx context.Context, d digest.Digest) error {
+	var body bytes.Buffer
+	if err := digest.Render(&body, d); err != nil {
+		return fmt.Errorf("failed to render digest: %w", err)
+	}
+	format := d.Subscription.Format
+
+	if r.outDir != "" {
+		name := fmt.Sprintf("%d-%s%s", d.Subscription.ID, d.Until.UTC().Format("20060102T150405Z"), digest.Extension(format))
+		path := filepath.Join(r.outDir, name)
+		if err := os.MkdirAll(r.outDir, 0755); err != nil {
+			return fmt.Errorf("failed to create %s: %w", r.outDir, err)
+		}
+		if err := os.WriteFile(path, body.Bytes(), 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+	}
+
+	if r.send != nil {
+		if !strings.Contains(d.Subscription.Subscriber, "@") {
+			return fmt.Errorf("subscriber %q is not an email address", d.Subscription.Subscriber)
+		}
+		if err := r.send(ctx, d.Subscription.Subscriber, digest.Subject(d), digest.ContentType(format), body.Bytes()); err != nil {
+			return err
+		}
+	}
+
+	r.log.Info("digest delivered",
+		slog.Int64("subscription", d.Subscription.ID),
+		slog.Int("jobs", len(d.Jobs)),
+	)
+	return nil
+}
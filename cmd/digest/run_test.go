@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/entreya/job-aggregation/pkg/db"
+)
+
+func testRun(t *testing.T, send sendFunc) (*digestRun, *db.SubscriptionDB) {
+	t.Helper()
+	dir := t.TempDir()
+	subs, err := db.OpenSubscriptions(filepath.Join(dir, "subscriptions.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { subs.Close() })
+
+	jobs := db.NewMemStore()
+	_, err = jobs.UpsertJobs(context.Background(), []db.Job{
+		{ID: "je", Title: "Junior Engineer", URL: "https://ssc.gov.in/je.pdf", LastSeenAt: 200},
+		{ID: "clerk", Title: "Clerk", URL: "https://ssc.gov.in/clerk.pdf", LastSeenAt: 300},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &digestRun{
+		log:    slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelWarn})),
+		jobs:   jobs,
+		subs:   subs,
+		outDir: filepath.Join(dir, "digests"),
+		send:   send,
+		now:    func() time.Time { return time.Unix(1000, 0) },
+	}, subs
+}
+
+type sent struct{ to, subject, contentType, body string }
+
+func TestDigestRun_WritesSendsAndAdvances(t *testing.T) {
+	var got []sent
+	r, subs := testRun(t, func(ctx context.Context, to, subject, contentType string, body []byte) error {
+		got = append(got, sent{to, subject, contentType, string(body)})
+		return nil
+	})
+	id, err := subs.AddSubscription(db.Subscription{Subscriber: "a@example.org", Keywords: []string{"engineer"}, Format: "text", CreatedAt: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := r.run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].to != "a@example.org" || got[0].subject != "1 new job" ||
+		!strings.HasPrefix(got[0].contentType, "text/plain") || !strings.Contains(got[0].body, "Junior Engineer") {
+		t.Fatalf("unexpected sends %+v", got)
+	}
+	files, _ := filepath.Glob(filepath.Join(r.outDir, "*.txt"))
+	if len(files) != 1 {
+		t.Errorf("expected one digest file, got %v", files)
+	}
+
+	list, err := subs.Subscriptions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if list[0].ID != id || list[0].LastDigestAt != 1000 {
+		t.Errorf("subscription not advanced: %+v", list[0])
+	}
+
+	// Nothing new since the last digest.
+	if err := r.run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 {
+		t.Errorf("expected no second digest, got %d sends", len(got))
+	}
+}
+
+func TestDigestRun_FailedSendIsRetriedNextRun(t *testing.T) {
+	r, subs := testRun(t, func(ctx context.Context, to, subject, contentType string, body []byte) error {
+		return errors.New("connection refused")
+	})
+	if _, err := subs.AddSubscription(db.Subscription{Subscriber: "a@example.org", CreatedAt: 100}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := r.run(context.Background()); err == nil {
+		t.Fatal("expected the failed send to be reported")
+	}
+	list, err := subs.Subscriptions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if list[0].LastDigestAt != 0 {
+		t.Errorf("undelivered digest advanced the subscription to %d", list[0].LastDigestAt)
+	}
+}
@@ -1,14 +1,12 @@
 package main
 
 import (
-	"crypto/sha256"
-	"encoding/hex"
-	"encoding/json"
-	"fmt"
-	"io"
+	"context"
 	"log/slog"
 	"os"
-	"path/filepath"
+	"os/signal"
+	"strconv"
+	"syscall"
 	"time"
 
 	"github.com/entreya/job-aggregation/pkg/db"
@@ -18,17 +16,6 @@ import (
 	"github.com/entreya/job-aggregation/pkg/scraper"
 )
 
-const (
-	jobsJSONPath = "data/jobs.json"
-)
-
-// Metadata represents the sync metadata for client-side update checks.
-type Metadata struct {
-	LastUpdated int64  `json:"last_updated"`
-	Checksum    string `json:"checksum"`
-	JobCount    int    `json:"job_count"`
-}
-
 func main() {
 	// ─── 1. Initialize structured logger ───────────────────────────────
 	env := os.Getenv("ENV")
@@ -40,154 +27,245 @@ func main() {
 		slog.String("env", env),
 	)
 
+	// SIGINT/SIGTERM cancel ctx, which aborts in-flight scrapes, retries and
+	// delays. The pipeline then closes the DB before exiting.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	// ─── 2. Initialize proxy rotator ───────────────────────────────────
-	proxyURLs := os.Getenv("PROXY_URLS")
+	// The pool is PROXY_URLS plus the optional list file and provider
+	// endpoint; the latter two are reloaded while the scraper runs.
 	proxyStrategy := os.Getenv("PROXY_STRATEGY")
 	if proxyStrategy == "" {
 		proxyStrategy = "round-robin"
 	}
-
-	rotator, err := proxy.NewRotator(proxyURLs, proxyStrategy, log)
+	loaders := []proxy.Loader{proxy.StaticLoader(os.Getenv("PROXY_URLS"))}
+	if path := os.Getenv("PROXY_FILE"); path != "" {
+		loaders = append(loaders, proxy.FileLoader(path))
+	}
+	if endpoint := os.Getenv("PROXY_PROVIDER_URL"); endpoint != "" {
+		loaders = append(loaders, proxy.ProviderLoader(endpoint, nil))
+	}
+	loadProxies := proxy.Combine(loaders...)
+	proxies, err := loadProxies(ctx)
 	if err != nil {
-		log.Error("failed to initialize proxy rotator",
+		log.Error("failed to load proxies",
 			slog.String("error", err.Error()),
 		)
 		os.Exit(1)
 	}
 
-	// ─── 3. Initialize database ────────────────────────────────────────
-	dbPath := "jobs.db"
-	database, err := db.InitDB(dbPath)
-	if err != nil {
-		log.Error("failed to initialize database",
-			slog.String("error", err.Error()),
-		)
-		os.Exit(1)
+	// Egress verification needs both an echo endpoint and a GeoIP database;
+	// once enabled, proxies are only used after their exit is located.
+	verifyCfg := proxy.VerifyConfig{
+		EchoURL:  os.Getenv("PROXY_VERIFY_URL"),
+		Interval: envDuration(log, "PROXY_VERIFY_INTERVAL", time.Hour),
+	}
+	if verifyCfg.EchoURL != "" {
+		path := os.Getenv("PROXY_GEOIP_DB")
+		if path == "" {
+			log.Error("PROXY_VERIFY_URL is set but PROXY_GEOIP_DB is not")
+			os.Exit(1)
+		}
+		verifyCfg.GeoDB, err = proxy.OpenGeoDB(path)
+		if err != nil {
+			log.Error("failed to load GeoIP database",
+				slog.String("error", err.Error()),
+			)
+			os.Exit(1)
+		}
 	}
 
-	// ─── 4. Configure and run scraper ──────────────────────────────────
-	chromePath := os.Getenv("CHROME_PATH")
-	s := scraper.NewScraper(scraper.Config{
-		TargetURL:  "https://recruitment.nic.in/index_new.php",
-		Rotator:    rotator,
-		RetryCfg:   scraper.DefaultRetryConfig(),
-		Logger:     log,
-		ChromePath: chromePath,
-		Timeout:    90 * time.Second,
+	rotator, err := proxy.New(proxy.Config{
+		Proxies:  proxies,
+		Strategy: proxyStrategy,
+		Health: proxy.HealthConfig{
+			FailureThreshold: envInt(log, "PROXY_FAILURE_THRESHOLD", 3),
+			Cooldown:         envDuration(log, "PROXY_COOLDOWN", 5*time.Minute),
+		},
+		Logger:          log,
+		RequireVerified: verifyCfg.GeoDB != nil,
 	})
-
-	jobsList, err := s.Scrape()
 	if err != nil {
-		log.Error("scrape failed",
+		log.Error("failed to initialize proxy rotator",
 			slog.String("error", err.Error()),
 		)
-		// Close DB before exiting
-		if closeErr := database.OptimizeAndClose(); closeErr != nil {
-			log.Error("failed to close DB", slog.String("error", closeErr.Error()))
-		}
 		os.Exit(1)
 	}
+	// Optional background reload, probe and egress verification; all stop
+	// when ctx is cancelled. The first verification round runs before any
+	// source is scraped.
+	if len(loaders) > 1 {
+		rotator.StartReload(ctx, loadProxies, envDuration(log, "PROXY_RELOAD_INTERVAL", time.Minute))
+	}
+	rotator.StartProbe(ctx, proxy.ProbeConfig{
+		URL:      os.Getenv("PROXY_PROBE_URL"),
+		Interval: envDuration(log, "PROXY_PROBE_INTERVAL", 5*time.Minute),
+	})
+	rotator.StartVerify(ctx, verifyCfg)
 
-	log.Info("scrape successful",
-		slog.Int("jobs_count", len(jobsList.Jobs)),
-	)
+	// ─── 3. Select sources ─────────────────────────────────────────────
+	registry := scraper.DefaultRegistry()
 
-	// ─── 5. Insert jobs into SQLite (upsert) ───────────────────────────
-	for _, j := range jobsList.Jobs {
-		job := db.Job{
-			ID:         j.Id,
-			Title:      j.Title,
-			Department: j.Department,
-			Location:   j.Location,
-			PostedDate: time.Now().Unix(),
-			URL:        j.Url,
-		}
-		if err := database.UpsertJob(job); err != nil {
-			log.Warn("failed to upsert job",
-				slog.String("job_id", j.Id),
+	// Config-defined sources are added to (or replace) the built-in ones.
+	if rulesPath := os.Getenv("RULES_FILE"); rulesPath != "" {
+		ruleSources, err := scraper.LoadRules(rulesPath)
+		if err != nil {
+			log.Error("failed to load extraction rules",
+				slog.String("file", rulesPath),
 				slog.String("error", err.Error()),
 			)
+			os.Exit(1)
+		}
+		for _, src := range ruleSources {
+			if err := registry.Override(src); err != nil {
+				log.Error("failed to register rule source",
+					slog.String("source", src.Name()),
+					slog.String("error", err.Error()),
+				)
+				os.Exit(1)
+			}
 		}
+		log.Info("extraction rules loaded",
+			slog.String("file", rulesPath),
+			slog.Int("sources", len(ruleSources)),
+		)
 	}
 
-	// ─── 6. Optimize and close DB ──────────────────────────────────────
-	if err := database.OptimizeAndClose(); err != nil {
-		log.Error("failed to optimize and close DB",
+	sources, err := registry.Select(os.Getenv("SOURCES"))
+	if err != nil {
+		log.Error("invalid SOURCES selection",
 			slog.String("error", err.Error()),
 		)
 		os.Exit(1)
 	}
 
-	// ─── 7. Generate metadata ──────────────────────────────────────────
-	if err := generateMetadata(dbPath, len(jobsList.Jobs)); err != nil {
-		log.Error("failed to generate metadata",
+	// ─── 4. Configure scraper ──────────────────────────────────────────
+	chromePath := os.Getenv("CHROME_PATH")
+	s := scraper.NewScraper(scraper.Config{
+		Rotator:      rotator,
+		RetryCfg:     scraper.DefaultRetryConfig(),
+		Logger:       log,
+		ChromePath:   chromePath,
+		Timeout:      90 * time.Second,
+		StrictEgress: envBool(log, "EGRESS_STRICT"),
+	})
+
+	orchestrator := scraper.NewOrchestrator(scraper.OrchestratorConfig{
+		Scraper:      s,
+		Workers:      envInt(log, "SCRAPE_WORKERS", 4),
+		PerHostLimit: envInt(log, "SCRAPE_PER_HOST_LIMIT", 1),
+		Logger:       log,
+	})
+
+	// ─── 5. Initialize database ────────────────────────────────────────
+	out := defaultOutputs()
+	database, err := db.InitDB(out.DBPath)
+	if err != nil {
+		log.Error("failed to initialize database",
 			slog.String("error", err.Error()),
 		)
 		os.Exit(1)
 	}
 
-	// ─── 8. Export to JSON (legacy format for Flutter client) ───────────
-	if err := exportToJSON(jobsList); err != nil {
-		log.Warn("error exporting to JSON (non-fatal)",
-			slog.String("error", err.Error()),
-		)
+	// ─── 6. Run the pipeline ───────────────────────────────────────────
+	p := &pipeline{
+		log:           log,
+		store:         database,
+		sources:       sources,
+		scrape:        orchestrator.Run,
+		notifier:      notifierFromEnv(log),
+		notifyTimeout: envDuration(log, "NOTIFY_TIMEOUT", 5*time.Minute),
+		out:           out,
+		now:           time.Now,
+	}
+	if envBool(log, "FETCH_ADVERTS") {
+		workers := envInt(log, "ADVERT_WORKERS", 2)
+		p.adverts = func(ctx context.Context, jobs []*models.JobPosting) map[string]*scraper.Advert {
+			return s.FetchAdverts(ctx, jobs, workers)
+		}
 	}
 
-	// ─── 9. Append to output file (new format with timestamps) ─────────
-	outputCfg := scraper.DefaultOutputConfig()
-	if err := scraper.AppendResults(jobsList.Jobs, outputCfg, log); err != nil {
-		log.Warn("error appending output (non-fatal)",
+	err = p.run(ctx)
+	logProxyStats(log, rotator)
+	if err != nil {
+		log.Error("pipeline failed",
 			slog.String("error", err.Error()),
 		)
+		os.Exit(1)
 	}
-
-	log.Info("pipeline complete",
-		slog.String("db", dbPath),
-		slog.String("metadata", "metadata.json"),
-		slog.String("json_export", jobsJSONPath),
-	)
 }
 
-func generateMetadata(dbPath string, count int) error {
-	file, err := os.Open(dbPath)
-	if err != nil {
-		return fmt.Errorf("failed to open db for hashing: %w", err)
+// logProxyStats logs the health each proxy ended the run with.
+func logProxyStats(log *slog.Logger, rotator *proxy.ProxyRotator) {
+	for _, st := range rotator.Stats() {
+		log.Info("proxy health",
+			slog.String("proxy", st.Proxy),
+			slog.Int("successes", st.Successes),
+			slog.Int("failures", st.Failures),
+			slog.Duration("latency", st.Latency),
+			slog.Float64("score", st.Score),
+			slog.Bool("ejected", !st.EjectedUntil.IsZero()),
+			slog.String("country", st.Country),
+			slog.String("region", st.Region),
+			slog.String("egress_ip", st.EgressIP),
+			slog.Bool("verified", st.Verified),
+		)
 	}
-	defer file.Close()
+}
 
-	hash := sha256.New()
-	if _, err := io.Copy(hash, file); err != nil {
-		return fmt.Errorf("failed to calculate hash: %w", err)
+// envInt reads a positive integer from the environment, returning def when
+// the variable is unset or invalid.
+func envInt(log *slog.Logger, key string, def int) int {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return def
 	}
-	checksum := hex.EncodeToString(hash.Sum(nil))
-
-	meta := Metadata{
-		LastUpdated: time.Now().Unix(),
-		Checksum:    checksum,
-		JobCount:    count,
+	n, err := strconv.Atoi(raw)
+	if err != nil || n <= 0 {
+		log.Warn("ignoring invalid integer env var",
+			slog.String("key", key),
+			slog.String("value", raw),
+			slog.Int("default", def),
+		)
+		return def
 	}
+	return n
+}
 
-	data, err := json.MarshalIndent(meta, "", "  ")
-	if err != nil {
-		return fmt.Errorf("failed to marshal metadata: %w", err)
+// envDuration reads a positive duration such as "90s" or "5m" from the
+// environment, returning def when the variable is unset or invalid.
+func envDuration(log *slog.Logger, key string, def time.Duration) time.Duration {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return def
 	}
-
-	return os.WriteFile("metadata.json", data, 0644)
+	d, err := time.ParseDuration(raw)
+	if err != nil || d <= 0 {
+		log.Warn("ignoring invalid duration env var",
+			slog.String("key", key),
+			slog.String("value", raw),
+			slog.Duration("default", def),
+		)
+		return def
+	}
+	return d
 }
 
-func exportToJSON(jobList *models.JobList) error {
-	dir := filepath.Dir(jobsJSONPath)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return err
+// envBool reports whether the environment variable key is set to a true
+// value ("1", "true", ...). Unset or invalid values are false.
+func envBool(log *slog.Logger, key string) bool {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return false
 	}
-
-	file, err := os.Create(jobsJSONPath)
+	b, err := strconv.ParseBool(raw)
 	if err != nil {
-		return err
+		log.Warn("ignoring invalid boolean env var",
+			slog.String("key", key),
+			slog.String("value", raw),
+		)
+		return false
 	}
-	defer file.Close()
-
-	encoder := json.NewEncoder(file)
-	encoder.SetIndent("", "  ")
-	return encoder.Encode(jobList)
+	return b
 }
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/entreya/job-aggregation/pkg/notify"
+)
+
+// notifierFromEnv registers a sink for every configured NOTIFY_* destination.
+// It returns nil when none is configured.
+func notifierFromEnv(log *slog.Logger) *notify.Notifier {
+	n := notify.New(log)
+	// Chat and email sinks are read by people and rate limited: past
+	// maxEvents in a run they get one summary instead.
+	maxEvents := envInt(log, "NOTIFY_MAX_EVENTS", 20)
+
+	if url := os.Getenv("NOTIFY_WEBHOOK_URL"); url != "" {
+		sink := &notify.WebhookSink{URL: url}
+		if auth := os.Getenv("NOTIFY_WEBHOOK_AUTH"); auth != "" {
+			sink.Headers = map[string]string{"Authorization": auth}
+		}
+		n.Add(sink, notify.DefaultOptions())
+	}
+
+	if token, chat := os.Getenv("NOTIFY_TELEGRAM_TOKEN"), os.Getenv("NOTIFY_TELEGRAM_CHAT_ID"); token != "" && chat != "" {
+		// Telegram allows 20 messages a minute in a group.
+		opts := notify.DefaultOptions()
+		opts.MinInterval = 3 * time.Second
+		opts.MaxEvents = maxEvents
+		n.Add(&notify.TelegramSink{
+			APIBase: os.Getenv("NOTIFY_TELEGRAM_API"),
+			Token:   token,
+			ChatID:  chat,
+		}, opts)
+	}
+
+	if addr, to := os.Getenv("NOTIFY_SMTP_ADDR"), os.Getenv("NOTIFY_EMAIL_TO"); addr != "" && to != "" {
+		opts := notify.DefaultOptions()
+		opts.MinInterval = time.Second
+		opts.MaxEvents = maxEvents
+		n.Add(&notify.EmailSink{
+			Addr:     addr,
+			Username: os.Getenv("NOTIFY_SMTP_USERNAME"),
+			Password: os.Getenv("NOTIFY_SMTP_PASSWORD"),
+			From:     os.Getenv("NOTIFY_EMAIL_FROM"),
+			To:       splitList(to),
+		}, opts)
+	}
+
+	if path := os.Getenv("NOTIFY_FILE"); path != "" {
+		n.Add(&notify.FileSink{Path: path}, notify.DefaultOptions())
+	}
+
+	if n.Len() == 0 {
+		return nil
+	}
+	log.Info("notifications enabled", slog.Int("sinks", n.Len()))
+	return n
+}
+
+// splitList splits a comma-separated list, dropping empty entries.
+func splitList(s string) []string {
+	var out []string
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
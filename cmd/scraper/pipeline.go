@@ -0,0 +1,483 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/entreya/job-aggregation/pkg/db"
+	"github.com/entreya/job-aggregation/pkg/delta"
+	"github.com/entreya/job-aggregation/pkg/models"
+	"github.com/entreya/job-aggregation/pkg/notify"
+	"github.com/entreya/job-aggregation/pkg/scraper"
+)
+
+// Metadata represents the sync metadata for client-side update checks.
+// MinReaderVersion lets clients skip databases they can no longer read;
+// SchemaVersion is informational.
+// Version and Deltas let clients on a recent version fetch only the changes
+// (see pkg/delta); anyone older downloads jobs.db.
+type Metadata struct {
+	LastUpdated      int64        `json:"last_updated"`
+	Checksum         string       `json:"checksum"`
+	JobCount         int          `json:"job_count"`
+	SchemaVersion    int          `json:"schema_version"`
+	MinReaderVersion int          `json:"min_reader_version"`
+	Version          int          `json:"version"`
+	Deltas           []delta.Info `json:"deltas"`
+}
+
+// outputs lists the files a pipeline run writes.
+type outputs struct {
+	DBPath       string // Database file hashed into metadata.json
+	MetadataPath string
+	JobsJSONPath string // Legacy JSON export for the Flutter client
+	Deltas       delta.Config
+	Output       scraper.OutputConfig
+}
+
+// defaultOutputs returns the paths used by the scheduled workflows, relative
+// to the repository root.
+func defaultOutputs() outputs {
+	return outputs{
+		DBPath:       "jobs.db",
+		MetadataPath: "metadata.json",
+		JobsJSONPath: "data/jobs.json",
+		Deltas:       delta.DefaultConfig(),
+		Output:       scraper.DefaultOutputConfig(),
+	}
+}
+
+// pipeline is one scrape-and-publish run. main wires the real scraper and
+// database; tests substitute fakes.
+type pipeline struct {
+	log     *slog.Logger
+	store   db.Store
+	sources []scraper.Source
+	// scrape runs every source, e.g. Orchestrator.Run.
+	scrape func(ctx context.Context, sources []scraper.Source) []scraper.SourceResult
+	// adverts enriches jobs from their PDF advertisements. nil disables it.
+	adverts func(ctx context.Context, jobs []*models.JobPosting) map[string]*scraper.Advert
+	// notifier announces new and changed jobs after publishing. nil disables it.
+	notifier *notify.Notifier
+	// notifyTimeout bounds notification delivery so it cannot hold up
+	// publishing the run (0 = no limit).
+	notifyTimeout time.Duration
+	out           outputs
+	now           func() time.Time
+}
+
+// collected is what a successful collect hands on for publishing.
+type collected struct {
+	jobs   *models.JobList
+	delta  *delta.State
+	events []notify.Event
+}
+
+// run scrapes, stores and publishes. The store is always closed. An error
+// means nothing was published: metadata.json is only written on success.
+func (p *pipeline) run(ctx context.Context) error {
+	if p.now == nil {
+		p.now = time.Now
+	}
+
+	c, err := p.collect(ctx)
+	if err != nil {
+		if closeErr := p.store.OptimizeAndClose(); closeErr != nil {
+			p.log.Error("failed to close DB", slog.String("error", closeErr.Error()))
+		}
+		return err
+	}
+
+	// ─── Optimize and close DB ─────────────────────────────────────────
+	if err := p.store.OptimizeAndClose(); err != nil {
+		return fmt.Errorf("failed to optimize and close DB: %w", err)
+	}
+
+	// ─── Generate metadata ─────────────────────────────────────────────
+	if err := generateMetadata(p.out.DBPath, p.out.MetadataPath, len(c.jobs.Jobs), c.delta, p.now()); err != nil {
+		return fmt.Errorf("failed to generate metadata: %w", err)
+	}
+
+	// ─── Commit delta sync state ───────────────────────────────────────
+	// Only now that metadata.json names the new version may the next run
+	// build on it.
+	if err := delta.Commit(p.out.Deltas, c.delta); err != nil {
+		return fmt.Errorf("failed to commit delta sync state: %w", err)
+	}
+
+	// ─── Export to JSON (legacy format for Flutter client) ─────────────
+	if err := exportToJSON(p.out.JobsJSONPath, c.jobs); err != nil {
+		p.log.Warn("error exporting to JSON (non-fatal)",
+			slog.String("error", err.Error()),
+		)
+	}
+
+	// ─── Append to output file (new format with timestamps) ────────────
+	if err := scraper.AppendResults(c.jobs.Jobs, p.out.Output, p.log); err != nil {
+		p.log.Warn("error appending output (non-fatal)",
+			slog.String("error", err.Error()),
+		)
+	}
+
+	// ─── Notify ────────────────────────────────────────────────────────
+	if p.notifier != nil && len(c.events) > 0 {
+		nctx := ctx
+		if p.notifyTimeout > 0 {
+			var cancel context.CancelFunc
+			nctx, cancel = context.WithTimeout(ctx, p.notifyTimeout)
+			defer cancel()
+		}
+		if err := p.notifier.Notify(nctx, c.events); err != nil {
+			p.log.Warn("some notifications were not sent (non-fatal)",
+				slog.String("error", err.Error()),
+			)
+		}
+	}
+
+	p.log.Info("pipeline complete",
+		slog.String("db", p.out.DBPath),
+		slog.String("metadata", p.out.MetadataPath),
+		slog.String("json_export", p.out.JobsJSONPath),
+	)
+	return nil
+}
+
+// collect runs the scrape and writes its results to the store and the delta
+// directory. It leaves the store open.
+func (p *pipeline) collect(ctx context.Context) (*collected, error) {
+	// ─── Scrape ────────────────────────────────────────────────────────
+	// A failing source is logged but never fails the pipeline on its own;
+	// only a run where every source failed is fatal.
+	results := p.scrape(ctx, p.sources)
+	if ctx.Err() != nil {
+		return nil, fmt.Errorf("scrape interrupted — not writing results: %w", ctx.Err())
+	}
+
+	succeeded := 0
+	for _, r := range results {
+		if r.Err != nil {
+			p.log.Warn("source scrape failed",
+				slog.String("source", r.Source),
+				slog.Int("pages_ok", r.PagesOK),
+				slog.Int("pages_failed", r.PagesFailed),
+				slog.String("error", r.Err.Error()),
+			)
+		}
+		if r.OK() {
+			succeeded++
+		}
+	}
+	if succeeded == 0 {
+		return nil, errors.New("scrape failed: no source could be scraped")
+	}
+
+	jobsList := scraper.MergeResults(results)
+
+	p.log.Info("scrape successful",
+		slog.Int("sources_scraped", succeeded),
+		slog.Int("sources_total", len(p.sources)),
+		slog.Int("jobs_count", len(jobsList.Jobs)),
+	)
+
+	// Optional: download linked PDF advertisements and pull out key facts.
+	adverts := map[string]*scraper.Advert{}
+	if p.adverts != nil {
+		adverts = p.adverts(ctx, jobsList.Jobs)
+	}
+
+	// ─── Upsert jobs in one transaction ────────────────────────────────
+	seenAt := p.now().Unix()
+	jobs := dbJobs(results, jobsList, adverts, seenAt)
+	summary, err := p.store.UpsertJobs(ctx, jobs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to store jobs: %w", err)
+	}
+	p.log.Info("jobs stored",
+		slog.Int("inserted", summary.Inserted),
+		slog.Int("updated", summary.Updated),
+		slog.Int("unchanged", summary.Unchanged),
+	)
+
+	var events []notify.Event
+	if p.notifier != nil {
+		if events, err = p.events(summary); err != nil {
+			return nil, err
+		}
+	}
+
+	// Jobs no longer listed by a source are marked removed — but only for
+	// sources whose every page was scraped, so a flaky page cannot retire
+	// postings that are still live. A source that listed nothing is more
+	// likely a parser broken by a markup change than a board with no
+	// openings, so it retires nothing either.
+	for _, r := range results {
+		if r.Err != nil {
+			continue
+		}
+		if r.Jobs == nil || len(r.Jobs.Jobs) == 0 {
+			p.log.Warn("source listed no jobs — not marking its jobs removed",
+				slog.String("source", r.Source),
+				slog.Int("pages_ok", r.PagesOK),
+			)
+			continue
+		}
+		removed, err := p.store.MarkRemoved(r.Source, seenAt)
+		if err != nil {
+			p.log.Warn("failed to mark removed jobs",
+				slog.String("source", r.Source),
+				slog.String("error", err.Error()),
+			)
+			continue
+		}
+		if removed > 0 {
+			p.log.Info("jobs no longer listed marked removed",
+				slog.String("source", r.Source),
+				slog.Int64("count", removed),
+			)
+		}
+	}
+
+	expired, err := p.store.MarkExpired(p.now().Format("2006-01-02"))
+	if err != nil {
+		p.log.Warn("failed to mark expired jobs",
+			slog.String("error", err.Error()),
+		)
+	} else if expired > 0 {
+		p.log.Info("jobs past their closing date marked expired",
+			slog.Int64("count", expired),
+		)
+	}
+
+	// ─── Write delta sync artifacts ────────────────────────────────────
+	// A failure here is fatal: publishing a new jobs.db under the old
+	// version would make delta clients believe they are current.
+	storedJobs, err := p.store.AllJobs()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read jobs for delta sync: %w", err)
+	}
+	records := make([]delta.Record, 0, len(storedJobs))
+	duplicates := 0
+	for _, j := range storedJobs {
+		records = append(records, delta.RecordFromJob(j))
+		if j.CanonicalID != "" && j.CanonicalID != j.ID {
+			duplicates++
+		}
+	}
+	if duplicates > 0 {
+		p.log.Info("duplicate jobs linked to a canonical job",
+			slog.Int("count", duplicates),
+		)
+	}
+	previous, err := delta.Load(p.out.Deltas.Dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read delta sync state: %w", err)
+	}
+	// The new version is committed in run once metadata.json publishes it.
+	deltaState, err := delta.Prepare(p.out.Deltas, records, p.now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to write delta sync artifacts: %w", err)
+	}
+	p.log.Info("delta sync prepared",
+		slog.Int("version", deltaState.Version),
+		slog.Int("deltas_published", len(deltaState.Chain)),
+	)
+
+	// The first run has no previous run to compare with: every job would
+	// be announced as new.
+	if previous.Version == 0 && len(events) > 0 {
+		p.log.Info("first run — recording a baseline without notifications",
+			slog.Int("events_skipped", len(events)),
+		)
+		events = nil
+	}
+
+	return &collected{jobs: jobsList, delta: deltaState, events: events}, nil
+}
+
+// events turns an upsert summary into notifications: one per new job and
+// one per changed job. A new job linked to an already known canonical job
+// is a duplicate listing and is not announced.
+func (p *pipeline) events(summary db.UpsertSummary) ([]notify.Event, error) {
+	at := p.now()
+	events := make([]notify.Event, 0, len(summary.InsertedIDs))
+
+	for _, id := range summary.InsertedIDs {
+		job, err := p.store.GetJob(id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read new job %s: %w", id, err)
+		}
+		if job.CanonicalID != "" && job.CanonicalID != job.ID {
+			continue
+		}
+		events = append(events, notify.NewJobEvent(job, at))
+	}
+
+	var order []string
+	changes := make(map[string][]db.Change)
+	for _, c := range summary.Changes {
+		if _, ok := changes[c.JobID]; !ok {
+			order = append(order, c.JobID)
+		}
+		changes[c.JobID] = append(changes[c.JobID], c)
+	}
+	for _, id := range order {
+		job, err := p.store.GetJob(id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read changed job %s: %w", id, err)
+		}
+		events = append(events, notify.JobChangedEvent(job, changes[id], at))
+	}
+	return events, nil
+}
+
+// dbJobs converts the merged scrape results to database rows. Each job is
+// attributed to the first source that listed it, matching the precedence of
+// MergeResults.
+func dbJobs(results []scraper.SourceResult, jobsList *models.JobList, adverts map[string]*scraper.Advert, seenAt int64) []db.Job {
+	sourceOf := make(map[string]string, len(jobsList.Jobs))
+	for _, r := range results {
+		if r.Jobs == nil {
+			continue
+		}
+		for _, j := range r.Jobs.Jobs {
+			if _, ok := sourceOf[j.Id]; !ok {
+				sourceOf[j.Id] = r.Source
+			}
+		}
+	}
+
+	jobs := make([]db.Job, 0, len(jobsList.Jobs))
+	for _, j := range jobsList.Jobs {
+		job := db.Job{
+			ID:                    j.Id,
+			Title:                 j.Title,
+			Department:            j.Department,
+			DepartmentID:          j.DepartmentId,
+			Location:              j.Location,
+			LocationID:            j.LocationId,
+			PostedDate:            postedUnix(j.Date),
+			URL:                   j.Url,
+			Vacancies:             int(j.Vacancies),
+			PayLevel:              j.PayLevel,
+			AgeLimit:              j.AgeLimit,
+			ClosingDate:           j.LastDate,
+			ClosingDateConfidence: j.LastDateConfidence,
+			AdvertisementNo:       j.AdvertisementNo,
+			Qualifications:        j.Qualifications,
+			PayScale:              j.PayScale,
+			MinAge:                int(j.MinAge),
+			MaxAge:                int(j.MaxAge),
+			ApplicationMode:       applicationMode(j.ApplicationMode),
+			Attachments:           attachments(j.Attachments),
+			Source:                sourceOf[j.Id],
+			LastSeenAt:            seenAt,
+			Fingerprint:           scraper.Fingerprint(j),
+		}
+		if job.Source == "" {
+			job.Source = j.Source
+		}
+		if advert, ok := adverts[j.Id]; ok {
+			job.AdvertText = advert.Text
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs
+}
+
+// applicationMode converts a posting's application mode to its jobs.db
+// value, "" when unknown.
+func applicationMode(mode models.ApplicationMode) string {
+	switch mode {
+	case models.ApplicationMode_APPLICATION_MODE_ONLINE:
+		return db.ApplicationOnline
+	case models.ApplicationMode_APPLICATION_MODE_OFFLINE:
+		return db.ApplicationOffline
+	case models.ApplicationMode_APPLICATION_MODE_BOTH:
+		return db.ApplicationBoth
+	}
+	return ""
+}
+
+// attachments converts a posting's attachments to their jobs.db form.
+func attachments(list []*models.Attachment) []db.Attachment {
+	if len(list) == 0 {
+		return nil
+	}
+	out := make([]db.Attachment, len(list))
+	for i, a := range list {
+		out[i] = db.Attachment{Title: a.Title, URL: a.Url}
+	}
+	return out
+}
+
+// postedUnix converts a listing's YYYY-MM-DD posting date to a Unix
+// timestamp, or 0 when the listing gave none.
+func postedUnix(date string) int64 {
+	t, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return 0
+	}
+	return t.Unix()
+}
+
+func generateMetadata(dbPath, metadataPath string, count int, deltaState *delta.State, now time.Time) error {
+	file, err := os.Open(dbPath)
+	if err != nil {
+		return fmt.Errorf("failed to open db for hashing: %w", err)
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return fmt.Errorf("failed to calculate hash: %w", err)
+	}
+	checksum := hex.EncodeToString(hash.Sum(nil))
+
+	deltas := deltaState.Chain
+	if deltas == nil {
+		deltas = []delta.Info{}
+	}
+
+	meta := Metadata{
+		LastUpdated:      now.Unix(),
+		Checksum:         checksum,
+		JobCount:         count,
+		SchemaVersion:    db.SchemaVersion,
+		MinReaderVersion: db.MinReaderVersion,
+		Version:          deltaState.Version,
+		Deltas:           deltas,
+	}
+
+	data, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal metadata: %w", err)
+	}
+
+	return os.WriteFile(metadataPath, data, 0644)
+}
+
+func exportToJSON(path string, jobList *models.JobList) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(jobList)
+}
@@ -0,0 +1,352 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/entreya/job-aggregation/pkg/db"
+	"github.com/entreya/job-aggregation/pkg/delta"
+	"github.com/entreya/job-aggregation/pkg/models"
+	"github.com/entreya/job-aggregation/pkg/notify"
+	"github.com/entreya/job-aggregation/pkg/scraper"
+)
+
+func testPipelineLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelWarn}))
+}
+
+// testOutputs points every pipeline output into dir.
+func testOutputs(dir string) outputs {
+	return outputs{
+		DBPath:       filepath.Join(dir, "jobs.db"),
+		MetadataPath: filepath.Join(dir, "metadata.json"),
+		JobsJSONPath: filepath.Join(dir, "data", "jobs.json"),
+		Deltas:       delta.Config{Dir: filepath.Join(dir, "deltas"), Keep: 5},
+		Output:       scraper.OutputConfig{Dir: filepath.Join(dir, "output"), Format: "json"},
+	}
+}
+
+// memOutputs is testOutputs for runs against a MemStore. Metadata hashes the
+// database file, so a placeholder stands in for it.
+func memOutputs(t *testing.T, dir string) outputs {
+	t.Helper()
+	out := testOutputs(dir)
+	if err := os.WriteFile(out.DBPath, []byte("placeholder"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+// fakeScrape returns a scrape func that yields results regardless of the
+// sources it is given.
+func fakeScrape(results ...scraper.SourceResult) func(context.Context, []scraper.Source) []scraper.SourceResult {
+	return func(context.Context, []scraper.Source) []scraper.SourceResult {
+		return results
+	}
+}
+
+func listed(source string, jobs ...*models.JobPosting) scraper.SourceResult {
+	return scraper.SourceResult{
+		Source:  source,
+		Jobs:    &models.JobList{Jobs: jobs},
+		PagesOK: 1,
+	}
+}
+
+func posting(id, title string) *models.JobPosting {
+	return &models.JobPosting{Id: id, Title: title, Date: "2026-03-01", Url: "https://example.gov.in/" + id}
+}
+
+func TestPipelineRun_PublishesResults(t *testing.T) {
+	dir := t.TempDir()
+	store := db.NewMemStore()
+	p := &pipeline{
+		log:    testPipelineLogger(),
+		store:  store,
+		scrape: fakeScrape(listed("nic", posting("a", "Clerk"), posting("b", "Driver"))),
+		out:    memOutputs(t, dir),
+		now:    func() time.Time { return time.Unix(1772300000, 0) },
+	}
+
+	if err := p.run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if !store.Closed() {
+		t.Error("expected store to be closed after run")
+	}
+
+	data, err := os.ReadFile(p.out.MetadataPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var meta Metadata
+	if err := json.Unmarshal(data, &meta); err != nil {
+		t.Fatal(err)
+	}
+	if meta.JobCount != 2 || meta.Version != 1 || meta.SchemaVersion != db.SchemaVersion || meta.MinReaderVersion != db.MinReaderVersion || meta.Checksum == "" {
+		t.Errorf("unexpected metadata %+v", meta)
+	}
+	if meta.LastUpdated != 1772300000 {
+		t.Errorf("expected last_updated from the pipeline clock, got %d", meta.LastUpdated)
+	}
+
+	if _, err := os.Stat(p.out.JobsJSONPath); err != nil {
+		t.Errorf("expected jobs.json export: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(p.out.Deltas.Dir, "state.json")); err != nil {
+		t.Errorf("expected delta state: %v", err)
+	}
+}
+
+func TestPipelineRun_AllSourcesFailed(t *testing.T) {
+	dir := t.TempDir()
+	store := db.NewMemStore()
+	p := &pipeline{
+		log:   testPipelineLogger(),
+		store: store,
+		scrape: fakeScrape(scraper.SourceResult{
+			Source:      "nic",
+			Err:         errors.New("connection refused"),
+			PagesFailed: 3,
+		}),
+		out: memOutputs(t, dir),
+	}
+
+	if err := p.run(context.Background()); err == nil {
+		t.Fatal("expected an error when every source failed")
+	}
+	if !store.Closed() {
+		t.Error("expected store to be closed after a failed run")
+	}
+	if _, err := os.Stat(p.out.MetadataPath); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected no metadata.json after a failed run, got %v", err)
+	}
+}
+
+func TestPipelineRun_InterruptedWritesNothing(t *testing.T) {
+	dir := t.TempDir()
+	store := db.NewMemStore()
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &pipeline{
+		log:   testPipelineLogger(),
+		store: store,
+		scrape: func(context.Context, []scraper.Source) []scraper.SourceResult {
+			cancel()
+			return []scraper.SourceResult{listed("nic", posting("a", "Clerk"))}
+		},
+		out: memOutputs(t, dir),
+	}
+
+	if err := p.run(ctx); err == nil {
+		t.Fatal("expected an error for an interrupted run")
+	}
+	if _, err := os.Stat(p.out.Deltas.Dir); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected no delta artifacts after an interrupted run, got %v", err)
+	}
+}
+
+func TestPipelineRun_FailedMetadataKeepsDeltaState(t *testing.T) {
+	dir := t.TempDir()
+	store := db.NewMemStore()
+	p := &pipeline{
+		log:    testPipelineLogger(),
+		store:  store,
+		scrape: fakeScrape(listed("nic", posting("a", "Clerk"))),
+		out:    memOutputs(t, dir),
+	}
+	// A directory where metadata.json should go makes writing it fail.
+	if err := os.Mkdir(p.out.MetadataPath, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := p.run(context.Background()); err == nil {
+		t.Fatal("expected an error when metadata cannot be written")
+	}
+	state, err := delta.Load(p.out.Deltas.Dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.Version != 0 {
+		t.Errorf("expected no delta version committed without metadata, got %d", state.Version)
+	}
+}
+
+func TestPipelineRun_MarksUnlistedJobsRemoved(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Unix(1772300000, 0)
+
+	runOnce := func(results ...scraper.SourceResult) {
+		t.Helper()
+		out := testOutputs(dir)
+		database, err := db.InitDB(out.DBPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		p := &pipeline{
+			log:    testPipelineLogger(),
+			store:  database,
+			scrape: fakeScrape(results...),
+			out:    out,
+			now:    func() time.Time { return now },
+		}
+		if err := p.run(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		now = now.Add(24 * time.Hour)
+	}
+
+	runOnce(listed("nic", posting("a", "Clerk"), posting("b", "Driver")))
+	runOnce(listed("nic", posting("a", "Clerk")))
+
+	database, err := db.InitDB(filepath.Join(dir, "jobs.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer database.OptimizeAndClose()
+
+	a, err := database.GetJob("a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := database.GetJob("b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Status != db.StatusActive || a.Source != "nic" {
+		t.Errorf("expected a active from nic, got %+v", a)
+	}
+	if b.Status != db.StatusRemoved {
+		t.Errorf("expected b removed, got status %q", b.Status)
+	}
+}
+
+func TestPipelineRun_EmptySourceRemovesNothing(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Unix(1772300000, 0)
+
+	runOnce := func(results ...scraper.SourceResult) {
+		t.Helper()
+		out := testOutputs(dir)
+		database, err := db.InitDB(out.DBPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		p := &pipeline{
+			log:    testPipelineLogger(),
+			store:  database,
+			scrape: fakeScrape(results...),
+			out:    out,
+			now:    func() time.Time { return now },
+		}
+		if err := p.run(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		now = now.Add(24 * time.Hour)
+	}
+
+	runOnce(listed("nic", posting("a", "Clerk"), posting("b", "Driver")))
+	// Every page of nic loads, but a markup change leaves the parser with
+	// nothing; another source is unaffected.
+	runOnce(listed("nic"), listed("ssc", posting("c", "Steno")))
+
+	database, err := db.InitDB(filepath.Join(dir, "jobs.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer database.OptimizeAndClose()
+
+	for _, id := range []string{"a", "b", "c"} {
+		job, err := database.GetJob(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if job.Status != db.StatusActive {
+			t.Errorf("expected %s active, got status %q", id, job.Status)
+		}
+	}
+}
+
+func TestPipelineRun_NotifiesNewAndChangedJobs(t *testing.T) {
+	dir := t.TempDir()
+	eventsPath := filepath.Join(dir, "events.jsonl")
+	now := time.Unix(1772300000, 0)
+
+	runOnce := func(results ...scraper.SourceResult) {
+		t.Helper()
+		out := testOutputs(dir)
+		database, err := db.InitDB(out.DBPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		n := notify.New(testPipelineLogger())
+		n.Add(&notify.FileSink{Path: eventsPath}, notify.Options{})
+		p := &pipeline{
+			log:      testPipelineLogger(),
+			store:    database,
+			scrape:   fakeScrape(results...),
+			notifier: n,
+			out:      out,
+			now:      func() time.Time { return now },
+		}
+		if err := p.run(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		now = now.Add(24 * time.Hour)
+	}
+
+	runOnce(listed("nic", posting("a", "Clerk")))
+	if _, err := os.Stat(eventsPath); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("first run must only record a baseline, got %v", err)
+	}
+
+	runOnce(listed("nic", posting("a", "Clerk Grade II"), posting("b", "Driver")))
+
+	data, err := os.ReadFile(eventsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []notify.Event
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		var e notify.Event
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, e)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected 2 events, got %+v", got)
+	}
+	if got[0].Type != notify.EventNewJob || got[0].Job.ID != "b" {
+		t.Errorf("expected new job b first, got %+v", got[0])
+	}
+	if got[1].Type != notify.EventJobChanged || got[1].Job.ID != "a" ||
+		len(got[1].Changes) != 1 || got[1].Changes[0].NewValue != "Clerk Grade II" {
+		t.Errorf("expected title change of a, got %+v", got[1])
+	}
+}
+
+func TestDBJobs_CopiesStructuredDetails(t *testing.T) {
+	j := posting("a", "Junior Engineer")
+	j.Qualifications = []string{"Diploma"}
+	j.MaxAge = 27
+	j.ApplicationMode = models.ApplicationMode_APPLICATION_MODE_BOTH
+	j.Attachments = []*models.Attachment{{Title: "Syllabus", Url: "https://example.gov.in/syllabus.pdf"}}
+	j.Source = "ssc"
+
+	jobs := dbJobs(nil, &models.JobList{Jobs: []*models.JobPosting{j}}, nil, 1000)
+	if len(jobs) != 1 {
+		t.Fatalf("expected 1 job, got %d", len(jobs))
+	}
+	got := jobs[0]
+	if got.MaxAge != 27 || got.ApplicationMode != db.ApplicationBoth || got.Source != "ssc" ||
+		len(got.Qualifications) != 1 || len(got.Attachments) != 1 || got.Attachments[0].Title != "Syllabus" {
+		t.Errorf("unexpected job %+v", got)
+	}
+}
@@ -13,6 +13,11 @@ class DatabaseManager {
   factory DatabaseManager() => _instance;
   DatabaseManager._internal();
 
+  /// Newest `jobs.db` schema version this app understands. Updates are
+  /// skipped only when their `min_reader_version` is newer: added columns
+  /// and tables do not raise it, so installed apps keep syncing.
+  static const int supportedSchemaVersion = 4;
+
   Database? _db;
   final Dio _dio = Dio();
   
@@ -67,20 +72,48 @@ class DatabaseManager {
       
       final String serverChecksum = serverMeta['checksum'];
       final int serverTimestamp = serverMeta['last_updated'];
+      // Metadata written before schema versioning has no min_reader_version.
+      final int minReader = serverMeta['min_reader_version'] ?? 0;
+
+      // The database dropped or changed columns we query — keep the current
+      // one until the app is updated.
+      if (minReader > supportedSchemaVersion) {
+        print("Database needs schema $minReader support, this app has $supportedSchemaVersion. Update the app to sync.");
+        return false;
+      }
 
       // 2. Check Local Metadata (if exists)
       final docsDir = await getApplicationDocumentsDirectory();
       final metaFile = File(join(docsDir.path, 'metadata.json'));
       
+      // Metadata written before delta sync has no version (0).
+      final int serverVersion = serverMeta['version'] ?? 0;
+      int localVersion = 0;
+
       if (await metaFile.exists()) {
         final localMetaJson = await metaFile.readAsString();
         final localMeta = jsonDecode(localMetaJson);
-        if (localMeta['checksum'] == serverChecksum) {
+        localVersion = localMeta['version'] ?? 0;
+        if (localMeta['checksum'] == serverChecksum ||
+            (localVersion > 0 && localVersion == serverVersion)) {
           print("Database is up to date.");
           return false; 
         }
       }
 
+      // 3. Prefer the delta chain when it starts at or before our version.
+      final List deltas = serverMeta['deltas'] ?? [];
+      if (localVersion > 0 && deltas.isNotEmpty && deltas.first['from'] <= localVersion) {
+        try {
+          await _applyDeltas(deltas.where((d) => d['from'] >= localVersion).toList());
+          await metaFile.writeAsString(jsonEncode(serverMeta));
+          print("Applied deltas $localVersion → $serverVersion.");
+          return true;
+        } catch (e) {
+          print("Delta sync failed, downloading full database: $e");
+        }
+      }
+
       print("New database version found ($serverTimestamp). Downloading...");
       await _downloadAndReplaceDB(serverChecksum);
       
@@ -95,6 +128,38 @@ class DatabaseManager {
     }
   }
 
+  /// Downloads each delta in order, verifies its checksum and applies all of
+  /// them in one transaction, so a failure leaves the database untouched.
+  Future<void> _applyDeltas(List deltas) async {
+    final payloads = <Map<String, dynamic>>[];
+    for (final info in deltas) {
+      final response = await _dio.get<List<int>>(
+        '$_repoBaseUrl/${info['file']}',
+        options: Options(responseType: ResponseType.bytes),
+      );
+      final bytes = response.data!;
+      if (sha256.convert(bytes).toString() != info['checksum']) {
+        throw Exception("Checksum mismatch for ${info['file']}");
+      }
+      payloads.add(jsonDecode(utf8.decode(bytes)));
+    }
+
+    final db = await database;
+    await db.transaction((txn) async {
+      for (final delta in payloads) {
+        for (final row in [...delta['added'], ...delta['changed']]) {
+          // Delete before insert: REPLACE would skip the full-text index's
+          // delete trigger.
+          await txn.delete('jobs', where: 'id = ?', whereArgs: [row['id']]);
+          await txn.insert('jobs', Map<String, Object?>.from(row));
+        }
+        for (final id in delta['removed']) {
+          await txn.delete('jobs', where: 'id = ?', whereArgs: [id]);
+        }
+      }
+    });
+  }
+
   /// Downloads the DB to a temp file, verifies checksum, and hot-swaps.
   Future<void> _downloadAndReplaceDB(String expectedChecksum) async {
     final docsDir = await getApplicationDocumentsDirectory();
@@ -138,15 +203,35 @@ class DatabaseManager {
     await _initDatabase();
   }
 
-  /// Search jobs by title or department.
+  /// Ranked full-text search over title, department, location and
+  /// advertisement text, using the `jobs_fts` index shipped in `jobs.db`.
+  /// Falls back to a LIKE scan on databases without the index.
   Future<List<Map<String, dynamic>>> searchJobs(String query) async {
     final db = await database;
+
+    // Quote each word and match it as a prefix — mirrors buildMatchQuery in
+    // pkg/db/search.go so user input is never parsed as FTS5 syntax.
+    final words = query
+        .split(RegExp(r'[^\p{L}\p{N}\p{M}]+', unicode: true))
+        .where((w) => w.isNotEmpty);
+    if (words.isEmpty) return [];
+    final match = words.map((w) => '"$w"*').join(' ');
+
+    try {
+      return await db.rawQuery('''
+        SELECT j.*, snippet(jobs_fts, -1, '[', ']', '…', 12) AS snippet
+        FROM jobs_fts
+        JOIN jobs j ON j.rowid = jobs_fts.rowid
+        WHERE jobs_fts MATCH ? AND j.status = 'active'
+        ORDER BY bm25(jobs_fts, 10.0, 5.0, 2.0, 1.0)
+        LIMIT 100
+      ''', [match]);
+    } catch (e) {
+      // Older database or SQLite built without FTS5.
+      print("Full-text search unavailable, using LIKE: $e");
+    }
+
     final sanitizedQuery = '%$query%';
-    
-    // Ensure table exists (in case we opened a fresh empty DB before first download)
-    // A robust app would have the schema created in _initDatabase via onCreate 
-    // or loaded from asset. Assuming downloaded DB has schema.
-    
     try {
         final results = await db.rawQuery('''
           SELECT * FROM jobs 
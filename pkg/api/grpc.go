@@ -0,0 +1,178 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/entreya/job-aggregation/pkg/db"
+	"github.com/entreya/job-aggregation/pkg/models"
+)
+
+// DefaultWatchInterval is how often WatchJobs looks for new jobs when
+// Config.WatchInterval is unset.
+const DefaultWatchInterval = 30 * time.Second
+
+// watchBatch is the number of new jobs WatchJobs reads per query.
+const watchBatch = 100
+
+// RegisterJobService registers the gRPC JobService (proto/job.proto) on r.
+// It reads the same database snapshots as the HTTP handlers.
+func (s *Server) RegisterJobService(r grpc.ServiceRegistrar) {
+	models.RegisterJobServiceServer(r, &jobService{s: s})
+}
+
+// jobService implements models.JobServiceServer.
+type jobService struct {
+	models.UnimplementedJobServiceServer
+	s *Server
+}
+
+// ListJobs pages through jobs with the filters of GET /jobs.
+func (g *jobService) ListJobs(ctx context.Context, req *models.ListJobsRequest) (*models.ListJobsResponse, error) {
+	f, err := newFilter(req.GetQuery(), req.GetDepartment(), req.GetLocation(), req.GetStatus(),
+		req.GetPostedFrom(), req.GetPostedTo())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	f.DepartmentID, f.LocationID = req.GetDepartmentId(), req.GetLocationId()
+	if f.Limit, err = pageLimit(req.GetLimit()); err != nil {
+		return nil, err
+	}
+	if req.GetOffset() < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid offset %d", req.GetOffset())
+	}
+	f.Offset = int(req.GetOffset())
+
+	snap, release := g.s.acquire()
+	defer release()
+	jobs, total, err := snap.db.ListJobs(f)
+	if err != nil {
+		return nil, g.internal(err)
+	}
+
+	resp := &models.ListJobsResponse{Jobs: make([]*models.JobPosting, 0, len(jobs)), Total: int32(total)}
+	for _, j := range jobs {
+		resp.Jobs = append(resp.Jobs, ToProto(j))
+	}
+	if next := f.Offset + len(jobs); next < total {
+		resp.NextOffset = int32(next)
+	}
+	return resp, nil
+}
+
+// GetJob returns one job, or NotFound.
+func (g *jobService) GetJob(ctx context.Context, req *models.GetJobRequest) (*models.JobPosting, error) {
+	snap, release := g.s.acquire()
+	defer release()
+	job, err := snap.db.GetJob(req.GetId())
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, status.Error(codes.NotFound, "job not found")
+	}
+	if err != nil {
+		return nil, g.internal(err)
+	}
+	return ToProto(job), nil
+}
+
+// SearchJobs runs a ranked full-text search.
+func (g *jobService) SearchJobs(ctx context.Context, req *models.SearchJobsRequest) (*models.SearchJobsResponse, error) {
+	limit, err := pageLimit(req.GetLimit())
+	if err != nil {
+		return nil, err
+	}
+	if req.GetOffset() < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid offset %d", req.GetOffset())
+	}
+
+	snap, release := g.s.acquire()
+	defer release()
+	results, err := snap.db.Search(req.GetQuery(), db.SearchOptions{
+		Limit:           limit,
+		Offset:          int(req.GetOffset()),
+		IncludeInactive: req.GetIncludeInactive(),
+	})
+	if err != nil {
+		return nil, g.internal(err)
+	}
+
+	resp := &models.SearchJobsResponse{Results: make([]*models.SearchResult, 0, len(results))}
+	for _, r := range results {
+		resp.Results = append(resp.Results, &models.SearchResult{Job: ToProto(r.Job), Rank: r.Rank, Snippet: r.Snippet})
+	}
+	return resp, nil
+}
+
+// WatchJobs streams active, non-duplicate jobs as scrapes add them,
+// checking the database every Config.WatchInterval until the client
+// cancels.
+func (g *jobService) WatchJobs(req *models.WatchJobsRequest, stream grpc.ServerStreamingServer[models.JobPosting]) error {
+	f := db.NewJobsFilter{
+		SeenAfter:    req.GetSince(),
+		AfterID:      req.GetAfterId(),
+		Department:   req.GetDepartment(),
+		DepartmentID: req.GetDepartmentId(),
+		Location:     req.GetLocation(),
+		LocationID:   req.GetLocationId(),
+		Limit:        watchBatch,
+	}
+	if f.SeenAfter == 0 {
+		f.SeenAfter, f.AfterID = time.Now().Unix(), ""
+	}
+
+	interval := g.s.cfg.WatchInterval
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		for {
+			snap, release := g.s.acquire()
+			jobs, err := snap.db.NewJobs(f)
+			release()
+			if err != nil {
+				return g.internal(err)
+			}
+			for _, j := range jobs {
+				if err := stream.Send(ToProto(j)); err != nil {
+					return err
+				}
+				f.SeenAfter, f.AfterID = j.FirstSeenAt, j.ID
+			}
+			if len(jobs) < f.Limit {
+				break
+			}
+		}
+
+		select {
+		case <-stream.Context().Done():
+			return status.FromContextError(stream.Context().Err()).Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// pageLimit validates a requested page size; 0 means DefaultLimit.
+func pageLimit(limit int32) (int, error) {
+	switch {
+	case limit == 0:
+		return DefaultLimit, nil
+	case limit < 0 || limit > MaxLimit:
+		return 0, status.Errorf(codes.InvalidArgument, "invalid limit %d: want 1 to %d", limit, MaxLimit)
+	}
+	return int(limit), nil
+}
+
+// internal logs err and returns an Internal status without exposing it.
+func (g *jobService) internal(err error) error {
+	g.s.log.Error("rpc failed", slog.String("error", err.Error()))
+	return status.Error(codes.Internal, "internal error")
+}
@@ -0,0 +1,186 @@
+package api
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/entreya/job-aggregation/pkg/db"
+	"github.com/entreya/job-aggregation/pkg/models"
+)
+
+// testGRPC serves the JobService over an in-memory connection and returns
+// a client for it and the path of its jobs.db.
+func testGRPC(t *testing.T) (models.JobServiceClient, string) {
+	t.Helper()
+	dbPath, metaPath := seedDB(t)
+	srv, err := New(Config{DBPath: dbPath, MetadataPath: metaPath, Logger: testLogger(),
+		WatchInterval: 10 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lis := bufconn.Listen(1 << 20)
+	grpcServer := grpc.NewServer()
+	srv.RegisterJobService(grpcServer)
+	go grpcServer.Serve(lis)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		conn.Close()
+		grpcServer.Stop()
+		srv.Close()
+	})
+	return models.NewJobServiceClient(conn), dbPath
+}
+
+func postingIDs(jobs []*models.JobPosting) []string {
+	ids := make([]string, 0, len(jobs))
+	for _, j := range jobs {
+		ids = append(ids, j.GetId())
+	}
+	return ids
+}
+
+func TestGRPC_ListJobs(t *testing.T) {
+	client, _ := testGRPC(t)
+	ctx := context.Background()
+
+	resp, err := client.ListJobs(ctx, &models.ListJobsRequest{Location: "mumbai", Limit: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := postingIDs(resp.GetJobs()); len(got) != 1 || got[0] != "ae" {
+		t.Errorf("unexpected jobs %v", got)
+	}
+	if resp.GetTotal() != 2 || resp.GetNextOffset() != 1 {
+		t.Errorf("unexpected paging: total %d, next offset %d", resp.GetTotal(), resp.GetNextOffset())
+	}
+
+	resp, err = client.ListJobs(ctx, &models.ListJobsRequest{Query: "engineer", PostedFrom: "2026-03-02"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := postingIDs(resp.GetJobs()); len(got) != 1 || got[0] != "ae" || resp.GetNextOffset() != 0 {
+		t.Errorf("unexpected jobs %v, next offset %d", got, resp.GetNextOffset())
+	}
+
+	resp, err = client.ListJobs(ctx, &models.ListJobsRequest{DepartmentId: "mopgp", LocationId: "in/dl"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := postingIDs(resp.GetJobs()); len(got) != 1 || got[0] != "je" || resp.GetJobs()[0].GetLocationId() != "in/dl/new-delhi" {
+		t.Errorf("unexpected jobs %v", resp.GetJobs())
+	}
+
+	for _, req := range []*models.ListJobsRequest{
+		{Status: "pending"},
+		{PostedTo: "01/03/2026"},
+		{Limit: MaxLimit + 1},
+		{Offset: -1},
+	} {
+		if _, err := client.ListJobs(ctx, req); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("%v: expected InvalidArgument, got %v", req, err)
+		}
+	}
+}
+
+func TestGRPC_GetJob(t *testing.T) {
+	client, _ := testGRPC(t)
+
+	job, err := client.GetJob(context.Background(), &models.GetJobRequest{Id: "je"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if job.GetTitle() != "Junior Engineer" || job.GetDate() != "2026-03-01" || job.GetVacancies() != 12 ||
+		job.GetFirstSeenAt() == 0 {
+		t.Errorf("unexpected job %v", job)
+	}
+	if job.GetClosingAt().AsTime().Format("2006-01-02") != "2026-03-31" || job.GetMaxAge() != 27 ||
+		job.GetApplicationMode() != models.ApplicationMode_APPLICATION_MODE_ONLINE || len(job.GetAttachments()) != 1 {
+		t.Errorf("structured details not converted: %v", job)
+	}
+
+	_, err = client.GetJob(context.Background(), &models.GetJobRequest{Id: "missing"})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("expected NotFound, got %v", err)
+	}
+}
+
+func TestGRPC_SearchJobs(t *testing.T) {
+	client, _ := testGRPC(t)
+
+	resp, err := client.SearchJobs(context.Background(), &models.SearchJobsRequest{Query: "civil"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.GetResults()) != 1 {
+		t.Fatalf("expected one result, got %v", resp.GetResults())
+	}
+	r := resp.GetResults()[0]
+	if r.GetJob().GetId() != "je" || r.GetSnippet() == "" {
+		t.Errorf("unexpected result %v", r)
+	}
+}
+
+func TestGRPC_WatchJobsStreamsNewJobs(t *testing.T) {
+	client, dbPath := testGRPC(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream, err := client.WatchJobs(ctx, &models.WatchJobsRequest{Since: 1, Location: "mumbai"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	recv := func() *models.JobPosting {
+		t.Helper()
+		job, err := stream.Recv()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return job
+	}
+
+	// Jobs already stored, oldest first and by ID within a run.
+	if a, b := recv(), recv(); a.GetId() != "ae" || b.GetId() != "clerk" {
+		t.Fatalf("unexpected backlog %s, %s", a.GetId(), b.GetId())
+	}
+
+	// A later scrape adds jobs while the stream is open.
+	writer, err := db.InitDB(dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	later := time.Now().Unix() + 60
+	for _, j := range []db.Job{
+		{ID: "steno", Title: "Stenographer", Location: "Mumbai", LastSeenAt: later},
+		{ID: "driver", Title: "Driver", Location: "Pune", LastSeenAt: later},
+	} {
+		if _, err := writer.UpsertJob(j); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writer.Close()
+
+	if job := recv(); job.GetId() != "steno" || job.GetFirstSeenAt() != later {
+		t.Errorf("unexpected job %v", job)
+	}
+
+	cancel()
+	if _, err := stream.Recv(); status.Code(err) != codes.Canceled {
+		t.Errorf("expected Canceled after cancelling, got %v", err)
+	}
+}
@@ -0,0 +1,383 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/entreya/job-aggregation/pkg/db"
+	"github.com/entreya/job-aggregation/pkg/models"
+)
+
+// Paging limits for /jobs.
+const (
+	DefaultLimit = 50
+	MaxLimit     = 200
+)
+
+// Media types served by the API.
+const (
+	contentTypeJSON     = "application/json"
+	contentTypeProtobuf = "application/x-protobuf"
+)
+
+// Job is the JSON representation of a job.
+type Job struct {
+	ID           string `json:"id"`
+	Title        string `json:"title"`
+	Department   string `json:"department"`
+	DepartmentID string `json:"department_id,omitempty"` // pkg/entity ID, e.g. "meity/nic"
+	Location     string `json:"location"`
+	LocationID   string `json:"location_id,omitempty"` // pkg/entity ID, e.g. "in/br/patna"
+	URL          string `json:"url"`
+	PostedDate   string `json:"posted_date,omitempty"`  // YYYY-MM-DD
+	ClosingDate  string `json:"closing_date,omitempty"` // YYYY-MM-DD
+	// ClosingDateConfidence is "high", "medium" or "low" for an inferred
+	// closing date.
+	ClosingDateConfidence string          `json:"closing_date_confidence,omitempty"`
+	AdvertisementNo       string          `json:"advertisement_no,omitempty"`
+	Vacancies             int             `json:"vacancies,omitempty"`
+	Qualifications        []string        `json:"qualifications,omitempty"`
+	PayLevel              string          `json:"pay_level,omitempty"`
+	PayScale              string          `json:"pay_scale,omitempty"`
+	AgeLimit              string          `json:"age_limit,omitempty"`
+	MinAge                int             `json:"min_age,omitempty"`
+	MaxAge                int             `json:"max_age,omitempty"`
+	ApplicationMode       string          `json:"application_mode,omitempty"` // online, offline or both
+	Attachments           []db.Attachment `json:"attachments,omitempty"`
+	Source                string          `json:"source,omitempty"`
+	Status                string          `json:"status"`
+	FirstSeenAt           int64           `json:"first_seen_at"`
+	LastSeenAt            int64           `json:"last_seen_at"`
+	CanonicalID           string          `json:"canonical_id,omitempty"`
+	AdvertText            string          `json:"advert_text,omitempty"` // Only in /jobs/{id}
+}
+
+// JobPage is the JSON response of /jobs.
+type JobPage struct {
+	Jobs       []Job `json:"jobs"`
+	Total      int   `json:"total"`
+	Limit      int   `json:"limit"`
+	Offset     int   `json:"offset"`
+	NextOffset *int  `json:"next_offset,omitempty"` // Absent on the last page
+}
+
+func jobFromDB(j db.Job) Job {
+	return Job{
+		ID:                    j.ID,
+		Title:                 j.Title,
+		Department:            j.Department,
+		DepartmentID:          j.DepartmentID,
+		Location:              j.Location,
+		LocationID:            j.LocationID,
+		URL:                   j.URL,
+		PostedDate:            formatDate(j.PostedDate),
+		ClosingDate:           j.ClosingDate,
+		ClosingDateConfidence: j.ClosingDateConfidence,
+		AdvertisementNo:       j.AdvertisementNo,
+		Vacancies:             j.Vacancies,
+		Qualifications:        j.Qualifications,
+		PayLevel:              j.PayLevel,
+		PayScale:              j.PayScale,
+		AgeLimit:              j.AgeLimit,
+		MinAge:                j.MinAge,
+		MaxAge:                j.MaxAge,
+		ApplicationMode:       j.ApplicationMode,
+		Attachments:           j.Attachments,
+		Source:                j.Source,
+		Status:                j.Status,
+		FirstSeenAt:           j.FirstSeenAt,
+		LastSeenAt:            j.LastSeenAt,
+		CanonicalID:           j.CanonicalID,
+	}
+}
+
+// ToProto converts a stored job to the published JobPosting message.
+func ToProto(j db.Job) *models.JobPosting {
+	p := &models.JobPosting{
+		Id:                 j.ID,
+		Title:              j.Title,
+		Department:         j.Department,
+		DepartmentId:       j.DepartmentID,
+		Location:           j.Location,
+		LocationId:         j.LocationID,
+		Url:                j.URL,
+		Date:               formatDate(j.PostedDate),
+		LastDate:           j.ClosingDate,
+		LastDateConfidence: j.ClosingDateConfidence,
+		AdvertisementNo:    j.AdvertisementNo,
+		Vacancies:          int32(j.Vacancies),
+		PayLevel:           j.PayLevel,
+		AgeLimit:           j.AgeLimit,
+		FirstSeenAt:        j.FirstSeenAt,
+		Qualifications:     j.Qualifications,
+		PayScale:           j.PayScale,
+		MinAge:             int32(j.MinAge),
+		MaxAge:             int32(j.MaxAge),
+		ApplicationMode:    applicationModes[j.ApplicationMode],
+		Source:             j.Source,
+	}
+	if j.PostedDate != 0 {
+		p.PostedAt = timestamppb.New(time.Unix(j.PostedDate, 0))
+	}
+	if t, err := time.Parse("2006-01-02", j.ClosingDate); err == nil {
+		p.ClosingAt = timestamppb.New(t)
+	}
+	for _, a := range j.Attachments {
+		p.Attachments = append(p.Attachments, &models.Attachment{Title: a.Title, Url: a.URL})
+	}
+	return p
+}
+
+// applicationModes maps jobs.db application modes to their enum values;
+// unknown modes map to UNSPECIFIED.
+var applicationModes = map[string]models.ApplicationMode{
+	db.ApplicationOnline:  models.ApplicationMode_APPLICATION_MODE_ONLINE,
+	db.ApplicationOffline: models.ApplicationMode_APPLICATION_MODE_OFFLINE,
+	db.ApplicationBoth:    models.ApplicationMode_APPLICATION_MODE_BOTH,
+}
+
+// formatDate renders a Unix posted_date as YYYY-MM-DD, or "" when unknown.
+func formatDate(unix int64) string {
+	if unix == 0 {
+		return ""
+	}
+	return time.Unix(unix, 0).UTC().Format("2006-01-02")
+}
+
+// handleListJobs serves GET /jobs.
+//
+// Query parameters: q (full-text search), department and location
+// (substring matches), status (active by default, or expired, removed or
+// all), from and to (posting date range, YYYY-MM-DD, inclusive), limit
+// and offset.
+func (s *Server) handleListJobs(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseFilter(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	snap, release := s.acquire()
+	defer release()
+	format := negotiate(r)
+	if notModified(w, r, snap, format) {
+		return
+	}
+
+	jobs, total, err := snap.db.ListJobs(filter)
+	if err != nil {
+		s.serverError(w, err)
+		return
+	}
+	w.Header().Set("X-Total-Count", strconv.Itoa(total))
+
+	if format == contentTypeProtobuf {
+		list := &models.JobList{Jobs: make([]*models.JobPosting, 0, len(jobs)), LastUpdated: snap.lastUpdated}
+		for _, j := range jobs {
+			list.Jobs = append(list.Jobs, ToProto(j))
+		}
+		s.writeProto(w, list)
+		return
+	}
+
+	page := JobPage{Jobs: make([]Job, 0, len(jobs)), Total: total, Limit: filter.Limit, Offset: filter.Offset}
+	for _, j := range jobs {
+		page.Jobs = append(page.Jobs, jobFromDB(j))
+	}
+	if next := filter.Offset + len(jobs); next < total {
+		page.NextOffset = &next
+	}
+	writeJSON(w, http.StatusOK, page)
+}
+
+// handleGetJob serves GET /jobs/{id}.
+func (s *Server) handleGetJob(w http.ResponseWriter, r *http.Request) {
+	snap, release := s.acquire()
+	defer release()
+	format := negotiate(r)
+	if notModified(w, r, snap, format) {
+		return
+	}
+
+	job, err := snap.db.GetJob(r.PathValue("id"))
+	if errors.Is(err, sql.ErrNoRows) {
+		writeError(w, http.StatusNotFound, "job not found")
+		return
+	}
+	if err != nil {
+		s.serverError(w, err)
+		return
+	}
+
+	if format == contentTypeProtobuf {
+		s.writeProto(w, ToProto(job))
+		return
+	}
+	out := jobFromDB(job)
+	out.AdvertText = job.AdvertText
+	writeJSON(w, http.StatusOK, out)
+}
+
+// handleHealth serves GET /healthz.
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	snap, release := s.acquire()
+	defer release()
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok", "checksum": snap.checksum})
+}
+
+// parseFilter reads /jobs query parameters.
+func parseFilter(r *http.Request) (db.JobFilter, error) {
+	q := r.URL.Query()
+	f, err := newFilter(q.Get("q"), q.Get("department"), q.Get("location"), q.Get("status"), q.Get("from"), q.Get("to"))
+	if err != nil {
+		return f, err
+	}
+	f.DepartmentID, f.LocationID = q.Get("department_id"), q.Get("location_id")
+
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > MaxLimit {
+			return f, fmt.Errorf("invalid limit %q: want 1 to %d", v, MaxLimit)
+		}
+		f.Limit = n
+	}
+	if v := q.Get("offset"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return f, fmt.Errorf("invalid offset %q", v)
+		}
+		f.Offset = n
+	}
+	return f, nil
+}
+
+// newFilter builds a filter with the default limit from the listing
+// parameters shared by /jobs and the gRPC ListJobs: status defaults to
+// active, and from and to are inclusive YYYY-MM-DD posting dates.
+func newFilter(query, department, location, status, from, to string) (db.JobFilter, error) {
+	f := db.JobFilter{
+		Query:      query,
+		Department: department,
+		Location:   location,
+		Limit:      DefaultLimit,
+	}
+
+	switch status {
+	case "":
+		f.Status = db.StatusActive
+	case "all":
+	case db.StatusActive, db.StatusExpired, db.StatusRemoved:
+		f.Status = status
+	default:
+		return f, fmt.Errorf("invalid status %q: want active, expired, removed or all", status)
+	}
+
+	if from != "" {
+		t, err := time.Parse("2006-01-02", from)
+		if err != nil {
+			return f, fmt.Errorf("invalid from date %q: want YYYY-MM-DD", from)
+		}
+		f.PostedFrom = t.Unix()
+	}
+	if to != "" {
+		t, err := time.Parse("2006-01-02", to)
+		if err != nil {
+			return f, fmt.Errorf("invalid to date %q: want YYYY-MM-DD", to)
+		}
+		// Inclusive: up to the last second of the day.
+		f.PostedTo = t.AddDate(0, 0, 1).Unix() - 1
+	}
+	return f, nil
+}
+
+// negotiate picks JSON or protobuf from the Accept header, honouring
+// q-values. JSON is the default.
+func negotiate(r *http.Request) string {
+	best, bestQ := contentTypeJSON, 0.0
+	for _, part := range strings.Split(r.Header.Get("Accept"), ",") {
+		fields := strings.Split(part, ";")
+		mediaType := strings.ToLower(strings.TrimSpace(fields[0]))
+		q := 1.0
+		for _, param := range fields[1:] {
+			if v, ok := strings.CutPrefix(strings.TrimSpace(param), "q="); ok {
+				if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+
+		var candidate string
+		switch mediaType {
+		case "application/x-protobuf", "application/protobuf", "application/vnd.google.protobuf":
+			candidate = contentTypeProtobuf
+		case "application/json", "application/*", "*/*":
+			candidate = contentTypeJSON
+		default:
+			continue
+		}
+		if q > bestQ {
+			best, bestQ = candidate, q
+		}
+	}
+	return best
+}
+
+// notModified sets the ETag and Vary headers and, if the client's
+// If-None-Match matches the current ETag, answers 304 and returns true.
+func notModified(w http.ResponseWriter, r *http.Request, snap *snapshot, format string) bool {
+	w.Header().Set("Vary", "Accept")
+	if snap.checksum == "" {
+		return false
+	}
+	// The two representations of a resource need distinct strong ETags.
+	etag := `"` + snap.checksum + `"`
+	if format == contentTypeProtobuf {
+		etag = `"` + snap.checksum + `-pb"`
+	}
+	w.Header().Set("ETag", etag)
+
+	for _, candidate := range strings.Split(r.Header.Get("If-None-Match"), ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == etag || candidate == "*" {
+			w.WriteHeader(http.StatusNotModified)
+			return true
+		}
+	}
+	return false
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", contentTypeJSON)
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
+
+func (s *Server) writeProto(w http.ResponseWriter, m proto.Message) {
+	data, err := proto.Marshal(m)
+	if err != nil {
+		s.serverError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", contentTypeProtobuf)
+	w.Write(data)
+}
+
+// serverError logs err and answers 500 without exposing it.
+func (s *Server) serverError(w http.ResponseWriter, err error) {
+	s.log.Error("request failed", slog.String("error", err.Error()))
+	writeError(w, http.StatusInternalServerError, "internal error")
+}
@@ -0,0 +1,179 @@
+// Package api serves jobs.db over a read-only HTTP API: a paginated,
+// filterable /jobs listing with full-text search and /jobs/{id}. Responses
+// are JSON, or protobuf (models.JobList / models.JobPosting) when the client
+// asks for it in Accept.
+//
+// The same data is available to internal consumers over gRPC as the
+// JobService of proto/job.proto (see RegisterJobService), whose WatchJobs
+// streams new postings as scrapes add them.
+//
+// Every HTTP response carries an ETag derived from the checksum in the
+// scraper's metadata.json, so clients can revalidate with If-None-Match and
+// get 304 Not Modified until the next scrape publishes new data.
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/entreya/job-aggregation/pkg/db"
+)
+
+// Config configures a Server.
+type Config struct {
+	DBPath       string // jobs.db, opened read-only
+	MetadataPath string // metadata.json written by the scraper; optional
+	Logger       *slog.Logger
+
+	// WatchInterval is how often gRPC WatchJobs streams check for new jobs
+	// (default DefaultWatchInterval).
+	WatchInterval time.Duration
+}
+
+// Server answers API requests from a read-only connection to the jobs
+// database. When the scraper publishes a new metadata.json the database is
+// reopened, which also picks up a jobs.db replaced by a git pull.
+type Server struct {
+	cfg Config
+	log *slog.Logger
+
+	// mu is held for reading while a request uses snap, and for writing
+	// while snap is replaced.
+	mu   sync.RWMutex
+	snap *snapshot
+
+	metaMu   sync.Mutex
+	metaStat metaStat
+}
+
+// snapshot is one published version of the database.
+type snapshot struct {
+	db          *db.DB
+	checksum    string // metadata.json checksum; "" when unknown
+	lastUpdated int64  // metadata.json last_updated, Unix seconds
+}
+
+// metaStat identifies a version of metadata.json cheaply.
+type metaStat struct {
+	modTime time.Time
+	size    int64
+}
+
+// New opens the database and metadata named in cfg.
+func New(cfg Config) (*Server, error) {
+	s := &Server{cfg: cfg, log: cfg.Logger}
+	if s.log == nil {
+		s.log = slog.Default()
+	}
+	stat, _ := s.statMetadata()
+	snap, err := s.open()
+	if err != nil {
+		return nil, err
+	}
+	s.snap, s.metaStat = snap, stat
+	return s, nil
+}
+
+// Close closes the database.
+func (s *Server) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.snap.db.Close()
+}
+
+// Handler returns the API's routes.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /jobs", s.handleListJobs)
+	mux.HandleFunc("GET /jobs/{id}", s.handleGetJob)
+	mux.HandleFunc("GET /healthz", s.handleHealth)
+	return mux
+}
+
+// acquire returns the current snapshot, reopening the database first if
+// metadata.json has changed. Call the returned release when done with it.
+func (s *Server) acquire() (*snapshot, func()) {
+	s.refresh()
+	s.mu.RLock()
+	return s.snap, s.mu.RUnlock
+}
+
+// refresh reopens the database if metadata.json changed since it was last
+// read. A failed reopen keeps serving the previous snapshot.
+func (s *Server) refresh() {
+	stat, ok := s.statMetadata()
+	if !ok {
+		return
+	}
+
+	s.metaMu.Lock()
+	defer s.metaMu.Unlock()
+	if stat == s.metaStat {
+		return
+	}
+
+	snap, err := s.open()
+	if err != nil {
+		s.log.Warn("failed to reload database; serving the previous version",
+			slog.String("error", err.Error()),
+		)
+		return
+	}
+	s.metaStat = stat
+
+	s.mu.Lock()
+	old := s.snap
+	s.snap = snap
+	s.mu.Unlock()
+	old.db.Close()
+
+	s.log.Info("database reloaded", slog.String("checksum", snap.checksum))
+}
+
+func (s *Server) statMetadata() (metaStat, bool) {
+	if s.cfg.MetadataPath == "" {
+		return metaStat{}, false
+	}
+	info, err := os.Stat(s.cfg.MetadataPath)
+	if err != nil {
+		return metaStat{}, false
+	}
+	return metaStat{modTime: info.ModTime(), size: info.Size()}, true
+}
+
+// open opens the database and reads the metadata describing it.
+func (s *Server) open() (*snapshot, error) {
+	snap := &snapshot{}
+	if s.cfg.MetadataPath != "" {
+		data, err := os.ReadFile(s.cfg.MetadataPath)
+		switch {
+		case err == nil:
+			var meta struct {
+				LastUpdated int64  `json:"last_updated"`
+				Checksum    string `json:"checksum"`
+			}
+			if err := json.Unmarshal(data, &meta); err != nil {
+				return nil, fmt.Errorf("failed to parse %s: %w", s.cfg.MetadataPath, err)
+			}
+			snap.checksum, snap.lastUpdated = meta.Checksum, meta.LastUpdated
+		case os.IsNotExist(err):
+			s.log.Warn("metadata not found; responses carry no ETag",
+				slog.String("metadata", s.cfg.MetadataPath),
+			)
+		default:
+			return nil, fmt.Errorf("failed to read %s: %w", s.cfg.MetadataPath, err)
+		}
+	}
+
+	database, err := db.OpenReadOnly(s.cfg.DBPath)
+	if err != nil {
+		return nil, err
+	}
+	snap.db = database
+	return snap, nil
+}
@@ -0,0 +1,256 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/entreya/job-aggregation/pkg/db"
+	"github.com/entreya/job-aggregation/pkg/models"
+)
+
+func testLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+}
+
+func writeMetadata(t *testing.T, path, checksum string) {
+	t.Helper()
+	data := fmt.Sprintf(`{"last_updated": 1772300000, "checksum": %q, "job_count": 3, "schema_version": %d}`, checksum, db.SchemaVersion)
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// seedDB writes a jobs.db with three jobs and a metadata.json describing it.
+func seedDB(t *testing.T) (dbPath, metaPath string) {
+	t.Helper()
+	dir := t.TempDir()
+	dbPath = filepath.Join(dir, "jobs.db")
+	metaPath = filepath.Join(dir, "metadata.json")
+
+	database, err := db.InitDB(dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	posted := func(date string) int64 {
+		ts, _ := time.Parse("2006-01-02", date)
+		return ts.Unix()
+	}
+	for _, j := range []db.Job{
+		{ID: "je", Title: "Junior Engineer", Department: "Staff Selection Commission", DepartmentID: "mopgp/dopt/ssc",
+			Location: "New Delhi", LocationID: "in/dl/new-delhi",
+			PostedDate: posted("2026-03-01"), ClosingDate: "2026-03-31", Vacancies: 12, AdvertText: "Diploma in Civil Engineering",
+			MaxAge: 27, ApplicationMode: db.ApplicationOnline, Attachments: []db.Attachment{{Title: "Syllabus", URL: "https://ssc.gov.in/syllabus.pdf"}}},
+		{ID: "ae", Title: "Assistant Engineer", Department: "CPWD", Location: "Mumbai", LocationID: "in/mh/mumbai", PostedDate: posted("2026-03-05")},
+		{ID: "clerk", Title: "Clerk", Department: "Staff Selection Commission", DepartmentID: "mopgp/dopt/ssc",
+			Location: "Mumbai", LocationID: "in/mh/mumbai", PostedDate: posted("2026-02-10")},
+	} {
+		if _, err := database.UpsertJob(j); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := database.Close(); err != nil {
+		t.Fatal(err)
+	}
+	writeMetadata(t, metaPath, "abc123")
+	return dbPath, metaPath
+}
+
+// testServer seeds a jobs.db and metadata.json and serves them.
+func testServer(t *testing.T) (*httptest.Server, string) {
+	t.Helper()
+	dbPath, metaPath := seedDB(t)
+	srv, err := New(Config{DBPath: dbPath, MetadataPath: metaPath, Logger: testLogger()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(srv.Handler())
+	t.Cleanup(func() {
+		ts.Close()
+		srv.Close()
+	})
+	return ts, metaPath
+}
+
+func get(t *testing.T, url string, header map[string]string) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+func decodePage(t *testing.T, resp *http.Response) JobPage {
+	t.Helper()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		t.Fatalf("status %d: %s", resp.StatusCode, body)
+	}
+	var page JobPage
+	if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+		t.Fatal(err)
+	}
+	return page
+}
+
+func pageIDs(page JobPage) []string {
+	ids := make([]string, 0, len(page.Jobs))
+	for _, j := range page.Jobs {
+		ids = append(ids, j.ID)
+	}
+	return ids
+}
+
+func TestListJobs_PaginatesNewestFirst(t *testing.T) {
+	ts, _ := testServer(t)
+
+	page := decodePage(t, get(t, ts.URL+"/jobs?limit=2", nil))
+	if got := pageIDs(page); len(got) != 2 || got[0] != "ae" || got[1] != "je" {
+		t.Errorf("unexpected first page %v", got)
+	}
+	if page.Total != 3 || page.NextOffset == nil || *page.NextOffset != 2 {
+		t.Errorf("unexpected paging %+v", page)
+	}
+
+	page = decodePage(t, get(t, ts.URL+"/jobs?limit=2&offset=2", nil))
+	if got := pageIDs(page); len(got) != 1 || got[0] != "clerk" || page.NextOffset != nil {
+		t.Errorf("unexpected last page %v, next %v", got, page.NextOffset)
+	}
+}
+
+func TestListJobs_Filters(t *testing.T) {
+	ts, _ := testServer(t)
+
+	tests := []struct {
+		query string
+		want  int
+	}{
+		{"department=selection", 2},
+		{"location=mumbai", 2},
+		{"department_id=mopgp", 2},
+		{"location_id=in/mh", 2},
+		{"location_id=in&department_id=mopgp/dopt/ssc", 2},
+		{"from=2026-03-01&to=2026-03-01", 1},
+		{"from=2026-03-01", 2},
+		{"q=engineer", 2},
+		{"q=engineer&location=delhi", 1},
+		{"q=civil", 1}, // advertisement text
+		{"status=removed", 0},
+		{"status=all", 3},
+	}
+	for _, tt := range tests {
+		page := decodePage(t, get(t, ts.URL+"/jobs?"+tt.query, nil))
+		if len(page.Jobs) != tt.want || page.Total != tt.want {
+			t.Errorf("%s: got %v (total %d), want %d", tt.query, pageIDs(page), page.Total, tt.want)
+		}
+	}
+}
+
+func TestListJobs_RejectsBadParameters(t *testing.T) {
+	ts, _ := testServer(t)
+
+	for _, query := range []string{"limit=0", "limit=1000", "offset=-1", "from=01-03-2026", "status=open"} {
+		if resp := get(t, ts.URL+"/jobs?"+query, nil); resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", query, resp.StatusCode)
+		}
+	}
+}
+
+func TestGetJob(t *testing.T) {
+	ts, _ := testServer(t)
+
+	resp := get(t, ts.URL+"/jobs/je", nil)
+	var job Job
+	if err := json.NewDecoder(resp.Body).Decode(&job); err != nil {
+		t.Fatal(err)
+	}
+	if job.Title != "Junior Engineer" || job.PostedDate != "2026-03-01" || job.ClosingDate != "2026-03-31" ||
+		job.Vacancies != 12 || job.AdvertText == "" || job.Status != db.StatusActive {
+		t.Errorf("unexpected job %+v", job)
+	}
+
+	if resp := get(t, ts.URL+"/jobs/missing", nil); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected 404, got %d", resp.StatusCode)
+	}
+}
+
+func TestETag_NotModifiedUntilMetadataChanges(t *testing.T) {
+	ts, metaPath := testServer(t)
+
+	resp := get(t, ts.URL+"/jobs", nil)
+	etag := resp.Header.Get("ETag")
+	if etag != `"abc123"` {
+		t.Fatalf("expected ETag from the metadata checksum, got %q", etag)
+	}
+
+	resp = get(t, ts.URL+"/jobs", map[string]string{"If-None-Match": etag})
+	if resp.StatusCode != http.StatusNotModified {
+		t.Errorf("expected 304, got %d", resp.StatusCode)
+	}
+
+	// A new scrape publishes a new checksum. Bump the mtime explicitly in
+	// case the filesystem's resolution hides the rewrite.
+	writeMetadata(t, metaPath, "def456")
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(metaPath, later, later); err != nil {
+		t.Fatal(err)
+	}
+
+	resp = get(t, ts.URL+"/jobs", map[string]string{"If-None-Match": etag})
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("ETag") != `"def456"` {
+		t.Errorf("expected 200 with the new ETag, got %d %q", resp.StatusCode, resp.Header.Get("ETag"))
+	}
+}
+
+func TestNegotiatesProtobuf(t *testing.T) {
+	ts, _ := testServer(t)
+
+	resp := get(t, ts.URL+"/jobs?location=mumbai", map[string]string{"Accept": "application/json;q=0.5, application/x-protobuf"})
+	if ct := resp.Header.Get("Content-Type"); ct != "application/x-protobuf" {
+		t.Fatalf("expected protobuf, got %q", ct)
+	}
+	if etag := resp.Header.Get("ETag"); etag != `"abc123-pb"` {
+		t.Errorf("expected a protobuf-specific ETag, got %q", etag)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var list models.JobList
+	if err := proto.Unmarshal(data, &list); err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Jobs) != 2 || list.Jobs[0].Id != "ae" || list.Jobs[0].Date != "2026-03-05" || list.LastUpdated != 1772300000 {
+		t.Errorf("unexpected list %v", &list)
+	}
+
+	resp = get(t, ts.URL+"/jobs/je", map[string]string{"Accept": "application/protobuf"})
+	data, _ = io.ReadAll(resp.Body)
+	var job models.JobPosting
+	if err := proto.Unmarshal(data, &job); err != nil || job.LastDate != "2026-03-31" {
+		t.Errorf("unexpected job %v: %v", &job, err)
+	}
+
+	resp = get(t, ts.URL+"/jobs", map[string]string{"Accept": "application/x-protobuf;q=0.1, */*"})
+	if ct := resp.Header.Get("Content-Type"); ct != "application/json" {
+		t.Errorf("expected JSON to win on q-value, got %q", ct)
+	}
+}
@@ -0,0 +1,336 @@
+// Package dates reads the dates Indian recruitment boards write —
+// "15.03.2026", "15/03/26", "15th March, 2026", "15-Mar-26", "March 15,
+// 2026", "15 मार्च 2026" — and deadlines such as "within 30 days of
+// publication in Employment News", and normalises them to midnight UTC with
+// a confidence level.
+//
+// Numeric dates are read day first, as Indian boards write them. It has no
+// dependencies on the rest of the module.
+package dates
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Confidence is how sure a Result is of the date it read.
+type Confidence int
+
+const (
+	// None means no date was read.
+	None Confidence = iota
+	// Low means the date was inferred: a relative deadline resolved against a
+	// reference date, or a day and month whose year was taken from it.
+	Low
+	// Medium means the date was written with a two-digit year.
+	Medium
+	// High means the date was written in full.
+	High
+)
+
+func (c Confidence) String() string {
+	switch c {
+	case Low:
+		return "low"
+	case Medium:
+		return "medium"
+	case High:
+		return "high"
+	}
+	return "none"
+}
+
+// Result is a date read from text.
+type Result struct {
+	Time       time.Time // Midnight UTC; zero for a relative deadline with no reference date
+	Confidence Confidence
+	Relative   bool   // Read from a phrase such as "within 30 days of publication"
+	Days       int    // For relative deadlines, the days allowed after the reference date
+	Match      string // The text the date was read from, with Devanagari digits as ASCII
+}
+
+// Format returns the date as YYYY-MM-DD, or "" when it has no time.
+func (r Result) Format() string {
+	if r.Time.IsZero() {
+		return ""
+	}
+	return r.Time.Format("2006-01-02")
+}
+
+// months maps every month spelling to its number. Hindi spellings have no
+// nukta; see normalise.
+var months = map[string]time.Month{
+	"jan": 1, "january": 1, "जनवरी": 1,
+	"feb": 2, "february": 2, "फरवरी": 2,
+	"mar": 3, "march": 3, "मार्च": 3,
+	"apr": 4, "april": 4, "अप्रैल": 4, "अप्रेल": 4,
+	"may": 5, "मई": 5,
+	"jun": 6, "june": 6, "जून": 6,
+	"jul": 7, "july": 7, "जुलाई": 7,
+	"aug": 8, "august": 8, "अगस्त": 8,
+	"sep": 9, "sept": 9, "september": 9, "सितंबर": 9, "सितम्बर": 9,
+	"oct": 10, "october": 10, "अक्टूबर": 10, "अक्तूबर": 10,
+	"nov": 11, "november": 11, "नवंबर": 11, "नवम्बर": 11,
+	"dec": 12, "december": 12, "दिसंबर": 12, "दिसम्बर": 12,
+}
+
+// monthPattern matches any spelling in months. Go's \b only knows ASCII
+// letters, so the Hindi names go without it.
+const monthPattern = `(jan(?:uary)?|feb(?:ruary)?|mar(?:ch)?|apr(?:il)?|may|june?|july?|aug(?:ust)?|sep(?:t(?:ember)?)?|oct(?:ober)?|nov(?:ember)?|dec(?:ember)?)\b\.?` +
+	`|(जनवरी|फरवरी|मार्च|अप्रैल|अप्रेल|मई|जून|जुलाई|अगस्त|सितंबर|सितम्बर|अक्टूबर|अक्तूबर|नवंबर|नवम्बर|दिसंबर|दिसम्बर)`
+
+var (
+	// isoRegex matches 2026-03-15.
+	isoRegex = regexp.MustCompile(`\b(\d{4})-(\d{1,2})-(\d{1,2})\b`)
+
+	// numericRegex matches 15.03.2026, 15/03/2026, 15-03-2026 and their
+	// two-digit-year forms.
+	numericRegex = regexp.MustCompile(`\b(\d{1,2})([./-])(\d{1,2})([./-])(\d{4}|\d{2})\b`)
+
+	// dayMonthRegex matches 15th March, 2026, 15 Mar 2026, 15-Mar-26 and
+	// 15 March. A two-digit year needs a -, /, . or ' before it, so "15 March,
+	// 26 posts" is not read as 2026.
+	dayMonthRegex = regexp.MustCompile(`(?i)\b(\d{1,2})(?:st|nd|rd|th)?(?:\s+of)?[\s\-./]*(?:` + monthPattern + `)(?:,?[\s\-./]*(\d{4})\b|\s?[\-/.']\s?(\d{2})\b)?`)
+
+	// monthDayRegex matches March 15, 2026 and Mar. 15th 2026.
+	monthDayRegex = regexp.MustCompile(`(?i)(?:` + monthPattern + `)\s*(\d{1,2})(?:st|nd|rd|th)?,?\s+(\d{4})\b`)
+
+	// withinDaysRegex matches "within 30 days of publication in Employment
+	// News" and "within 21 days from the date of advertisement".
+	withinDaysRegex = regexp.MustCompile(`(?i)\bwithin\s+(\d{1,3})\s+days?\b[^.;\n]{0,60}?\b(?:publication|published|advertisement|advt|issue|notification|appearance)`)
+
+	// daysFromRegex matches "30 days from the date of publication" and "the
+	// 21st day of publication".
+	daysFromRegex = regexp.MustCompile(`(?i)\b(\d{1,3})(?:st|nd|rd|th)?\s+days?\s+(?:from|of|after)\s+(?:the\s+)?(?:date\s+of\s+)?(?:its\s+)?(?:publication|advertisement|advt|issue|notification|appearance)`)
+
+	// hindiWithinDaysRegex matches "30 दिनों के भीतर" (within 30 days).
+	hindiWithinDaysRegex = regexp.MustCompile(`(\d{1,3})\s*दिन(?:ों)?\s*(?:के\s*)?(?:भीतर|अंदर|अन्दर)`)
+
+	// closingRegex matches the phrases that introduce a closing date.
+	closingRegex = regexp.MustCompile(`(?i)\b(?:last|closing|end)\s+date\b|\bon\s+or\s+before\b|\bnot\s+later\s+than\b|\bdue\s+date\b|अंतिम\s*(?:तिथि|तारीख|दिनांक)`)
+)
+
+// closingWindow is how far after a closing phrase its date may start.
+const closingWindow = 60
+
+// Parse returns the first date in text. Relative deadlines are resolved
+// against ref, as are dates without a year; with a zero ref, yearless dates
+// are skipped and relative deadlines are returned without a time.
+func Parse(text string, ref time.Time) (Result, bool) {
+	all := ParseAll(text, ref)
+	if len(all) == 0 {
+		return Result{}, false
+	}
+	return all[0], true
+}
+
+// ParseAll returns every date in text in order of appearance. See Parse.
+func ParseAll(text string, ref time.Time) []Result {
+	found := find(normalise(text), ref)
+	results := make([]Result, len(found))
+	for i, c := range found {
+		results[i] = c.result
+	}
+	return results
+}
+
+// Closing returns the closing date stated in text: the date following a
+// phrase such as "last date", "on or before" or "अंतिम तिथि", else a
+// relative deadline anywhere in text. Relative deadlines are resolved as in
+// Parse.
+func Closing(text string, ref time.Time) (Result, bool) {
+	text = normalise(text)
+	for _, loc := range closingRegex.FindAllStringIndex(text, -1) {
+		if found := find(text[loc[1]:], ref); len(found) > 0 && found[0].start <= closingWindow {
+			return found[0].result, true
+		}
+	}
+	for _, c := range find(text, ref) {
+		if c.result.Relative {
+			return c.result, true
+		}
+	}
+	return Result{}, false
+}
+
+// candidate is a match of one of the patterns at a position in the text.
+type candidate struct {
+	start, end int
+	result     Result
+}
+
+// find reads every date in normalised text, keeping the earliest, longest
+// match where patterns overlap.
+func find(text string, ref time.Time) []candidate {
+	var found []candidate
+	add := func(re *regexp.Regexp, read func(m []string) (Result, bool)) {
+		for _, loc := range re.FindAllStringSubmatchIndex(text, -1) {
+			m := make([]string, len(loc)/2)
+			for i := range m {
+				if loc[2*i] >= 0 {
+					m[i] = text[loc[2*i]:loc[2*i+1]]
+				}
+			}
+			if r, ok := read(m); ok {
+				r.Match = strings.TrimSpace(m[0])
+				found = append(found, candidate{start: loc[0], end: loc[1], result: r})
+			}
+		}
+	}
+
+	add(isoRegex, func(m []string) (Result, bool) {
+		return date(m[1], m[2], m[3], High)
+	})
+	add(numericRegex, func(m []string) (Result, bool) {
+		// 15.03-2026 is more likely a range or a version than a date.
+		if m[2] != m[4] {
+			return Result{}, false
+		}
+		return date(m[5], m[3], m[1], yearConfidence(m[5]))
+	})
+	add(dayMonthRegex, func(m []string) (Result, bool) {
+		month := firstNonEmpty(m[2], m[3])
+		year := firstNonEmpty(m[4], m[5])
+		if year == "" {
+			// "candidates aged 18 may apply"
+			if strings.EqualFold(month, "may") {
+				return Result{}, false
+			}
+			return yearless(m[1], month, ref)
+		}
+		return date(year, month, m[1], yearConfidence(year))
+	})
+	add(monthDayRegex, func(m []string) (Result, bool) {
+		return date(m[4], firstNonEmpty(m[1], m[2]), m[3], High)
+	})
+	for _, re := range []*regexp.Regexp{withinDaysRegex, daysFromRegex, hindiWithinDaysRegex} {
+		add(re, func(m []string) (Result, bool) {
+			return relative(m[1], ref)
+		})
+	}
+
+	sort.SliceStable(found, func(i, j int) bool {
+		if found[i].start != found[j].start {
+			return found[i].start < found[j].start
+		}
+		return found[i].end > found[j].end
+	})
+	var kept []candidate
+	end := -1
+	for _, c := range found {
+		if c.start < end {
+			continue
+		}
+		kept = append(kept, c)
+		end = c.end
+	}
+	return kept
+}
+
+// date builds a Result from year, month (a number or name) and day,
+// rejecting impossible dates such as 31.02.2026.
+func date(year, month, day string, confidence Confidence) (Result, bool) {
+	y, err := strconv.Atoi(year)
+	if err != nil {
+		return Result{}, false
+	}
+	if len(year) == 2 {
+		y += 2000
+	}
+	m, ok := monthNumber(month)
+	if !ok {
+		return Result{}, false
+	}
+	d, err := strconv.Atoi(day)
+	if err != nil {
+		return Result{}, false
+	}
+
+	t := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	if t.Year() != y || t.Month() != m || t.Day() != d {
+		return Result{}, false
+	}
+	return Result{Time: t, Confidence: confidence}, true
+}
+
+// yearless reads a day and month without a year, taking the year that puts
+// the date nearest ref.
+func yearless(day, month string, ref time.Time) (Result, bool) {
+	if ref.IsZero() {
+		return Result{}, false
+	}
+	ref = midnight(ref)
+	best, ok := Result{}, false
+	for _, y := range []int{ref.Year() - 1, ref.Year(), ref.Year() + 1} {
+		r, valid := date(strconv.Itoa(y), month, day, Low)
+		if valid && (!ok || distance(r.Time, ref) < distance(best.Time, ref)) {
+			best, ok = r, true
+		}
+	}
+	return best, ok
+}
+
+// relative builds a deadline days after ref.
+func relative(days string, ref time.Time) (Result, bool) {
+	n, err := strconv.Atoi(days)
+	if err != nil || n == 0 {
+		return Result{}, false
+	}
+	r := Result{Confidence: Low, Relative: true, Days: n}
+	if !ref.IsZero() {
+		r.Time = midnight(ref).AddDate(0, 0, n)
+	}
+	return r, true
+}
+
+// monthNumber reads a month given as a number or a name.
+func monthNumber(s string) (time.Month, bool) {
+	if n, err := strconv.Atoi(s); err == nil {
+		return time.Month(n), n >= 1 && n <= 12
+	}
+	m, ok := months[strings.TrimSuffix(strings.ToLower(s), ".")]
+	return m, ok
+}
+
+// yearConfidence rates a date by how its year was written.
+func yearConfidence(year string) Confidence {
+	if len(year) == 2 {
+		return Medium
+	}
+	return High
+}
+
+// midnight returns midnight UTC of t's calendar date in t's location.
+func midnight(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func distance(a, b time.Time) time.Duration {
+	if a.Before(b) {
+		return b.Sub(a)
+	}
+	return a.Sub(b)
+}
+
+func firstNonEmpty(a, b string) string {
+	if a != "" {
+		return a
+	}
+	return b
+}
+
+// devanagari maps Devanagari digits to ASCII and folds the nukta spellings
+// of फ़रवरी and friends into the plain letters used in months.
+var devanagari = strings.NewReplacer(
+	"०", "0", "१", "1", "२", "2", "३", "3", "४", "4",
+	"५", "5", "६", "6", "७", "7", "८", "8", "९", "9",
+	"़", "", "फ़", "फ",
+)
+
+// normalise prepares text for matching.
+func normalise(text string) string {
+	return devanagari.Replace(text)
+}
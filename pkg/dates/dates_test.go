@@ -0,0 +1,127 @@
+package dates
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	ref := time.Date(2026, 3, 1, 15, 30, 0, 0, time.FixedZone("IST", 5*3600+1800))
+
+	tests := []struct {
+		in         string
+		ref        time.Time
+		want       string
+		confidence Confidence
+	}{
+		// Numeric, day first.
+		{"15.03.2026", time.Time{}, "2026-03-15", High},
+		{"15/03/2026", time.Time{}, "2026-03-15", High},
+		{"15-03-2026", time.Time{}, "2026-03-15", High},
+		{"5.3.2026", time.Time{}, "2026-03-05", High},
+		{"15.03.26", time.Time{}, "2026-03-15", Medium},
+		{"Last date: 01.12.2026 (5 PM)", time.Time{}, "2026-12-01", High},
+		{"2026-03-15", time.Time{}, "2026-03-15", High},
+
+		// Month names.
+		{"15th March, 2026", time.Time{}, "2026-03-15", High},
+		{"1st Apr 2026", time.Time{}, "2026-04-01", High},
+		{"22nd of February 2026", time.Time{}, "2026-02-22", High},
+		{"15 SEPT. 2026", time.Time{}, "2026-09-15", High},
+		{"15-Mar-26", time.Time{}, "2026-03-15", Medium},
+		{"15 Mar '26", time.Time{}, "2026-03-15", Medium},
+		{"March 15, 2026", time.Time{}, "2026-03-15", High},
+		{"Dec. 3rd 2026", time.Time{}, "2026-12-03", High},
+
+		// Hindi month names and Devanagari digits.
+		{"15 मार्च 2026", time.Time{}, "2026-03-15", High},
+		{"अंतिम तिथि: १५ मार्च, २०२६", time.Time{}, "2026-03-15", High},
+		{"१५.०३.२०२६", time.Time{}, "2026-03-15", High},
+		{"28 फ़रवरी 2026", time.Time{}, "2026-02-28", High},
+		{"2 सितम्बर 2026", time.Time{}, "2026-09-02", High},
+
+		// No year: taken from the reference date, nearest first.
+		{"15th March", ref, "2026-03-15", Low},
+		{"20 December", ref, "2025-12-20", Low},
+		{"15th March", time.Time{}, "", None},
+
+		// Relative deadlines.
+		{"within 30 days of publication in Employment News", ref, "2026-03-31", Low},
+		{"Applications must reach within 21 days from the date of advertisement", ref, "2026-03-22", Low},
+		{"on the 45th day from the date of publication", ref, "2026-04-15", Low},
+		{"रोजगार समाचार में प्रकाशन की तिथि से 30 दिनों के भीतर", ref, "2026-03-31", Low},
+
+		// Not dates.
+		{"31.02.2026", time.Time{}, "", None},
+		{"15.13.2026", time.Time{}, "", None},
+		{"32.01.2026", time.Time{}, "", None},
+		{"15.03-2026", time.Time{}, "", None},
+		{"Level 7", time.Time{}, "", None},
+		{"candidates aged 18 may apply", ref, "", None},
+		{"no date", time.Time{}, "", None},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, ok := Parse(tt.in, tt.ref)
+			if got.Format() != tt.want || got.Confidence != tt.confidence {
+				t.Errorf("Parse(%q) = %q (%v), want %q (%v)", tt.in, got.Format(), got.Confidence, tt.want, tt.confidence)
+			}
+			if ok != (tt.confidence != None) {
+				t.Errorf("Parse(%q) ok = %v", tt.in, ok)
+			}
+		})
+	}
+}
+
+func TestParse_RelativeWithoutReference(t *testing.T) {
+	got, ok := Parse("within 30 days of publication in Employment News", time.Time{})
+	if !ok || !got.Relative || got.Days != 30 || !got.Time.IsZero() || got.Format() != "" {
+		t.Errorf("got %+v, %v; want an unresolved 30-day deadline", got, ok)
+	}
+}
+
+func TestParseAll(t *testing.T) {
+	got := ParseAll("Published 01.03.2026; last date 15th March, 2026 (extended to 2026-03-31)", time.Time{})
+	want := []string{"2026-03-01", "2026-03-15", "2026-03-31"}
+	if len(got) != len(want) {
+		t.Fatalf("got %d dates %+v, want %v", len(got), got, want)
+	}
+	for i := range want {
+		if got[i].Format() != want[i] {
+			t.Errorf("date %d: got %s, want %s", i, got[i].Format(), want[i])
+		}
+	}
+	if got[1].Match != "15th March, 2026" {
+		t.Errorf("unexpected match %q", got[1].Match)
+	}
+}
+
+func TestClosing(t *testing.T) {
+	ref := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"Published on 01.03.2026. Last date for receipt of applications: 31.03.2026", "2026-03-31"},
+		{"Applications should reach on or before 15th April, 2026.", "2026-04-15"},
+		{"Closing Date - 20-Mar-26", "2026-03-20"},
+		{"not later than March 25, 2026", "2026-03-25"},
+		{"आवेदन की अंतिम तिथि 10.04.2026 है", "2026-04-10"},
+		{"Issued 01.03.2026. Apply within 30 days of publication in Employment News.", "2026-03-31"},
+		// The phrase's date is too far away to belong to it.
+		{"Last date: see the notification, which runs to several pages and lists every condition, dated 01.01.2026", ""},
+		// A date with no closing phrase is not a deadline.
+		{"Notification dated 01.03.2026", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, ok := Closing(tt.in, ref)
+			if got.Format() != tt.want || ok != (tt.want != "") {
+				t.Errorf("Closing(%q) = %q, %v; want %q", tt.in, got.Format(), ok, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,187 @@
+package db
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// UpsertResult reports what an upsert did to a job.
+type UpsertResult int
+
+const (
+	Unchanged UpsertResult = iota // Already stored with the same content
+	Inserted                      // New job
+	Updated                       // Stored job whose content changed
+)
+
+// String returns the result's name as used in logs.
+func (r UpsertResult) String() string {
+	switch r {
+	case Inserted:
+		return "inserted"
+	case Updated:
+		return "updated"
+	default:
+		return "unchanged"
+	}
+}
+
+// UpsertCounts counts the results of a batch upsert.
+type UpsertCounts struct {
+	Inserted  int
+	Updated   int
+	Unchanged int
+}
+
+// UpsertSummary describes a batch upsert: how many jobs had each result,
+// which were new and what changed, in batch order.
+type UpsertSummary struct {
+	UpsertCounts
+	InsertedIDs []string
+	Changes     []Change
+}
+
+// add records the result of upserting job id with changes.
+func (s *UpsertSummary) add(id string, r UpsertResult, changes []Change) {
+	switch r {
+	case Inserted:
+		s.Inserted++
+		s.InsertedIDs = append(s.InsertedIDs, id)
+	case Updated:
+		s.Updated++
+		s.Changes = append(s.Changes, changes...)
+	default:
+		s.Unchanged++
+	}
+}
+
+// Change is one edit to a stored job, as recorded in job_history.
+type Change struct {
+	JobID     string
+	Field     string // jobs column name, e.g. "closing_date"
+	OldValue  string
+	NewValue  string
+	ChangedAt int64 // Unix timestamp of the run that saw the change
+}
+
+// mergeJob returns job as it will be stored, given the stored row (nil for
+// a new job), and the fields that changed. Only content fields are compared:
+// last_seen_at and status move on every run and are not changes.
+//
+// Values the scrape did not provide do not overwrite stored ones: a zero
+// PostedDate keeps the stored date (or the first-seen time for a new job),
+// and empty advertisement and listing details keep what an earlier run
+// extracted, so a run without FETCH_ADVERTS erases nothing.
+func mergeJob(stored *Job, job Job, seenAt int64) (Job, []Change) {
+	job.LastSeenAt = seenAt
+	job.Status = StatusActive
+
+	if stored == nil {
+		job.FirstSeenAt = seenAt
+		if job.PostedDate <= 0 {
+			job.PostedDate = seenAt
+		}
+		return job, nil
+	}
+
+	job.FirstSeenAt = stored.FirstSeenAt
+	if job.PostedDate <= 0 {
+		job.PostedDate = stored.PostedDate
+	}
+	if job.Vacancies == 0 {
+		job.Vacancies = stored.Vacancies
+	}
+	if job.PayLevel == "" {
+		job.PayLevel = stored.PayLevel
+	}
+	if job.AgeLimit == "" {
+		job.AgeLimit = stored.AgeLimit
+	}
+	if job.ClosingDate == "" {
+		job.ClosingDate, job.ClosingDateConfidence = stored.ClosingDate, stored.ClosingDateConfidence
+	}
+	if job.AdvertText == "" {
+		job.AdvertText = stored.AdvertText
+	}
+	if job.Fingerprint == "" {
+		job.Fingerprint = stored.Fingerprint
+	}
+	if job.AdvertisementNo == "" {
+		job.AdvertisementNo = stored.AdvertisementNo
+	}
+	if len(job.Qualifications) == 0 {
+		job.Qualifications = stored.Qualifications
+	}
+	if job.PayScale == "" {
+		job.PayScale = stored.PayScale
+	}
+	if job.MinAge == 0 && job.MaxAge == 0 {
+		job.MinAge, job.MaxAge = stored.MinAge, stored.MaxAge
+	}
+	if job.ApplicationMode == "" {
+		job.ApplicationMode = stored.ApplicationMode
+	}
+	if len(job.Attachments) == 0 {
+		job.Attachments = stored.Attachments
+	}
+
+	var changes []Change
+	diff := func(field, oldValue, newValue string) {
+		if oldValue != newValue {
+			changes = append(changes, Change{
+				JobID:     job.ID,
+				Field:     field,
+				OldValue:  oldValue,
+				NewValue:  newValue,
+				ChangedAt: seenAt,
+			})
+		}
+	}
+	diff("title", stored.Title, job.Title)
+	diff("department", stored.Department, job.Department)
+	diff("location", stored.Location, job.Location)
+	diff("posted_date", strconv.FormatInt(stored.PostedDate, 10), strconv.FormatInt(job.PostedDate, 10))
+	diff("url", stored.URL, job.URL)
+	diff("vacancies", strconv.Itoa(stored.Vacancies), strconv.Itoa(job.Vacancies))
+	diff("pay_level", stored.PayLevel, job.PayLevel)
+	diff("age_limit", stored.AgeLimit, job.AgeLimit)
+	diff("closing_date", stored.ClosingDate, job.ClosingDate)
+	diff("source", stored.Source, job.Source)
+	diff("advertisement_no", stored.AdvertisementNo, job.AdvertisementNo)
+	diff("qualifications", EncodeList(stored.Qualifications), EncodeList(job.Qualifications))
+	diff("pay_scale", stored.PayScale, job.PayScale)
+	diff("min_age", strconv.Itoa(stored.MinAge), strconv.Itoa(job.MinAge))
+	diff("max_age", strconv.Itoa(stored.MaxAge), strconv.Itoa(job.MaxAge))
+	diff("application_mode", stored.ApplicationMode, job.ApplicationMode)
+	diff("attachments", EncodeAttachments(stored.Attachments), EncodeAttachments(job.Attachments))
+	if stored.AdvertText != job.AdvertText {
+		// The text itself can run to 64 KB; history only notes the change.
+		diff("advert_text", "", "(changed)")
+	}
+	return job, changes
+}
+
+// JobHistory returns the recorded changes to a job, oldest first.
+func (d *DB) JobHistory(id string) ([]Change, error) {
+	rows, err := d.conn.Query(`
+	SELECT job_id, field, old_value, new_value, changed_at
+	FROM job_history WHERE job_id = ? ORDER BY changed_at, id
+	`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history of job %s: %w", id, err)
+	}
+	defer rows.Close()
+
+	var changes []Change
+	for rows.Next() {
+		var c Change
+		if err := rows.Scan(&c.JobID, &c.Field, &c.OldValue, &c.NewValue, &c.ChangedAt); err != nil {
+			return nil, fmt.Errorf("failed to read history of job %s: %w", id, err)
+		}
+		changes = append(changes, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read history of job %s: %w", id, err)
+	}
+	return changes, nil
+}
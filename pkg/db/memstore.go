@@ -0,0 +1,187 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+// errStoreClosed is returned by MemStore methods after OptimizeAndClose.
+var errStoreClosed = errors.New("store is closed")
+
+// MemStore is an in-memory Store with the same upsert and lifecycle
+// semantics as DB. Safe for concurrent use.
+type MemStore struct {
+	mu      sync.Mutex
+	jobs    map[string]Job
+	history map[string][]Change
+	closed  bool
+}
+
+// NewMemStore creates an empty in-memory store.
+func NewMemStore() *MemStore {
+	return &MemStore{
+		jobs:    make(map[string]Job),
+		history: make(map[string][]Change),
+	}
+}
+
+// UpsertJobs implements Store.
+func (m *MemStore) UpsertJobs(ctx context.Context, jobs []Job) (UpsertSummary, error) {
+	var summary UpsertSummary
+	if err := ctx.Err(); err != nil {
+		return summary, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return summary, errStoreClosed
+	}
+
+	now := time.Now().Unix()
+	for _, job := range jobs {
+		seenAt := job.LastSeenAt
+		if seenAt == 0 {
+			seenAt = now
+		}
+
+		var stored *Job
+		if existing, ok := m.jobs[job.ID]; ok {
+			stored = &existing
+		}
+		merged, changes := mergeJob(stored, job, seenAt)
+		merged.CanonicalID = m.canonicalID(merged)
+		m.jobs[job.ID] = merged
+		m.history[job.ID] = append(m.history[job.ID], changes...)
+
+		result := Unchanged
+		switch {
+		case stored == nil:
+			result = Inserted
+		case len(changes) > 0:
+			result = Updated
+		}
+		summary.add(merged.ID, result, changes)
+	}
+	return summary, nil
+}
+
+// canonicalID mirrors canonicalIDSQL: the canonical ID of the earliest seen
+// other job sharing job's fingerprint, else job's own ID.
+func (m *MemStore) canonicalID(job Job) string {
+	if job.Fingerprint == "" {
+		return job.ID
+	}
+	var first *Job
+	for id, other := range m.jobs {
+		if id == job.ID || other.Fingerprint != job.Fingerprint {
+			continue
+		}
+		if first == nil || other.FirstSeenAt < first.FirstSeenAt ||
+			(other.FirstSeenAt == first.FirstSeenAt && other.ID < first.ID) {
+			o := other
+			first = &o
+		}
+	}
+	switch {
+	case first == nil:
+		return job.ID
+	case first.CanonicalID != "":
+		return first.CanonicalID
+	}
+	return first.ID
+}
+
+// GetJob implements Store.
+func (m *MemStore) GetJob(id string) (Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return Job{}, errStoreClosed
+	}
+
+	job, ok := m.jobs[id]
+	if !ok {
+		return Job{}, sql.ErrNoRows
+	}
+	return job, nil
+}
+
+// AllJobs implements Store.
+func (m *MemStore) AllJobs() ([]Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return nil, errStoreClosed
+	}
+
+	jobs := make([]Job, 0, len(m.jobs))
+	for _, job := range m.jobs {
+		jobs = append(jobs, job)
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].ID < jobs[j].ID })
+	return jobs, nil
+}
+
+// JobHistory implements Store.
+func (m *MemStore) JobHistory(id string) ([]Change, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return nil, errStoreClosed
+	}
+	return append([]Change(nil), m.history[id]...), nil
+}
+
+// MarkRemoved implements Store.
+func (m *MemStore) MarkRemoved(source string, seenAt int64) (int64, error) {
+	return m.mark(StatusRemoved, func(j Job) bool {
+		return j.Source == source && j.LastSeenAt < seenAt && j.Status != StatusRemoved
+	})
+}
+
+// MarkExpired implements Store.
+func (m *MemStore) MarkExpired(today string) (int64, error) {
+	return m.mark(StatusExpired, func(j Job) bool {
+		return j.Status == StatusActive && j.ClosingDate != "" && j.ClosingDate < today &&
+			j.ClosingDateConfidence != ConfidenceLow
+	})
+}
+
+// mark sets status on every job matching match.
+func (m *MemStore) mark(status string, match func(Job) bool) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return 0, errStoreClosed
+	}
+
+	var n int64
+	for id, job := range m.jobs {
+		if match(job) {
+			job.Status = status
+			m.jobs[id] = job
+			n++
+		}
+	}
+	return n, nil
+}
+
+// OptimizeAndClose implements Store. Later calls to other methods fail.
+func (m *MemStore) OptimizeAndClose() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.closed = true
+	return nil
+}
+
+// Closed reports whether OptimizeAndClose has been called.
+func (m *MemStore) Closed() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.closed
+}
@@ -0,0 +1,717 @@
+package db
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"embed"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// SchemaVersion is the schema version this build writes. It is published in
+// metadata.json so clients can refuse databases newer than they understand.
+// Bump it together with every migration appended below.
+const SchemaVersion = 9
+
+// MinReaderVersion is the oldest schema a client must understand to read a
+// database this build writes. It is published in metadata.json and gates
+// clients instead of SchemaVersion, so adding a column or table does not
+// lock out installed apps. Raise it to SchemaVersion only with a migration
+// that drops, renames or changes the meaning of something clients read.
+const MinReaderVersion = 1
+
+// ErrSchemaTooNew is returned when a database was written by a newer build
+// whose migrations this build does not know.
+var ErrSchemaTooNew = errors.New("database schema is newer than this build supports")
+
+// migration is one forward-only schema change. Migrations run in order, each
+// in its own transaction, and are never edited once released — add a new one.
+// A migration must not call code that may change later: copy the rules or
+// data it needs into this file.
+type migration struct {
+	version int
+	name    string
+	up      func(tx *sql.Tx) error
+}
+
+// migrations lists every schema change in order. Versions start at 1 and
+// increase by one.
+//
+// Some databases predate the migration table but already contain columns
+// added by later migrations, so column additions use addColumns.
+var migrations = []migration{
+	{
+		version: 1,
+		name:    "create jobs table",
+		up: func(tx *sql.Tx) error {
+			_, err := tx.Exec(`
+			CREATE TABLE IF NOT EXISTS jobs (
+				id TEXT PRIMARY KEY,
+				title TEXT,
+				department TEXT,
+				location TEXT,
+				posted_date INTEGER,
+				url TEXT
+			);
+			`)
+			return err
+		},
+	},
+	{
+		version: 2,
+		name:    "add advertisement columns",
+		up: func(tx *sql.Tx) error {
+			return addColumns(tx, "jobs",
+				"vacancies INTEGER",
+				"pay_level TEXT",
+				"age_limit TEXT",
+				"closing_date TEXT",
+				"advert_text TEXT",
+			)
+		},
+	},
+	{
+		version: 3,
+		name:    "add lifecycle columns",
+		up: func(tx *sql.Tx) error {
+			err := addColumns(tx, "jobs",
+				"source TEXT",
+				"first_seen_at INTEGER",
+				"last_seen_at INTEGER",
+				"status TEXT NOT NULL DEFAULT 'active'",
+			)
+			if err != nil {
+				return err
+			}
+
+			// Existing rows: posted_date was the time of the last scrape, and
+			// NIC was the only source.
+			_, err = tx.Exec(`
+			UPDATE jobs SET
+				first_seen_at = COALESCE(first_seen_at, posted_date),
+				last_seen_at  = COALESCE(last_seen_at, posted_date),
+				source        = COALESCE(source, 'nic')
+			WHERE first_seen_at IS NULL OR last_seen_at IS NULL OR source IS NULL;
+			`)
+			return err
+		},
+	},
+	{
+		version: 4,
+		name:    "add full-text search and indexes",
+		up: func(tx *sql.Tx) error {
+			// jobs_fts is an external-content FTS5 index over jobs: it stores
+			// only the index, and the triggers keep it in step with jobs.
+			_, err := tx.Exec(`
+			CREATE VIRTUAL TABLE IF NOT EXISTS jobs_fts USING fts5(
+				title, department, location, advert_text,
+				content='jobs', content_rowid='rowid',
+				tokenize='unicode61 remove_diacritics 2'
+			);
+
+			CREATE TRIGGER IF NOT EXISTS jobs_fts_ai AFTER INSERT ON jobs BEGIN
+				INSERT INTO jobs_fts(rowid, title, department, location, advert_text)
+				VALUES (new.rowid, new.title, new.department, new.location, new.advert_text);
+			END;
+
+			CREATE TRIGGER IF NOT EXISTS jobs_fts_ad AFTER DELETE ON jobs BEGIN
+				INSERT INTO jobs_fts(jobs_fts, rowid, title, department, location, advert_text)
+				VALUES ('delete', old.rowid, old.title, old.department, old.location, old.advert_text);
+			END;
+
+			CREATE TRIGGER IF NOT EXISTS jobs_fts_au AFTER UPDATE OF title, department, location, advert_text ON jobs BEGIN
+				INSERT INTO jobs_fts(jobs_fts, rowid, title, department, location, advert_text)
+				VALUES ('delete', old.rowid, old.title, old.department, old.location, old.advert_text);
+				INSERT INTO jobs_fts(rowid, title, department, location, advert_text)
+				VALUES (new.rowid, new.title, new.department, new.location, new.advert_text);
+			END;
+
+			INSERT INTO jobs_fts(jobs_fts) VALUES ('rebuild');
+
+			CREATE INDEX IF NOT EXISTS idx_jobs_posted_date ON jobs(posted_date);
+			CREATE INDEX IF NOT EXISTS idx_jobs_status ON jobs(status);
+			CREATE INDEX IF NOT EXISTS idx_jobs_closing_date ON jobs(closing_date);
+			CREATE INDEX IF NOT EXISTS idx_jobs_source ON jobs(source);
+			`)
+			return err
+		},
+	},
+	{
+		version: 5,
+		name:    "add job history",
+		up: func(tx *sql.Tx) error {
+			// One row per changed field per run; see mergeJob.
+			_, err := tx.Exec(`
+			CREATE TABLE IF NOT EXISTS job_history (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				job_id TEXT NOT NULL,
+				field TEXT NOT NULL,
+				old_value TEXT NOT NULL,
+				new_value TEXT NOT NULL,
+				changed_at INTEGER NOT NULL
+			);
+
+			CREATE INDEX IF NOT EXISTS idx_job_history_job ON job_history(job_id, changed_at);
+			`)
+			return err
+		},
+	},
+	{
+		version: 6,
+		name:    "add duplicate detection",
+		up: func(tx *sql.Tx) error {
+			err := addColumns(tx, "jobs",
+				"fingerprint TEXT",
+				"canonical_id TEXT",
+			)
+			if err != nil {
+				return err
+			}
+			if err := rekeyJobs(tx); err != nil {
+				return err
+			}
+
+			// Fingerprints are filled in by the next scrape; until then every
+			// job is its own canonical job.
+			_, err = tx.Exec(`
+			UPDATE jobs SET canonical_id = id WHERE canonical_id IS NULL;
+
+			CREATE INDEX IF NOT EXISTS idx_jobs_fingerprint ON jobs(fingerprint);
+			CREATE INDEX IF NOT EXISTS idx_jobs_canonical_id ON jobs(canonical_id);
+			`)
+			return err
+		},
+	},
+	{
+		version: 7,
+		name:    "add structured posting details",
+		up: func(tx *sql.Tx) error {
+			// Lists are stored as JSON arrays; see EncodeList and
+			// EncodeAttachments.
+			return addColumns(tx, "jobs",
+				"advertisement_no TEXT",
+				"qualifications TEXT",
+				"pay_scale TEXT",
+				"min_age INTEGER",
+				"max_age INTEGER",
+				"application_mode TEXT",
+				"attachments TEXT",
+			)
+		},
+	},
+	{
+		version: 8,
+		name:    "add closing date confidence",
+		up: func(tx *sql.Tx) error {
+			// Existing rows stay NULL: their confidence is unknown, and they
+			// expire as before.
+			return addColumns(tx, "jobs", "closing_date_confidence TEXT")
+		},
+	},
+	{
+		version: 9,
+		name:    "add normalised departments and locations",
+		up: func(tx *sql.Tx) error {
+			err := addColumns(tx, "jobs",
+				"department_id TEXT",
+				"location_id TEXT",
+			)
+			if err != nil {
+				return err
+			}
+			if err := normaliseEntities(tx); err != nil {
+				return err
+			}
+			_, err = tx.Exec(`
+			CREATE INDEX IF NOT EXISTS idx_jobs_department_id ON jobs(department_id);
+			CREATE INDEX IF NOT EXISTS idx_jobs_location_id ON jobs(location_id);
+			`)
+			return err
+		},
+	},
+}
+
+// normaliseEntities gives every job the pkg/entity IDs of its department and
+// location and renames them to the entities' canonical names, as the scraper
+// now does, so the next scrape does not record the renames as changes.
+// Names not in the dataset are kept, with an empty ID. Like the scraper, a
+// location that is empty or only names the country yields to a city or
+// state named in the title. It matches with v9Catalog, not pkg/entity, which
+// may change after release.
+func normaliseEntities(tx *sql.Tx) error {
+	rows, err := tx.Query(`SELECT id, title, department, location FROM jobs`)
+	if err != nil {
+		return fmt.Errorf("failed to list jobs for normalising: %w", err)
+	}
+	type names struct {
+		id, department, location, departmentID, locationID string
+	}
+	var jobs []names
+	catalog, err := loadV9Catalog()
+	if err != nil {
+		rows.Close()
+		return err
+	}
+	for rows.Next() {
+		var (
+			n                           names
+			title, department, location sql.NullString
+		)
+		if err := rows.Scan(&n.id, &title, &department, &location); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to read job for normalising: %w", err)
+		}
+		n.department, n.location = department.String, location.String
+		if e, ok := catalog.departments.find(n.department); ok {
+			n.department, n.departmentID = e.name, e.id
+		}
+		loc, ok := catalog.locations.find(n.location)
+		if n.location == "" || (ok && !strings.Contains(loc.id, "/")) {
+			if inTitle, found := catalog.locations.find(title.String); found && strings.Contains(inTitle.id, "/") {
+				loc, ok = inTitle, true
+			}
+		}
+		if ok {
+			n.location, n.locationID = loc.name, loc.id
+		}
+		jobs = append(jobs, n)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to list jobs for normalising: %w", err)
+	}
+
+	stmt, err := tx.Prepare(`UPDATE jobs SET department = ?, department_id = ?, location = ?, location_id = ? WHERE id = ?`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare normalising: %w", err)
+	}
+	defer stmt.Close()
+	for _, n := range jobs {
+		if _, err := stmt.Exec(n.department, n.departmentID, n.location, n.locationID, n.id); err != nil {
+			return fmt.Errorf("failed to normalise job %s: %w", n.id, err)
+		}
+	}
+	return nil
+}
+
+// v9Dataset is the pkg/entity dataset as released with migration 9.
+//
+//go:embed migrations/v9_departments.json migrations/v9_locations.json
+var v9Dataset embed.FS
+
+// v9Catalog is pkg/entity's name matching as released with migration 9,
+// over v9Dataset.
+type v9Catalog struct {
+	departments, locations *v9Index
+}
+
+type v9Entity struct {
+	id, name string
+}
+
+type v9Index struct {
+	exact   map[string]v9Entity // Key of every name and alias
+	aliases []v9Alias           // Names found inside longer text, longest first
+}
+
+type v9Alias struct {
+	tokens []string
+	entity v9Entity
+	depth  int
+}
+
+type v9Node struct {
+	ID       string   `json:"id"`
+	Name     string   `json:"name"`
+	Aliases  []string `json:"aliases"`
+	Children []v9Node `json:"children"`
+}
+
+func loadV9Catalog() (*v9Catalog, error) {
+	departments, err := loadV9Index("migrations/v9_departments.json")
+	if err != nil {
+		return nil, err
+	}
+	locations, err := loadV9Index("migrations/v9_locations.json")
+	if err != nil {
+		return nil, err
+	}
+	return &v9Catalog{departments: departments, locations: locations}, nil
+}
+
+func loadV9Index(path string) (*v9Index, error) {
+	data, err := v9Dataset.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	var roots []v9Node
+	if err := json.Unmarshal(data, &roots); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	idx := &v9Index{exact: make(map[string]v9Entity)}
+	var walk func(n v9Node, parent string)
+	walk = func(n v9Node, parent string) {
+		e := v9Entity{id: n.ID, name: n.Name}
+		if parent != "" {
+			e.id = parent + "/" + n.ID
+		}
+		for _, name := range append([]string{n.Name}, n.Aliases...) {
+			key := v9Key(name)
+			if _, dup := idx.exact[key]; dup || key == "" {
+				continue
+			}
+			idx.exact[key] = e
+			if utf8.RuneCountInString(strings.ReplaceAll(key, " ", "")) >= 3 {
+				idx.aliases = append(idx.aliases, v9Alias{tokens: strings.Fields(key), entity: e, depth: strings.Count(e.id, "/")})
+			}
+		}
+		for _, child := range n.Children {
+			walk(child, e.id)
+		}
+	}
+	for _, root := range roots {
+		walk(root, "")
+	}
+	sort.SliceStable(idx.aliases, func(i, j int) bool {
+		return len(idx.aliases[i].tokens) > len(idx.aliases[j].tokens)
+	})
+	return idx, nil
+}
+
+// find returns the entity raw names outright, else the most specific entity
+// mentioned in it. Longer aliases claim their words first, then the deepest
+// entity wins, then the longest alias, then the earliest.
+func (idx *v9Index) find(raw string) (v9Entity, bool) {
+	key := v9Key(raw)
+	if key == "" {
+		return v9Entity{}, false
+	}
+	if e, ok := idx.exact[key]; ok {
+		return e, true
+	}
+
+	tokens := strings.Fields(key)
+	claimed := make([]bool, len(tokens))
+	var best *v9Alias
+	bestAt := 0
+	for i := range idx.aliases {
+		a := &idx.aliases[i]
+		at := -1
+	search:
+		for p := 0; p+len(a.tokens) <= len(tokens); p++ {
+			for j, t := range a.tokens {
+				if claimed[p+j] || tokens[p+j] != t {
+					continue search
+				}
+			}
+			at = p
+			break
+		}
+		if at < 0 {
+			continue
+		}
+		for j := range a.tokens {
+			claimed[at+j] = true
+		}
+		if best == nil || a.depth > best.depth ||
+			(a.depth == best.depth && len(a.tokens) > len(best.tokens)) ||
+			(a.depth == best.depth && len(a.tokens) == len(best.tokens) && at < bestAt) {
+			best, bestAt = a, at
+		}
+	}
+	if best == nil {
+		return v9Entity{}, false
+	}
+	return best.entity, true
+}
+
+var (
+	v9OfAbbrevRegex = regexp.MustCompile(`(?i)\b([mdo])/o\b`)
+	v9OfAbbrevs     = map[string]string{"m": " ministry of ", "d": " department of ", "o": " office of "}
+	v9Synonyms      = map[string]string{
+		"govt":         "government",
+		"dept":         "department",
+		"deptt":        "department",
+		"it":           "information technology",
+		"center":       "centre",
+		"organization": "organisation",
+		"the":          "",
+	}
+)
+
+// v9Key is entity.Key as released with migration 9.
+func v9Key(s string) string {
+	s = strings.ToLower(s)
+	s = v9OfAbbrevRegex.ReplaceAllStringFunc(s, func(m string) string {
+		return v9OfAbbrevs[m[:1]]
+	})
+	s = strings.ReplaceAll(s, "&", " and ")
+	s = strings.ReplaceAll(s, "\u093c", "")
+
+	words := strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && !unicode.IsMark(r)
+	})
+	out := words[:0]
+	for _, w := range words {
+		if syn, ok := v9Synonyms[w]; ok {
+			if syn == "" {
+				continue
+			}
+			w = syn
+		}
+		out = append(out, w)
+	}
+	return strings.Join(out, " ")
+}
+
+// rekeyJobs moves every job to the ID of its canonical URL (v6JobID), which
+// replaced hashing the raw URL. Jobs whose URLs canonicalise to the
+// same ID are merged into one row — the one whose ID is already canonical,
+// else the most recently seen — which keeps the earliest first-seen time and
+// takes over the others' history.
+func rekeyJobs(tx *sql.Tx) error {
+	rows, err := tx.Query(`SELECT id, url, first_seen_at FROM jobs ORDER BY last_seen_at DESC, id`)
+	if err != nil {
+		return fmt.Errorf("failed to list jobs for rekeying: %w", err)
+	}
+	type rekey struct {
+		oldID, newID string
+		firstSeen    int64
+	}
+	var jobs []rekey
+	for rows.Next() {
+		var (
+			id        string
+			rawURL    sql.NullString
+			firstSeen sql.NullInt64
+		)
+		if err := rows.Scan(&id, &rawURL, &firstSeen); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to read job for rekeying: %w", err)
+		}
+		newID := id
+		if rawURL.String != "" {
+			newID = v6JobID(rawURL.String)
+		}
+		jobs = append(jobs, rekey{oldID: id, newID: newID, firstSeen: firstSeen.Int64})
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to list jobs for rekeying: %w", err)
+	}
+
+	// Rows whose IDs are already canonical keep them.
+	kept := make(map[string]bool, len(jobs))
+	for _, j := range jobs {
+		if j.oldID == j.newID {
+			kept[j.newID] = true
+		}
+	}
+	for _, j := range jobs {
+		if j.oldID == j.newID {
+			continue
+		}
+		if _, err := tx.Exec(`UPDATE job_history SET job_id = ? WHERE job_id = ?`, j.newID, j.oldID); err != nil {
+			return fmt.Errorf("failed to rekey history of job %s: %w", j.oldID, err)
+		}
+		if kept[j.newID] {
+			// A more recently seen row already holds the ID.
+			if _, err := tx.Exec(`DELETE FROM jobs WHERE id = ?`, j.oldID); err != nil {
+				return fmt.Errorf("failed to merge job %s: %w", j.oldID, err)
+			}
+			_, err := tx.Exec(`UPDATE jobs SET first_seen_at = MIN(first_seen_at, ?) WHERE id = ? AND ? > 0`,
+				j.firstSeen, j.newID, j.firstSeen)
+			if err != nil {
+				return fmt.Errorf("failed to merge job %s: %w", j.oldID, err)
+			}
+			continue
+		}
+		if _, err := tx.Exec(`UPDATE jobs SET id = ? WHERE id = ?`, j.newID, j.oldID); err != nil {
+			return fmt.Errorf("failed to rekey job %s: %w", j.oldID, err)
+		}
+		kept[j.newID] = true
+	}
+	return nil
+}
+
+// v6JobID is jobid.FromURL as released with migration 6. It is copied here
+// so later changes to pkg/jobid do not change what the migration does.
+func v6JobID(rawURL string) string {
+	hash := sha256.Sum256([]byte(v6CanonicalURL(rawURL)))
+	return hex.EncodeToString(hash[:16])
+}
+
+// v6CanonicalURL is jobid.CanonicalURL as released with migration 6.
+func v6CanonicalURL(rawURL string) string {
+	rawURL = strings.TrimSpace(rawURL)
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return rawURL
+	}
+
+	if u.Scheme == "http" || u.Scheme == "https" {
+		u.Scheme = "https"
+	}
+	host := strings.ToLower(u.Hostname())
+	host = strings.TrimPrefix(host, "www.")
+	if port := u.Port(); port != "" && port != "80" && port != "443" {
+		host += ":" + port
+	}
+	u.Host = host
+	u.User = nil
+	u.Fragment = ""
+	u.RawFragment = ""
+
+	if i := strings.Index(strings.ToLower(u.Path), ";jsessionid="); i >= 0 {
+		u.Path = u.Path[:i]
+		u.RawPath = ""
+	}
+	if len(u.Path) > 1 {
+		u.Path = strings.TrimRight(u.Path, "/")
+		u.RawPath = strings.TrimRight(u.RawPath, "/")
+	}
+	if u.Path == "/" {
+		u.Path = ""
+	}
+
+	query := u.Query()
+	for key := range query {
+		lower := strings.ToLower(key)
+		if lower == "fbclid" || lower == "gclid" || lower == "jsessionid" || lower == "phpsessid" ||
+			strings.HasPrefix(lower, "utm_") {
+			query.Del(key)
+		}
+	}
+	u.RawQuery = query.Encode()
+	u.ForceQuery = false
+
+	return u.String()
+}
+
+// migrate applies every migration in list newer than the database's
+// recorded version. The version is tracked in a schema_version table rather
+// than PRAGMA user_version, which the Flutter client's sqflite uses for
+// itself.
+func migrate(db *sql.DB, list []migration) error {
+	_, err := db.Exec(`
+	CREATE TABLE IF NOT EXISTS schema_version (
+		version INTEGER PRIMARY KEY,
+		name TEXT NOT NULL,
+		applied_at INTEGER NOT NULL
+	);
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create schema_version table: %w", err)
+	}
+
+	current, err := schemaVersion(db)
+	if err != nil {
+		return err
+	}
+	if latest := list[len(list)-1].version; current > latest {
+		return fmt.Errorf("%w: database is at version %d, build supports %d", ErrSchemaTooNew, current, latest)
+	}
+
+	for _, m := range list {
+		if m.version <= current {
+			continue
+		}
+		if err := applyMigration(db, m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyMigration runs m and records it in one transaction.
+func applyMigration(db *sql.DB, m migration) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("migration %d (%s): %w", m.version, m.name, err)
+	}
+	defer tx.Rollback()
+
+	if err := m.up(tx); err != nil {
+		return fmt.Errorf("migration %d (%s): %w", m.version, m.name, err)
+	}
+	_, err = tx.Exec(`INSERT INTO schema_version (version, name, applied_at) VALUES (?, ?, ?)`,
+		m.version, m.name, time.Now().Unix())
+	if err != nil {
+		return fmt.Errorf("migration %d (%s): failed to record version: %w", m.version, m.name, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("migration %d (%s): %w", m.version, m.name, err)
+	}
+	return nil
+}
+
+// schemaVersion returns the highest applied migration version, or 0 for a
+// database that has never been migrated.
+func schemaVersion(db *sql.DB) (int, error) {
+	var version sql.NullInt64
+	if err := db.QueryRow(`SELECT MAX(version) FROM schema_version`).Scan(&version); err != nil {
+		return 0, fmt.Errorf("failed to read schema version: %w", err)
+	}
+	return int(version.Int64), nil
+}
+
+// SchemaVersion returns the schema version recorded in the open database.
+func (d *DB) SchemaVersion() (int, error) {
+	return schemaVersion(d.conn)
+}
+
+// addColumns adds each column (given as "name TYPE") to table unless it
+// already exists.
+func addColumns(tx *sql.Tx, table string, columns ...string) error {
+	existing, err := columnNames(tx, table)
+	if err != nil {
+		return err
+	}
+	for _, column := range columns {
+		name := strings.Fields(column)[0]
+		if existing[name] {
+			continue
+		}
+		if _, err := tx.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s;", table, column)); err != nil {
+			return fmt.Errorf("failed to add column %s.%s: %w", table, name, err)
+		}
+	}
+	return nil
+}
+
+// columnNames returns the set of column names of table.
+func columnNames(tx *sql.Tx, table string) (map[string]bool, error) {
+	rows, err := tx.Query(fmt.Sprintf("PRAGMA table_info(%s);", table))
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect table %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	names := make(map[string]bool)
+	for rows.Next() {
+		var (
+			cid, notNull, pk int
+			name, colType    string
+			dflt             sql.NullString
+		)
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &dflt, &pk); err != nil {
+			return nil, fmt.Errorf("failed to inspect table %s: %w", table, err)
+		}
+		names[name] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to inspect table %s: %w", table, err)
+	}
+	return names, nil
+}
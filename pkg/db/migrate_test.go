@@ -0,0 +1,248 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestMigrations_AreSequential(t *testing.T) {
+	for i, m := range migrations {
+		if m.version != i+1 {
+			t.Errorf("migration %q has version %d, want %d", m.name, m.version, i+1)
+		}
+	}
+	if last := migrations[len(migrations)-1].version; last != SchemaVersion {
+		t.Errorf("SchemaVersion is %d but the last migration is %d", SchemaVersion, last)
+	}
+	if MinReaderVersion < 1 || MinReaderVersion > SchemaVersion {
+		t.Errorf("MinReaderVersion %d is outside 1..%d", MinReaderVersion, SchemaVersion)
+	}
+}
+
+func TestInitDB_RecordsSchemaVersion(t *testing.T) {
+	database := openTestDB(t)
+
+	version, err := database.SchemaVersion()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version != SchemaVersion {
+		t.Errorf("expected schema version %d, got %d", SchemaVersion, version)
+	}
+
+	var applied int
+	if err := database.conn.QueryRow(`SELECT COUNT(*) FROM schema_version`).Scan(&applied); err != nil {
+		t.Fatal(err)
+	}
+	if applied != len(migrations) {
+		t.Errorf("expected %d recorded migrations, got %d", len(migrations), applied)
+	}
+}
+
+func TestInitDB_AppliesOnlyPendingMigrations(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jobs.db")
+
+	first, err := InitDB(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first.conn.Close()
+
+	second, err := InitDB(path)
+	if err != nil {
+		t.Fatalf("re-opening a current database failed: %v", err)
+	}
+	defer second.conn.Close()
+
+	var applied int
+	if err := second.conn.QueryRow(`SELECT COUNT(*) FROM schema_version`).Scan(&applied); err != nil {
+		t.Fatal(err)
+	}
+	if applied != len(migrations) {
+		t.Errorf("migrations re-applied: %d rows in schema_version", applied)
+	}
+}
+
+func TestInitDB_RejectsNewerSchema(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jobs.db")
+
+	database, err := InitDB(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = database.conn.Exec(`INSERT INTO schema_version (version, name, applied_at) VALUES (?, 'from the future', 0)`,
+		SchemaVersion+1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	database.conn.Close()
+
+	_, err = InitDB(path)
+	if !errors.Is(err, ErrSchemaTooNew) {
+		t.Errorf("expected ErrSchemaTooNew, got %v", err)
+	}
+}
+
+func TestMigration_FailureRollsBack(t *testing.T) {
+	conn, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "jobs.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	if err := migrate(conn, migrations); err != nil {
+		t.Fatal(err)
+	}
+
+	bad := migration{
+		version: SchemaVersion + 1,
+		name:    "broken",
+		up: func(tx *sql.Tx) error {
+			if err := addColumns(tx, "jobs", "half_done TEXT"); err != nil {
+				return err
+			}
+			_, err := tx.Exec(`ALTER TABLE no_such_table ADD COLUMN x TEXT`)
+			return err
+		},
+	}
+	if err := applyMigration(conn, bad); err == nil {
+		t.Fatal("expected migration error")
+	}
+
+	version, err := schemaVersion(conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version != SchemaVersion {
+		t.Errorf("failed migration was recorded: version %d", version)
+	}
+
+	tx, err := conn.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+	cols, err := columnNames(tx, "jobs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cols["half_done"] {
+		t.Error("column from failed migration was not rolled back")
+	}
+}
+
+func TestMigration_RekeysJobsByCanonicalURL(t *testing.T) {
+	conn, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "jobs.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	// Bring the database to version 5, before duplicate detection.
+	if _, err := conn.Exec(`CREATE TABLE schema_version (version INTEGER PRIMARY KEY, name TEXT NOT NULL, applied_at INTEGER NOT NULL)`); err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range migrations[:5] {
+		if err := applyMigration(conn, m); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The same PDF under two spellings, stored under raw-URL IDs.
+	_, err = conn.Exec(`
+	INSERT INTO jobs (id, title, department, location, posted_date, url, first_seen_at, last_seen_at, status) VALUES
+		('raw-http', 'Clerk', 'NIC', '', 0, 'http://example.gov.in/a.pdf', 1000, 3000, 'active'),
+		('raw-slash', 'Clerk', 'NIC', '', 0, 'https://www.example.gov.in/a.pdf/', 500, 2000, 'active'),
+		('other', 'Driver', 'NIC', '', 0, 'https://example.gov.in/b.pdf', 700, 3000, 'active');
+	INSERT INTO job_history (job_id, field, old_value, new_value, changed_at) VALUES
+		('raw-slash', 'title', 'Clerk (draft)', 'Clerk', 1500);
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := migrate(conn, migrations); err != nil {
+		t.Fatal(err)
+	}
+	database := &DB{conn: conn}
+
+	jobs, err := database.AllJobs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(jobs) != 2 {
+		t.Fatalf("expected duplicates merged into 2 jobs, got %+v", jobs)
+	}
+
+	// The ID migration 6 gives https://example.gov.in/a.pdf, spelled out so
+	// the test does not follow later changes to pkg/jobid.
+	id := "59e4b810fa966a487b75fd2d1174d9a8"
+	merged, err := database.GetJob(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if merged.URL != "http://example.gov.in/a.pdf" || merged.FirstSeenAt != 500 || merged.CanonicalID != id {
+		t.Errorf("unexpected merged job: %+v", merged)
+	}
+
+	history, err := database.JobHistory(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 1 {
+		t.Errorf("expected history moved to the merged job, got %+v", history)
+	}
+}
+
+func TestMigration_NormalisesDepartmentsAndLocations(t *testing.T) {
+	conn, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "jobs.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	// Bring the database to version 8, before normalisation.
+	if _, err := conn.Exec(`CREATE TABLE schema_version (version INTEGER PRIMARY KEY, name TEXT NOT NULL, applied_at INTEGER NOT NULL)`); err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range migrations[:8] {
+		if err := applyMigration(conn, m); err != nil {
+			t.Fatal(err)
+		}
+	}
+	_, err = conn.Exec(`
+	INSERT INTO jobs (id, title, department, location, posted_date, url, first_seen_at, last_seen_at, status) VALUES
+		('a', 'Scientist-B', 'NIC', 'All India', 0, 'https://example.gov.in/a.pdf', 1000, 1000, 'active'),
+		('b', 'Clerk', 'Municipal Corporation', 'Bombay', 0, 'https://example.gov.in/b.pdf', 1000, 1000, 'active'),
+		('c', 'Scientist-B, NIC Patna', 'NIC', 'All India', 0, 'https://example.gov.in/c.pdf', 1000, 1000, 'active');
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := migrate(conn, migrations); err != nil {
+		t.Fatal(err)
+	}
+	database := &DB{conn: conn}
+
+	want := map[string]Job{
+		"a": {Department: "National Informatics Centre", DepartmentID: "meity/nic", Location: "All India", LocationID: "in"},
+		"b": {Department: "Municipal Corporation", Location: "Mumbai", LocationID: "in/mh/mumbai"},
+		// As in the scraper, a city in the title beats "All India".
+		"c": {Department: "National Informatics Centre", DepartmentID: "meity/nic", Location: "Patna", LocationID: "in/br/patna"},
+	}
+	for id, w := range want {
+		got, err := database.GetJob(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Department != w.Department || got.DepartmentID != w.DepartmentID ||
+			got.Location != w.Location || got.LocationID != w.LocationID {
+			t.Errorf("%s: got %q (%q), %q (%q); want %q (%q), %q (%q)", id,
+				got.Department, got.DepartmentID, got.Location, got.LocationID,
+				w.Department, w.DepartmentID, w.Location, w.LocationID)
+		}
+	}
+}
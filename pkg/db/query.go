@@ -0,0 +1,235 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// JobFilter selects jobs for ListJobs. Zero-valued fields do not filter.
+type JobFilter struct {
+	Query      string // Full-text query, as for Search; results are ranked by relevance
+	Department string // Case-insensitive substring of the department
+	Location   string // Case-insensitive substring of the location
+	// Entity IDs (see Job.DepartmentID); each matches the entity and
+	// everything under it, so "in/br" includes "in/br/patna".
+	DepartmentID string
+	LocationID   string
+	Status       string // One of the Status* constants; "" for any status
+	PostedFrom   int64  // Earliest posted_date, Unix seconds, inclusive
+	PostedTo     int64  // Latest posted_date, Unix seconds, inclusive
+	Limit        int    // Maximum number of jobs (default 20)
+	Offset       int    // Jobs to skip, for paging
+}
+
+// ListJobs returns one page of the jobs matching f and the number of
+// matching jobs across all pages. Without a query, jobs are ordered newest
+// posting first.
+func (d *DB) ListJobs(f JobFilter) ([]Job, int, error) {
+	if f.Limit <= 0 {
+		f.Limit = 20
+	}
+
+	from := `FROM jobs j`
+	var (
+		where []string
+		args  []any
+	)
+	order := `j.posted_date DESC, j.id`
+	if strings.TrimSpace(f.Query) != "" {
+		match := buildMatchQuery(f.Query)
+		if match == "" {
+			return []Job{}, 0, nil
+		}
+		from = `FROM jobs_fts JOIN jobs j ON j.rowid = jobs_fts.rowid`
+		where = append(where, `jobs_fts MATCH ?`)
+		args = append(args, match)
+		order = `bm25(jobs_fts, ` + searchWeights + `), j.id`
+	}
+	if f.Department != "" {
+		where = append(where, `j.department LIKE ? ESCAPE '\'`)
+		args = append(args, likeContains(f.Department))
+	}
+	if f.Location != "" {
+		where = append(where, `j.location LIKE ? ESCAPE '\'`)
+		args = append(args, likeContains(f.Location))
+	}
+	if f.DepartmentID != "" {
+		where = append(where, withinEntity("j.department_id"))
+		args = append(args, f.DepartmentID, likePrefix(f.DepartmentID+"/"))
+	}
+	if f.LocationID != "" {
+		where = append(where, withinEntity("j.location_id"))
+		args = append(args, f.LocationID, likePrefix(f.LocationID+"/"))
+	}
+	if f.Status != "" {
+		where = append(where, `j.status = ?`)
+		args = append(args, f.Status)
+	}
+	if f.PostedFrom != 0 {
+		where = append(where, `j.posted_date >= ?`)
+		args = append(args, f.PostedFrom)
+	}
+	if f.PostedTo != 0 {
+		where = append(where, `j.posted_date <= ?`)
+		args = append(args, f.PostedTo)
+	}
+	if len(where) > 0 {
+		from += ` WHERE ` + strings.Join(where, ` AND `)
+	}
+
+	var total int
+	if err := d.conn.QueryRow(`SELECT COUNT(*) `+from, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count jobs: %w", err)
+	}
+
+	rows, err := d.conn.Query(`SELECT `+qualifyColumns("j", jobColumns)+` `+from+
+		` ORDER BY `+order+` LIMIT ? OFFSET ?`, append(args, f.Limit, f.Offset)...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list jobs: %w", err)
+	}
+	defer rows.Close()
+
+	jobs := make([]Job, 0, f.Limit)
+	for rows.Next() {
+		job, err := scanJob(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to read job: %w", err)
+		}
+		jobs = append(jobs, job)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to list jobs: %w", err)
+	}
+	return jobs, total, nil
+}
+
+// NewJobsFilter selects jobs for NewJobs. Zero-valued fields do not filter.
+type NewJobsFilter struct {
+	SeenAfter  int64  // first_seen_at, Unix seconds, exclusive
+	AfterID    string // Also return jobs first seen at SeenAfter with a greater ID, to resume a batch
+	Department string // Case-insensitive substring of the department
+	Location   string // Case-insensitive substring of the location
+	// Entity IDs, matching as in JobFilter.
+	DepartmentID string
+	LocationID   string
+	Limit        int // Maximum number of jobs (default 20)
+}
+
+// NewJobs returns active jobs first seen after f.SeenAfter, oldest first,
+// skipping duplicates linked to another canonical job. Passing the last
+// returned job's FirstSeenAt and ID as the next SeenAfter and AfterID pages
+// through jobs that share a first-seen time, as all jobs of one run do.
+func (d *DB) NewJobs(f NewJobsFilter) ([]Job, error) {
+	if f.Limit <= 0 {
+		f.Limit = 20
+	}
+	query := `SELECT ` + jobColumns + ` FROM jobs
+	WHERE status = ? AND (canonical_id IS NULL OR canonical_id = '' OR canonical_id = id)`
+	args := []any{StatusActive}
+	if f.AfterID != "" {
+		query += ` AND (first_seen_at > ? OR (first_seen_at = ? AND id > ?))`
+		args = append(args, f.SeenAfter, f.SeenAfter, f.AfterID)
+	} else {
+		query += ` AND first_seen_at > ?`
+		args = append(args, f.SeenAfter)
+	}
+	if f.Department != "" {
+		query += ` AND department LIKE ? ESCAPE '\'`
+		args = append(args, likeContains(f.Department))
+	}
+	if f.Location != "" {
+		query += ` AND location LIKE ? ESCAPE '\'`
+		args = append(args, likeContains(f.Location))
+	}
+	if f.DepartmentID != "" {
+		query += ` AND ` + withinEntity("department_id")
+		args = append(args, f.DepartmentID, likePrefix(f.DepartmentID+"/"))
+	}
+	if f.LocationID != "" {
+		query += ` AND ` + withinEntity("location_id")
+		args = append(args, f.LocationID, likePrefix(f.LocationID+"/"))
+	}
+	query += ` ORDER BY first_seen_at, id LIMIT ?`
+	args = append(args, f.Limit)
+
+	rows, err := d.conn.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list new jobs: %w", err)
+	}
+	defer rows.Close()
+
+	var jobs []Job
+	for rows.Next() {
+		job, err := scanJob(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read job: %w", err)
+		}
+		jobs = append(jobs, job)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list new jobs: %w", err)
+	}
+	return jobs, nil
+}
+
+// likeContains returns a LIKE pattern (with \ as the escape character)
+// matching s anywhere in a value. SQLite's LIKE is case-insensitive for
+// ASCII.
+func likeContains(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return "%" + r.Replace(s) + "%"
+}
+
+// likePrefix returns a LIKE pattern (with \ as the escape character)
+// matching values that start with s.
+func likePrefix(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return r.Replace(s) + "%"
+}
+
+// withinEntity returns a condition matching an entity ID column against an
+// ID and its descendants; its arguments are the ID and likePrefix(ID+"/").
+// LIKE is case-insensitive, but entity IDs are lower case.
+func withinEntity(column string) string {
+	return `(` + column + ` = ? OR ` + column + ` LIKE ? ESCAPE '\')`
+}
+
+// readBusyTimeout is the busy_timeout pragma of read-only connections, in
+// milliseconds: longer than a scrape's upsert transaction holds its lock.
+const readBusyTimeout = "busy_timeout(10000)"
+
+// OpenReadOnly opens an existing database for reading only, e.g. for a
+// server alongside the scraper. It never migrates, so the database must
+// already be at SchemaVersion. Reads wait up to readBusyTimeout for a
+// writer holding the database lock instead of failing with SQLITE_BUSY.
+func OpenReadOnly(filepath string) (*DB, error) {
+	conn, err := sql.Open("sqlite", withPragma("file:"+filepath+"?mode=ro", readBusyTimeout))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open db: %w", err)
+	}
+	// Databases from before migrations have no schema_version table: they
+	// are at version 0.
+	var tables int
+	err = conn.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'schema_version'`).Scan(&tables)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to open db: %w", err)
+	}
+	version := 0
+	if tables > 0 {
+		if version, err = schemaVersion(conn); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	switch {
+	case version > SchemaVersion:
+		conn.Close()
+		return nil, fmt.Errorf("%w: database is at version %d, build supports %d", ErrSchemaTooNew, version, SchemaVersion)
+	case version < SchemaVersion:
+		conn.Close()
+		return nil, fmt.Errorf("database is at schema version %d, want %d: run the scraper to migrate it", version, SchemaVersion)
+	}
+	return &DB{conn: conn}, nil
+}
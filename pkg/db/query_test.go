@@ -0,0 +1,175 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func jobIDs(jobs []Job) []string {
+	ids := make([]string, 0, len(jobs))
+	for _, j := range jobs {
+		ids = append(ids, j.ID)
+	}
+	return ids
+}
+
+func seedListJobs(t *testing.T, database *DB) {
+	t.Helper()
+	for _, j := range []Job{
+		{ID: "a", Title: "Junior Engineer", Department: "Staff Selection Commission", DepartmentID: "mopgp/dopt/ssc", Location: "New Delhi", LocationID: "in/dl/new-delhi", PostedDate: 1000},
+		{ID: "b", Title: "Assistant Engineer", Department: "CPWD", Location: "Mumbai", LocationID: "in/mh/mumbai", PostedDate: 3000},
+		{ID: "c", Title: "Clerk", Department: "Staff Selection Commission", DepartmentID: "mopgp/dopt/ssc", Location: "Mumbai", LocationID: "in/mh/mumbai", PostedDate: 2000},
+		{ID: "d", Title: "Driver 100%_match", Department: "NIC", DepartmentID: "meity/nic", Location: "Pune", LocationID: "in/mh/pune", PostedDate: 4000},
+	} {
+		if _, err := database.UpsertJob(j); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := database.conn.Exec(`UPDATE jobs SET status = ? WHERE id = 'd'`, StatusRemoved); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestListJobs_Filters(t *testing.T) {
+	database := openTestDB(t)
+	seedListJobs(t, database)
+
+	tests := []struct {
+		name  string
+		f     JobFilter
+		want  []string
+		total int
+	}{
+		{"newest first", JobFilter{}, []string{"d", "b", "c", "a"}, 4},
+		{"status", JobFilter{Status: StatusActive}, []string{"b", "c", "a"}, 3},
+		{"department substring", JobFilter{Department: "selection"}, []string{"c", "a"}, 2},
+		{"location", JobFilter{Location: "mumbai"}, []string{"b", "c"}, 2},
+		{"date range", JobFilter{PostedFrom: 2000, PostedTo: 3000}, []string{"b", "c"}, 2},
+		{"query ranks by relevance", JobFilter{Query: "engineer"}, []string{"b", "a"}, 2},
+		{"query and filter", JobFilter{Query: "engineer", Location: "delhi"}, []string{"a"}, 1},
+		{"department id includes descendants", JobFilter{DepartmentID: "mopgp"}, []string{"c", "a"}, 2},
+		{"location id", JobFilter{LocationID: "in/mh"}, []string{"d", "b", "c"}, 3},
+		{"location id is not a string prefix", JobFilter{LocationID: "in/m"}, []string{}, 0},
+		{"like wildcards are literal", JobFilter{Location: "%"}, []string{}, 0},
+		{"query without words", JobFilter{Query: "++"}, []string{}, 0},
+		{"paging", JobFilter{Limit: 2, Offset: 1}, []string{"b", "c"}, 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jobs, total, err := database.ListJobs(tt.f)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := jobIDs(jobs); !reflect.DeepEqual(got, tt.want) || total != tt.total {
+				t.Errorf("got %v (total %d), want %v (total %d)", got, total, tt.want, tt.total)
+			}
+		})
+	}
+}
+
+func TestNewJobs(t *testing.T) {
+	database := openTestDB(t)
+	for _, j := range []Job{
+		{ID: "old", Title: "Clerk", Location: "Mumbai", LocationID: "in/mh/mumbai", LastSeenAt: 100},
+		{ID: "b", Title: "Assistant Engineer", Location: "Mumbai", LocationID: "in/mh/mumbai", LastSeenAt: 300},
+		{ID: "a", Title: "Junior Engineer", Location: "New Delhi", LastSeenAt: 200},
+		{ID: "dup", Title: "Junior Engineer", Location: "New Delhi", LastSeenAt: 200},
+		{ID: "c", Title: "Stenographer", Location: "New Delhi", LastSeenAt: 200},
+		{ID: "gone", Title: "Driver", Location: "Pune", LastSeenAt: 400},
+	} {
+		if _, err := database.UpsertJob(j); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := database.conn.Exec(`UPDATE jobs SET canonical_id = 'a' WHERE id = 'dup'`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := database.conn.Exec(`UPDATE jobs SET status = ? WHERE id = 'gone'`, StatusRemoved); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		f    NewJobsFilter
+		want []string
+	}{
+		{"oldest first", NewJobsFilter{SeenAfter: 100}, []string{"a", "c", "b"}},
+		{"resume within a second", NewJobsFilter{SeenAfter: 200, AfterID: "a"}, []string{"c", "b"}},
+		{"filter and limit", NewJobsFilter{Location: "mumbai", Limit: 1}, []string{"old"}},
+		{"location id", NewJobsFilter{SeenAfter: 100, LocationID: "in/mh"}, []string{"b"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jobs, err := database.NewJobs(tt.f)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := jobIDs(jobs); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOpenReadOnly(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jobs.db")
+	database, err := InitDB(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	seedListJobs(t, database)
+	database.conn.Close()
+
+	ro, err := OpenReadOnly(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ro.Close()
+
+	if _, err := ro.GetJob("a"); err != nil {
+		t.Errorf("expected to read job a: %v", err)
+	}
+	if _, err := ro.UpsertJob(Job{ID: "e", Title: "Peon"}); err == nil {
+		t.Error("expected writes to fail on a read-only database")
+	}
+}
+
+func TestOpenReadOnly_RejectsOtherSchemas(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jobs.db")
+	database, err := InitDB(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = database.conn.Exec(`INSERT INTO schema_version (version, name, applied_at) VALUES (?, 'from the future', 0)`,
+		SchemaVersion+1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	database.conn.Close()
+
+	if _, err := OpenReadOnly(path); !errors.Is(err, ErrSchemaTooNew) {
+		t.Errorf("expected ErrSchemaTooNew, got %v", err)
+	}
+	if _, err := OpenReadOnly(filepath.Join(t.TempDir(), "missing.db")); err == nil {
+		t.Error("expected an error for a missing database")
+	}
+
+	// A database from before migrations.
+	legacy := filepath.Join(t.TempDir(), "legacy.db")
+	conn, err := sql.Open("sqlite", legacy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := conn.Exec(`CREATE TABLE jobs (id TEXT PRIMARY KEY, title TEXT)`); err != nil {
+		t.Fatal(err)
+	}
+	conn.Close()
+	_, err = OpenReadOnly(legacy)
+	if err == nil || !strings.Contains(err.Error(), "version 0") || !strings.Contains(err.Error(), "run the scraper") {
+		t.Errorf("expected a version 0 error asking to run the scraper, got %v", err)
+	}
+}
@@ -0,0 +1,108 @@
+package db
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// bm25 column weights for jobs_fts (title, department, location,
+// advert_text): a hit in the title outranks one buried in the PDF text.
+const searchWeights = "10.0, 5.0, 2.0, 1.0"
+
+// SearchOptions narrows a full-text search.
+type SearchOptions struct {
+	Limit           int  // Maximum number of results (default 20)
+	Offset          int  // Results to skip, for paging
+	IncludeInactive bool // Also return expired and removed jobs
+}
+
+// SearchResult is a job matching a search, best match first.
+type SearchResult struct {
+	Job
+	Rank    float64 // bm25 score; lower is better
+	Snippet string  // Matching excerpt with hits wrapped in [ and ]
+}
+
+// Search runs a ranked full-text search over job titles, departments,
+// locations and advertisement text. Every word of query must match, as a
+// prefix ("assist" finds "Assistant"); FTS5 operators in query are treated
+// as plain words. A query without any words returns no results.
+func (d *DB) Search(query string, opts SearchOptions) ([]SearchResult, error) {
+	match := buildMatchQuery(query)
+	if match == "" {
+		return nil, nil
+	}
+	if opts.Limit <= 0 {
+		opts.Limit = 20
+	}
+
+	sqlQuery := `
+	SELECT ` + qualifyColumns("j", jobColumns) + `,
+		bm25(jobs_fts, ` + searchWeights + `) AS rank,
+		snippet(jobs_fts, -1, '[', ']', '…', 12)
+	FROM jobs_fts
+	JOIN jobs j ON j.rowid = jobs_fts.rowid
+	WHERE jobs_fts MATCH ?`
+	args := []any{match}
+	if !opts.IncludeInactive {
+		sqlQuery += ` AND j.status = ?`
+		args = append(args, StatusActive)
+	}
+	sqlQuery += ` ORDER BY rank LIMIT ? OFFSET ?`
+	args = append(args, opts.Limit, opts.Offset)
+
+	rows, err := d.conn.Query(sqlQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search jobs: %w", err)
+	}
+	defer rows.Close()
+
+	results := make([]SearchResult, 0, opts.Limit)
+	for rows.Next() {
+		var r SearchResult
+		job, err := scanJob(rows, &r.Rank, &r.Snippet)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read search result: %w", err)
+		}
+		r.Job = job
+		results = append(results, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to search jobs: %w", err)
+	}
+	return results, nil
+}
+
+// RebuildSearchIndex regenerates jobs_fts from the jobs table. The triggers
+// keep the index current, so this is only needed to repair it or after
+// writes that bypassed them.
+func (d *DB) RebuildSearchIndex() error {
+	if _, err := d.conn.Exec(`INSERT INTO jobs_fts(jobs_fts) VALUES ('rebuild');`); err != nil {
+		return fmt.Errorf("failed to rebuild search index: %w", err)
+	}
+	return nil
+}
+
+// buildMatchQuery turns free text into an FTS5 query that ANDs a prefix
+// match for each word. Words are quoted, so input can never be parsed as
+// FTS5 syntax.
+func buildMatchQuery(query string) string {
+	words := strings.FieldsFunc(query, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && !unicode.IsMark(r)
+	})
+	terms := make([]string, 0, len(words))
+	for _, w := range words {
+		terms = append(terms, `"`+w+`"*`)
+	}
+	return strings.Join(terms, " ")
+}
+
+// qualifyColumns prefixes each column of a comma-separated list with alias.
+func qualifyColumns(alias, columns string) string {
+	parts := strings.Split(columns, ",")
+	for i, p := range parts {
+		parts[i] = alias + "." + strings.TrimSpace(p)
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,134 @@
+package db
+
+import (
+	"testing"
+)
+
+func seedSearchJobs(t *testing.T, database *DB) {
+	t.Helper()
+	for _, j := range []Job{
+		{ID: "sta", Title: "Scientific/Technical Assistant-A", Department: "NIC", Location: "All India",
+			AdvertText: "Total No. of Posts: 45. Candidates must hold a B.Sc. in Computer Science."},
+		{ID: "so", Title: "Section Officer on deputation", Department: "NIC", Location: "New Delhi",
+			AdvertText: "Officers holding analogous posts. Knowledge of computer applications desirable."},
+		{ID: "clerk", Title: "Junior Clerk", Department: "Staff Selection Commission", Location: "Mumbai"},
+		{ID: "old", Title: "Computer Operator", Department: "NIC", Location: "Pune"},
+	} {
+		if _, err := database.UpsertJob(j); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func resultIDs(results []SearchResult) []string {
+	ids := make([]string, 0, len(results))
+	for _, r := range results {
+		ids = append(ids, r.ID)
+	}
+	return ids
+}
+
+func TestSearch_RanksTitleHitsFirst(t *testing.T) {
+	database := openTestDB(t)
+	seedSearchJobs(t, database)
+
+	results, err := database.Search("computer", SearchOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids := resultIDs(results)
+	if len(ids) != 3 || ids[0] != "old" {
+		t.Fatalf("expected title match 'old' first of 3 results, got %v", ids)
+	}
+	if results[1].Snippet == "" || results[1].Rank < results[0].Rank {
+		t.Errorf("expected ranked results with snippets, got %+v", results[1])
+	}
+}
+
+func TestSearch_PrefixAndAllWords(t *testing.T) {
+	database := openTestDB(t)
+	seedSearchJobs(t, database)
+
+	results, err := database.Search("assist NIC", SearchOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ids := resultIDs(results); len(ids) != 1 || ids[0] != "sta" {
+		t.Errorf("expected only 'sta', got %v", ids)
+	}
+
+	results, err = database.Search("mumbai", SearchOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ids := resultIDs(results); len(ids) != 1 || ids[0] != "clerk" {
+		t.Errorf("expected location match 'clerk', got %v", ids)
+	}
+}
+
+func TestSearch_TracksUpdatesAndStatus(t *testing.T) {
+	database := openTestDB(t)
+	seedSearchJobs(t, database)
+
+	// INSERT OR REPLACE must drop the old title from the index.
+	if _, err := database.UpsertJob(Job{ID: "clerk", Title: "Senior Clerk", Department: "SSC", Location: "Mumbai"}); err != nil {
+		t.Fatal(err)
+	}
+	if results, _ := database.Search("junior", SearchOptions{}); len(results) != 0 {
+		t.Errorf("stale title still indexed: %v", resultIDs(results))
+	}
+	if results, _ := database.Search("senior", SearchOptions{}); len(results) != 1 {
+		t.Errorf("new title not indexed: %v", resultIDs(results))
+	}
+	if _, err := database.conn.Exec(`INSERT INTO jobs_fts(jobs_fts, rank) VALUES ('integrity-check', 1)`); err != nil {
+		t.Errorf("index out of sync after update: %v", err)
+	}
+
+	if _, err := database.conn.Exec(`UPDATE jobs SET status = ? WHERE id = 'old'`, StatusRemoved); err != nil {
+		t.Fatal(err)
+	}
+	if results, _ := database.Search("computer", SearchOptions{}); len(results) != 2 {
+		t.Errorf("expected removed job to be hidden, got %v", resultIDs(results))
+	}
+	if results, _ := database.Search("computer", SearchOptions{IncludeInactive: true}); len(results) != 3 {
+		t.Errorf("expected removed job with IncludeInactive, got %v", resultIDs(results))
+	}
+}
+
+func TestSearch_IgnoresQuerySyntax(t *testing.T) {
+	database := openTestDB(t)
+	seedSearchJobs(t, database)
+
+	for _, q := range []string{`"clerk`, `clerk OR`, `NEAR(clerk`, `title:clerk`, `*`, ``} {
+		if _, err := database.Search(q, SearchOptions{}); err != nil {
+			t.Errorf("Search(%q) returned error: %v", q, err)
+		}
+	}
+}
+
+func TestRebuildSearchIndex(t *testing.T) {
+	database := openTestDB(t)
+	seedSearchJobs(t, database)
+
+	if err := database.RebuildSearchIndex(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := database.conn.Exec(`INSERT INTO jobs_fts(jobs_fts, rank) VALUES ('integrity-check', 1)`); err != nil {
+		t.Errorf("index inconsistent after rebuild: %v", err)
+	}
+}
+
+func TestBuildMatchQuery(t *testing.T) {
+	tests := map[string]string{
+		"data entry":   `"data"* "entry"*`,
+		`"clerk" OR x`: `"clerk"* "OR"* "x"*`,
+		"B.Sc.":        `"B"* "Sc"*`,
+		"सहायक भर्ती":  `"सहायक"* "भर्ती"*`,
+		"  -- ":        "",
+	}
+	for in, want := range tests {
+		if got := buildMatchQuery(in); got != want {
+			t.Errorf("buildMatchQuery(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -1,13 +1,46 @@
 package db
 
 import (
+	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"strings"
+	"time"
 
 	_ "modernc.org/sqlite" // Pure Go SQLite driver
 )
 
+// Job lifecycle states stored in jobs.status.
+const (
+	StatusActive  = "active"  // Listed in the latest scrape of its source
+	StatusExpired = "expired" // Closing date has passed
+	StatusRemoved = "removed" // No longer listed by its source
+)
+
+// Application modes stored in jobs.application_mode.
+const (
+	ApplicationOnline  = "online"
+	ApplicationOffline = "offline"
+	ApplicationBoth    = "both"
+)
+
+// Closing date confidences stored in jobs.closing_date_confidence, as
+// written by pkg/dates.
+const (
+	ConfidenceHigh   = "high"   // Stated with a four-digit year
+	ConfidenceMedium = "medium" // Stated with a two-digit year
+	ConfidenceLow    = "low"    // Inferred, e.g. counted from the posting date
+)
+
+// Attachment is a document linked from a job listing.
+type Attachment struct {
+	Title string `json:"title"`
+	URL   string `json:"url"`
+}
+
 // Job represents the job structure for the database.
 type Job struct {
 	ID         string
@@ -16,6 +49,44 @@ type Job struct {
 	Location   string
 	PostedDate int64 // Unix timestamp
 	URL        string
+
+	// Advertisement details, filled when the linked PDF could be read.
+	Vacancies   int
+	PayLevel    string
+	AgeLimit    string
+	ClosingDate string // YYYY-MM-DD
+	AdvertText  string
+	// ClosingDateConfidence is how ClosingDate was read: "high", "medium"
+	// or ConfidenceLow for an inferred date; "" when unknown.
+	ClosingDateConfidence string
+
+	// Structured posting details. Qualifications and ages come from the
+	// advertisement; the advertisement number and attachments from the
+	// listing.
+	AdvertisementNo string
+	Qualifications  []string
+	PayScale        string // As printed, e.g. "Rs. 35,400-1,12,400"
+	MinAge          int    // Years; 0 when not stated
+	MaxAge          int    // Years; 0 when not stated
+	ApplicationMode string // One of the Application* constants; "" when not stated
+	Attachments     []Attachment
+
+	// Normalised department and location: pkg/entity IDs such as
+	// "meity/nic" or "in/br/patna"; "" when the name is not in its dataset.
+	DepartmentID string
+	LocationID   string
+
+	// Lifecycle tracking. UpsertJobs keeps the stored FirstSeenAt and sets
+	// LastSeenAt (default: now) and Status to active.
+	Source      string // Name of the source the job was scraped from
+	FirstSeenAt int64  // Unix timestamp
+	LastSeenAt  int64  // Unix timestamp
+	Status      string
+
+	// Duplicate detection. UpsertJobs links jobs sharing a Fingerprint under
+	// the CanonicalID of the earliest seen of them.
+	Fingerprint string // Content hash from jobid.Fingerprint; "" when unknown
+	CanonicalID string // ID of the canonical job; the job's own ID if unique
 }
 
 // DB wraps the sql.DB connection.
@@ -23,9 +94,14 @@ type DB struct {
 	conn *sql.DB
 }
 
-// InitDB initializes the SQLite database and creates the jobs table if it doesn't exist.
+// InitDB opens the SQLite database and brings its schema up to date by
+// applying any pending migrations (see migrate.go).
 func InitDB(filepath string) (*DB, error) {
-	db, err := sql.Open("sqlite", filepath)
+	// REPLACE conflict resolution only fires delete triggers with
+	// recursive_triggers on, and the full-text index depends on them. Upserts
+	// no longer replace rows, but manual repairs might. DSN pragmas apply to
+	// every pooled connection.
+	db, err := sql.Open("sqlite", withPragma(filepath, "recursive_triggers(1)"))
 	if err != nil {
 		return nil, fmt.Errorf("failed to open db: %w", err)
 	}
@@ -35,37 +111,367 @@ func InitDB(filepath string) (*DB, error) {
 	// However, user requested portability, so let's stick to standard journal for now unless performance dictates otherwise.
 	// Actually, user requested "PRAGMA journal_mode = DELETE" at the end. We'll set that in Optimize().
 
-	createTableSQL := `
-	CREATE TABLE IF NOT EXISTS jobs (
-		id TEXT PRIMARY KEY,
-		title TEXT,
-		department TEXT,
-		location TEXT,
-		posted_date INTEGER,
-		url TEXT
-	);
+	if err := migrate(db, migrations); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &DB{conn: db}, nil
+}
+
+// withPragma appends a modernc.org/sqlite _pragma parameter to a DSN.
+func withPragma(dsn, pragma string) string {
+	sep := "?"
+	if strings.Contains(dsn, "?") {
+		sep = "&"
+	}
+	return dsn + sep + "_pragma=" + pragma
+}
+
+// upsertJobSQL inserts a new job or updates the stored one in place. The
+// row keeps its rowid and first_seen_at; mergeJob has already resolved every
+// other value.
+const upsertJobSQL = `
+	INSERT INTO jobs (id, title, department, location, posted_date, url,
+		vacancies, pay_level, age_limit, closing_date, advert_text,
+		source, first_seen_at, last_seen_at, status, fingerprint, canonical_id,
+		advertisement_no, qualifications, pay_scale, min_age, max_age,
+		application_mode, attachments, department_id, location_id,
+		closing_date_confidence)
+	VALUES (:id, :title, :department, :location, :posted, :url,
+		:vacancies, :pay_level, :age_limit, :closing_date, :advert_text,
+		:source, :first_seen, :seen, :status, :fingerprint, :canonical_id,
+		:advertisement_no, :qualifications, :pay_scale, :min_age, :max_age,
+		:application_mode, :attachments, :department_id, :location_id,
+		:closing_date_confidence)
+	ON CONFLICT(id) DO UPDATE SET
+		title = excluded.title,
+		department = excluded.department,
+		location = excluded.location,
+		posted_date = excluded.posted_date,
+		url = excluded.url,
+		vacancies = excluded.vacancies,
+		pay_level = excluded.pay_level,
+		age_limit = excluded.age_limit,
+		closing_date = excluded.closing_date,
+		advert_text = excluded.advert_text,
+		source = excluded.source,
+		last_seen_at = excluded.last_seen_at,
+		status = excluded.status,
+		fingerprint = excluded.fingerprint,
+		canonical_id = excluded.canonical_id,
+		advertisement_no = excluded.advertisement_no,
+		qualifications = excluded.qualifications,
+		pay_scale = excluded.pay_scale,
+		min_age = excluded.min_age,
+		max_age = excluded.max_age,
+		application_mode = excluded.application_mode,
+		attachments = excluded.attachments,
+		department_id = excluded.department_id,
+		location_id = excluded.location_id,
+		closing_date_confidence = excluded.closing_date_confidence
 	`
 
-	_, err = db.Exec(createTableSQL)
+// canonicalIDSQL finds the canonical job of another job sharing a
+// fingerprint: the earliest seen, so the link is stable across runs.
+const canonicalIDSQL = `
+	SELECT COALESCE(NULLIF(canonical_id, ''), id) FROM jobs
+	WHERE fingerprint = ? AND id != ?
+	ORDER BY first_seen_at, id LIMIT 1
+	`
+
+// insertChangeSQL records one field change in job_history.
+const insertChangeSQL = `
+	INSERT INTO job_history (job_id, field, old_value, new_value, changed_at)
+	VALUES (?, ?, ?, ?, ?)
+	`
+
+// UpsertJob inserts a new job or updates an existing one on conflict.
+// It is UpsertJobs for a single job.
+func (d *DB) UpsertJob(job Job) (UpsertResult, error) {
+	summary, err := d.UpsertJobs(context.Background(), []Job{job})
+	switch {
+	case err != nil:
+		return Unchanged, err
+	case summary.Inserted > 0:
+		return Inserted, nil
+	case summary.Updated > 0:
+		return Updated, nil
+	}
+	return Unchanged, nil
+}
+
+// UpsertJobs writes jobs in a single transaction using prepared statements.
+// Either every job is written or, on any error or cancellation of ctx, none
+// is. The summary counts how many jobs were inserted, updated or unchanged.
+//
+// Each job is compared with its stored row; changed fields are recorded in
+// job_history (see JobHistory). The first-seen time of an existing row is
+// preserved, and each job is marked active as of job.LastSeenAt (now when
+// zero). See mergeJob for which values the stored row keeps.
+func (d *DB) UpsertJobs(ctx context.Context, jobs []Job) (UpsertSummary, error) {
+	var summary UpsertSummary
+
+	tx, err := d.conn.BeginTx(ctx, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create table: %w", err)
+		return UpsertSummary{}, fmt.Errorf("failed to begin upsert: %w", err)
 	}
+	defer tx.Rollback()
 
-	return &DB{conn: db}, nil
+	getStmt, err := tx.PrepareContext(ctx, `SELECT `+jobColumns+` FROM jobs WHERE id = ?`)
+	if err != nil {
+		return UpsertSummary{}, fmt.Errorf("failed to prepare upsert: %w", err)
+	}
+	defer getStmt.Close()
+
+	upsertStmt, err := tx.PrepareContext(ctx, upsertJobSQL)
+	if err != nil {
+		return UpsertSummary{}, fmt.Errorf("failed to prepare upsert: %w", err)
+	}
+	defer upsertStmt.Close()
+
+	historyStmt, err := tx.PrepareContext(ctx, insertChangeSQL)
+	if err != nil {
+		return UpsertSummary{}, fmt.Errorf("failed to prepare upsert: %w", err)
+	}
+	defer historyStmt.Close()
+
+	canonicalStmt, err := tx.PrepareContext(ctx, canonicalIDSQL)
+	if err != nil {
+		return UpsertSummary{}, fmt.Errorf("failed to prepare upsert: %w", err)
+	}
+	defer canonicalStmt.Close()
+
+	now := time.Now().Unix()
+	for _, job := range jobs {
+		seenAt := job.LastSeenAt
+		if seenAt == 0 {
+			seenAt = now
+		}
+
+		var stored *Job
+		existing, err := scanJob(getStmt.QueryRowContext(ctx, job.ID))
+		switch {
+		case err == nil:
+			stored = &existing
+		case !errors.Is(err, sql.ErrNoRows):
+			return UpsertSummary{}, fmt.Errorf("failed to read job %s: %w", job.ID, err)
+		}
+
+		merged, changes := mergeJob(stored, job, seenAt)
+		merged.CanonicalID = merged.ID
+		if merged.Fingerprint != "" {
+			err := canonicalStmt.QueryRowContext(ctx, merged.Fingerprint, merged.ID).Scan(&merged.CanonicalID)
+			if err != nil && !errors.Is(err, sql.ErrNoRows) {
+				return UpsertSummary{}, fmt.Errorf("failed to find duplicates of job %s: %w", job.ID, err)
+			}
+		}
+
+		_, err = upsertStmt.ExecContext(ctx,
+			sql.Named("id", merged.ID),
+			sql.Named("title", merged.Title),
+			sql.Named("department", merged.Department),
+			sql.Named("location", merged.Location),
+			sql.Named("posted", merged.PostedDate),
+			sql.Named("url", merged.URL),
+			sql.Named("vacancies", merged.Vacancies),
+			sql.Named("pay_level", merged.PayLevel),
+			sql.Named("age_limit", merged.AgeLimit),
+			sql.Named("closing_date", merged.ClosingDate),
+			sql.Named("advert_text", merged.AdvertText),
+			sql.Named("source", merged.Source),
+			sql.Named("first_seen", merged.FirstSeenAt),
+			sql.Named("seen", merged.LastSeenAt),
+			sql.Named("status", merged.Status),
+			sql.Named("fingerprint", merged.Fingerprint),
+			sql.Named("canonical_id", merged.CanonicalID),
+			sql.Named("advertisement_no", merged.AdvertisementNo),
+			sql.Named("qualifications", EncodeList(merged.Qualifications)),
+			sql.Named("pay_scale", merged.PayScale),
+			sql.Named("min_age", merged.MinAge),
+			sql.Named("max_age", merged.MaxAge),
+			sql.Named("application_mode", merged.ApplicationMode),
+			sql.Named("attachments", EncodeAttachments(merged.Attachments)),
+			sql.Named("department_id", merged.DepartmentID),
+			sql.Named("location_id", merged.LocationID),
+			sql.Named("closing_date_confidence", merged.ClosingDateConfidence),
+		)
+		if err != nil {
+			return UpsertSummary{}, fmt.Errorf("failed to upsert job %s: %w", job.ID, err)
+		}
+
+		for _, c := range changes {
+			if _, err := historyStmt.ExecContext(ctx, c.JobID, c.Field, c.OldValue, c.NewValue, c.ChangedAt); err != nil {
+				return UpsertSummary{}, fmt.Errorf("failed to record history of job %s: %w", job.ID, err)
+			}
+		}
+
+		result := Unchanged
+		switch {
+		case stored == nil:
+			result = Inserted
+		case len(changes) > 0:
+			result = Updated
+		}
+		summary.add(merged.ID, result, changes)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return UpsertSummary{}, fmt.Errorf("failed to commit upsert of %d jobs: %w", len(jobs), err)
+	}
+	return summary, nil
 }
 
-// UpsertJob insterts a new job or updates an existing one on conflict.
-// We use INSERT OR REPLACE which is standard for SQLite upserts on PK.
-func (d *DB) UpsertJob(job Job) error {
-	query := `
-	INSERT OR REPLACE INTO jobs (id, title, department, location, posted_date, url)
-	VALUES (?, ?, ?, ?, ?, ?)
-	`
-	_, err := d.conn.Exec(query, job.ID, job.Title, job.Department, job.Location, job.PostedDate, job.URL)
+// jobColumns lists the jobs columns read by scanJob, in scan order.
+const jobColumns = `id, title, department, location, posted_date, url,
+	vacancies, pay_level, age_limit, closing_date, advert_text,
+	source, first_seen_at, last_seen_at, status, fingerprint, canonical_id,
+	advertisement_no, qualifications, pay_scale, min_age, max_age,
+	application_mode, attachments, department_id, location_id,
+	closing_date_confidence`
+
+// rowScanner is implemented by *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanJob reads one row selected with jobColumns, plus any extra trailing
+// columns into extra.
+func scanJob(row rowScanner, extra ...any) (Job, error) {
+	var (
+		job                                       Job
+		vacancies, minAge, maxAge                 sql.NullInt64
+		payLevel, ageLimit, closing, text, source sql.NullString
+		fingerprint, canonicalID                  sql.NullString
+		advtNo, qualifications, payScale          sql.NullString
+		mode, attachments                         sql.NullString
+		departmentID, locationID                  sql.NullString
+		closingConfidence                         sql.NullString
+	)
+	dest := []any{&job.ID, &job.Title, &job.Department, &job.Location, &job.PostedDate, &job.URL,
+		&vacancies, &payLevel, &ageLimit, &closing, &text,
+		&source, &job.FirstSeenAt, &job.LastSeenAt, &job.Status,
+		&fingerprint, &canonicalID,
+		&advtNo, &qualifications, &payScale, &minAge, &maxAge,
+		&mode, &attachments, &departmentID, &locationID,
+		&closingConfidence}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return Job{}, err
+	}
+	job.Vacancies = int(vacancies.Int64)
+	job.PayLevel = payLevel.String
+	job.AgeLimit = ageLimit.String
+	job.ClosingDate = closing.String
+	job.AdvertText = text.String
+	job.Source = source.String
+	job.Fingerprint = fingerprint.String
+	job.CanonicalID = canonicalID.String
+	job.AdvertisementNo = advtNo.String
+	job.PayScale = payScale.String
+	job.MinAge = int(minAge.Int64)
+	job.MaxAge = int(maxAge.Int64)
+	job.ApplicationMode = mode.String
+	job.DepartmentID = departmentID.String
+	job.LocationID = locationID.String
+	job.ClosingDateConfidence = closingConfidence.String
+
+	// Rows written before schema 7 hold NULL; empty lists read as nil.
+	if qualifications.String != "" {
+		list, err := DecodeList(qualifications.String)
+		if err != nil {
+			return Job{}, fmt.Errorf("invalid qualifications of job %s: %w", job.ID, err)
+		}
+		if len(list) > 0 {
+			job.Qualifications = list
+		}
+	}
+	var err error
+	if job.Attachments, err = DecodeAttachments(attachments.String); err != nil {
+		return Job{}, fmt.Errorf("invalid attachments of job %s: %w", job.ID, err)
+	}
+	return job, nil
+}
+
+// EncodeAttachments stores attachments as a JSON array, as in
+// jobs.attachments; nil becomes [].
+func EncodeAttachments(list []Attachment) string {
+	if len(list) == 0 {
+		return "[]"
+	}
+	data, _ := json.Marshal(list) // Attachment always encodes
+	return string(data)
+}
+
+// DecodeAttachments parses jobs.attachments; "" and [] give nil.
+func DecodeAttachments(s string) ([]Attachment, error) {
+	if s == "" {
+		return nil, nil
+	}
+	var list []Attachment
+	if err := json.Unmarshal([]byte(s), &list); err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, nil
+	}
+	return list, nil
+}
+
+// GetJob returns the stored job with the given ID, or sql.ErrNoRows.
+func (d *DB) GetJob(id string) (Job, error) {
+	return scanJob(d.conn.QueryRow(`SELECT `+jobColumns+` FROM jobs WHERE id = ?`, id))
+}
+
+// AllJobs returns every stored job, whatever its status, ordered by ID.
+func (d *DB) AllJobs() ([]Job, error) {
+	rows, err := d.conn.Query(`SELECT ` + jobColumns + ` FROM jobs ORDER BY id`)
 	if err != nil {
-		return fmt.Errorf("failed to upsert job %s: %w", job.ID, err)
+		return nil, fmt.Errorf("failed to list jobs: %w", err)
 	}
-	return nil
+	defer rows.Close()
+
+	jobs := make([]Job, 0, 256)
+	for rows.Next() {
+		job, err := scanJob(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read job: %w", err)
+		}
+		jobs = append(jobs, job)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list jobs: %w", err)
+	}
+	return jobs, nil
+}
+
+// MarkRemoved marks every job of source that was not seen at or after seenAt
+// as removed. Call it only after a fully successful scrape of that source,
+// passing the LastSeenAt used for the run's upserts.
+// Returns the number of jobs newly marked removed.
+func (d *DB) MarkRemoved(source string, seenAt int64) (int64, error) {
+	res, err := d.conn.Exec(`
+	UPDATE jobs SET status = ?
+	WHERE source = ? AND last_seen_at < ? AND status != ?
+	`, StatusRemoved, source, seenAt, StatusRemoved)
+	if err != nil {
+		return 0, fmt.Errorf("failed to mark removed jobs for %s: %w", source, err)
+	}
+	return res.RowsAffected()
+}
+
+// MarkExpired marks active jobs whose closing date is before today
+// (YYYY-MM-DD) as expired, unless the date was inferred (ConfidenceLow).
+// Returns the number of jobs marked.
+func (d *DB) MarkExpired(today string) (int64, error) {
+	res, err := d.conn.Exec(`
+	UPDATE jobs SET status = ?
+	WHERE status = ? AND closing_date IS NOT NULL AND closing_date != '' AND closing_date < ?
+		AND COALESCE(closing_date_confidence, '') != ?
+	`, StatusExpired, StatusActive, today, ConfidenceLow)
+	if err != nil {
+		return 0, fmt.Errorf("failed to mark expired jobs: %w", err)
+	}
+	return res.RowsAffected()
 }
 
 // Optimize runs VACUUM and sets journal_mode to DELETE to ensure the file is as small and portable as possible.
@@ -73,6 +479,11 @@ func (d *DB) UpsertJob(job Job) error {
 func (d *DB) OptimizeAndClose() error {
 	log.Println("Optimizing database...")
 
+	// Merge the full-text index into a single b-tree before compacting.
+	if _, err := d.conn.Exec("INSERT INTO jobs_fts(jobs_fts) VALUES ('optimize');"); err != nil {
+		return fmt.Errorf("failed to optimize search index: %w", err)
+	}
+
 	// VACUUM to reclaim space
 	_, err := d.conn.Exec("VACUUM;")
 	if err != nil {
@@ -87,3 +498,9 @@ func (d *DB) OptimizeAndClose() error {
 
 	return d.conn.Close()
 }
+
+// Close closes the connection without compacting, for readers that must
+// not rewrite the file.
+func (d *DB) Close() error {
+	return d.conn.Close()
+}
@@ -0,0 +1,176 @@
+package db
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/entreya/job-aggregation/pkg/jobid"
+)
+
+func TestInitDB_UpgradesExistingTable(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jobs.db")
+
+	// A database written before advertisement parsing existed.
+	legacy, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := legacy.Exec(`CREATE TABLE jobs (id TEXT PRIMARY KEY, title TEXT, department TEXT,
+		location TEXT, posted_date INTEGER, url TEXT)`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := legacy.Exec(`INSERT INTO jobs VALUES ('old', 'Clerk', 'NIC', 'Delhi', 1, 'https://x/a.pdf')`); err != nil {
+		t.Fatal(err)
+	}
+	legacy.Close()
+
+	database, err := InitDB(path)
+	if err != nil {
+		t.Fatalf("InitDB failed on legacy table: %v", err)
+	}
+	defer database.conn.Close()
+
+	job := Job{ID: "new", Title: "STA", URL: "https://x/b.pdf", Vacancies: 45, PayLevel: "Level 6",
+		AgeLimit: "18-30 years", ClosingDate: "2026-03-02", AdvertText: "Total No. of Posts: 45"}
+	if _, err := database.UpsertJob(job); err != nil {
+		t.Fatalf("UpsertJob failed: %v", err)
+	}
+
+	var vacancies int
+	var payLevel, closing string
+	row := database.conn.QueryRow(`SELECT vacancies, pay_level, closing_date FROM jobs WHERE id = 'new'`)
+	if err := row.Scan(&vacancies, &payLevel, &closing); err != nil {
+		t.Fatal(err)
+	}
+	if vacancies != 45 || payLevel != "Level 6" || closing != "2026-03-02" {
+		t.Errorf("unexpected advert columns: %d %q %q", vacancies, payLevel, closing)
+	}
+
+	// Legacy IDs are rekeyed to the hash of the canonical URL.
+	old, err := database.GetJob(jobid.FromURL("https://x/a.pdf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if old.FirstSeenAt != 1 || old.Source != "nic" || old.Status != StatusActive {
+		t.Errorf("legacy row not backfilled: %+v", old)
+	}
+
+	// Re-opening must not try to add the columns again.
+	database.conn.Close()
+	again, err := InitDB(path)
+	if err != nil {
+		t.Fatalf("second InitDB failed: %v", err)
+	}
+	again.conn.Close()
+}
+
+func openTestDB(t *testing.T) *DB {
+	t.Helper()
+	database, err := InitDB(filepath.Join(t.TempDir(), "jobs.db"))
+	if err != nil {
+		t.Fatalf("InitDB failed: %v", err)
+	}
+	t.Cleanup(func() { database.conn.Close() })
+	return database
+}
+
+func TestUpsertJob_KeepsFirstSeen(t *testing.T) {
+	database := openTestDB(t)
+
+	job := Job{ID: "a", Title: "Clerk", URL: "https://x/a.pdf", Source: "nic", LastSeenAt: 1000}
+	if _, err := database.UpsertJob(job); err != nil {
+		t.Fatal(err)
+	}
+	job.Title = "Clerk (corrected)"
+	job.LastSeenAt = 2000
+	if _, err := database.UpsertJob(job); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := database.GetJob("a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.FirstSeenAt != 1000 || got.LastSeenAt != 2000 {
+		t.Errorf("expected first/last seen 1000/2000, got %d/%d", got.FirstSeenAt, got.LastSeenAt)
+	}
+	if got.PostedDate != 1000 {
+		t.Errorf("expected posted date to fall back to first seen, got %d", got.PostedDate)
+	}
+	if got.Title != "Clerk (corrected)" || got.Status != StatusActive {
+		t.Errorf("unexpected row: %+v", got)
+	}
+}
+
+func TestMarkRemoved_OnlyAffectsUnseenJobsOfSource(t *testing.T) {
+	database := openTestDB(t)
+
+	for _, j := range []Job{
+		{ID: "gone", Title: "Old", Source: "nic", LastSeenAt: 1000},
+		{ID: "kept", Title: "Still listed", Source: "nic", LastSeenAt: 1000},
+		{ID: "other", Title: "SSC post", Source: "ssc", LastSeenAt: 1000},
+	} {
+		if _, err := database.UpsertJob(j); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Next run: only "kept" is listed again by nic.
+	if _, err := database.UpsertJob(Job{ID: "kept", Title: "Still listed", Source: "nic", LastSeenAt: 2000}); err != nil {
+		t.Fatal(err)
+	}
+	n, err := database.MarkRemoved("nic", 2000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 {
+		t.Errorf("expected 1 job marked removed, got %d", n)
+	}
+
+	for id, want := range map[string]string{"gone": StatusRemoved, "kept": StatusActive, "other": StatusActive} {
+		got, err := database.GetJob(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Status != want {
+			t.Errorf("job %s: expected status %q, got %q", id, want, got.Status)
+		}
+	}
+
+	// A removed job that is listed again becomes active.
+	if _, err := database.UpsertJob(Job{ID: "gone", Title: "Old", Source: "nic", LastSeenAt: 3000}); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := database.GetJob("gone"); got.Status != StatusActive || got.FirstSeenAt != 1000 {
+		t.Errorf("expected relisted job active with original first seen, got %+v", got)
+	}
+}
+
+func TestMarkExpired(t *testing.T) {
+	database := openTestDB(t)
+
+	for _, j := range []Job{
+		{ID: "past", Title: "A", ClosingDate: "2026-03-01"},
+		{ID: "today", Title: "B", ClosingDate: "2026-03-15"},
+		{ID: "undated", Title: "C"},
+	} {
+		if _, err := database.UpsertJob(j); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	n, err := database.MarkExpired("2026-03-15")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 {
+		t.Errorf("expected 1 job expired, got %d", n)
+	}
+	if got, _ := database.GetJob("past"); got.Status != StatusExpired {
+		t.Errorf("expected past job expired, got %q", got.Status)
+	}
+	if got, _ := database.GetJob("today"); got.Status != StatusActive {
+		t.Errorf("job closing today should stay active, got %q", got.Status)
+	}
+}
@@ -0,0 +1,29 @@
+package db
+
+import "context"
+
+// Store is the job storage used by the scrape pipeline. DB is the SQLite
+// implementation shipped to clients; MemStore is an in-memory stand-in for
+// tests.
+type Store interface {
+	// UpsertJobs writes jobs atomically: all of them or, on error, none.
+	// Changed fields are recorded as history.
+	UpsertJobs(ctx context.Context, jobs []Job) (UpsertSummary, error)
+	// GetJob returns the job with the given ID, or sql.ErrNoRows.
+	GetJob(id string) (Job, error)
+	// AllJobs returns every stored job ordered by ID.
+	AllJobs() ([]Job, error)
+	// JobHistory returns the recorded changes to a job, oldest first.
+	JobHistory(id string) ([]Change, error)
+	// MarkRemoved marks jobs of source not seen since seenAt as removed.
+	MarkRemoved(source string, seenAt int64) (int64, error)
+	// MarkExpired marks active jobs closing before today as expired.
+	MarkExpired(today string) (int64, error)
+	// OptimizeAndClose compacts the store and releases it.
+	OptimizeAndClose() error
+}
+
+var (
+	_ Store = (*DB)(nil)
+	_ Store = (*MemStore)(nil)
+)
@@ -0,0 +1,313 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// forEachStore runs fn against both Store implementations so they stay
+// interchangeable.
+func forEachStore(t *testing.T, fn func(t *testing.T, store Store)) {
+	t.Run("sqlite", func(t *testing.T) { fn(t, openTestDB(t)) })
+	t.Run("memory", func(t *testing.T) { fn(t, NewMemStore()) })
+}
+
+func TestStore_UpsertJobsLifecycle(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store Store) {
+		ctx := context.Background()
+
+		batch := []Job{
+			{ID: "a", Title: "Clerk", Source: "nic", LastSeenAt: 1000, ClosingDate: "2026-01-31"},
+			{ID: "b", Title: "Driver", Source: "nic", LastSeenAt: 1000, PostedDate: 500},
+		}
+		if _, err := store.UpsertJobs(ctx, batch); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := store.UpsertJobs(ctx, []Job{{ID: "b", Title: "Driver", Source: "nic", LastSeenAt: 2000}}); err != nil {
+			t.Fatal(err)
+		}
+
+		b, err := store.GetJob("b")
+		if err != nil {
+			t.Fatal(err)
+		}
+		// A listing without a date keeps the stored posting date.
+		if b.FirstSeenAt != 1000 || b.LastSeenAt != 2000 || b.PostedDate != 500 {
+			t.Errorf("unexpected lifecycle fields for b: %+v", b)
+		}
+
+		if n, err := store.MarkRemoved("nic", 2000); err != nil || n != 1 {
+			t.Errorf("MarkRemoved = %d, %v; want 1", n, err)
+		}
+		if n, err := store.MarkExpired("2026-06-01"); err != nil || n != 0 {
+			t.Errorf("MarkExpired on removed job = %d, %v; want 0", n, err)
+		}
+
+		all, err := store.AllJobs()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(all) != 2 || all[0].ID != "a" || all[0].Status != StatusRemoved || all[1].Status != StatusActive {
+			t.Errorf("unexpected jobs: %+v", all)
+		}
+
+		if _, err := store.GetJob("missing"); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("expected sql.ErrNoRows, got %v", err)
+		}
+		if err := store.OptimizeAndClose(); err != nil {
+			t.Errorf("OptimizeAndClose: %v", err)
+		}
+	})
+}
+
+func TestStore_MarkExpiredSkipsInferredDates(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store Store) {
+		_, err := store.UpsertJobs(context.Background(), []Job{
+			{ID: "stated", Title: "Clerk", ClosingDate: "2026-03-01", ClosingDateConfidence: ConfidenceHigh},
+			{ID: "inferred", Title: "Driver", ClosingDate: "2026-03-01", ClosingDateConfidence: ConfidenceLow},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if n, err := store.MarkExpired("2026-03-15"); err != nil || n != 1 {
+			t.Errorf("MarkExpired = %d, %v; want 1", n, err)
+		}
+		inferred, err := store.GetJob("inferred")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if inferred.Status != StatusActive || inferred.ClosingDateConfidence != ConfidenceLow {
+			t.Errorf("expected the inferred closing date kept and the job active, got %+v", inferred)
+		}
+	})
+}
+
+func TestStore_UpsertJobsReportsAndRecordsChanges(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store Store) {
+		ctx := context.Background()
+		job := Job{ID: "a", Title: "Clerk", Source: "nic", ClosingDate: "2026-03-01",
+			AdvertText: "Advt 1/2026", LastSeenAt: 1000}
+
+		summary, err := store.UpsertJobs(ctx, []Job{job})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if summary.UpsertCounts != (UpsertCounts{Inserted: 1}) || len(summary.InsertedIDs) != 1 || summary.InsertedIDs[0] != "a" {
+			t.Errorf("first upsert: %+v", summary)
+		}
+
+		job.LastSeenAt = 2000
+		if summary, err = store.UpsertJobs(ctx, []Job{job}); err != nil {
+			t.Fatal(err)
+		}
+		if summary.UpsertCounts != (UpsertCounts{Unchanged: 1}) {
+			t.Errorf("identical upsert: %+v", summary)
+		}
+
+		// Title corrected and deadline extended; the advert was not fetched
+		// this run, which must not erase the stored text.
+		job.Title = "Clerk Grade II"
+		job.ClosingDate = "2026-03-15"
+		job.AdvertText = ""
+		job.LastSeenAt = 3000
+		if summary, err = store.UpsertJobs(ctx, []Job{job}); err != nil {
+			t.Fatal(err)
+		}
+		if summary.UpsertCounts != (UpsertCounts{Updated: 1}) || len(summary.Changes) != 2 {
+			t.Errorf("changed upsert: %+v", summary)
+		}
+
+		got, err := store.GetJob("a")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.AdvertText != "Advt 1/2026" || got.LastSeenAt != 3000 || got.FirstSeenAt != 1000 {
+			t.Errorf("unexpected stored job: %+v", got)
+		}
+
+		history, err := store.JobHistory("a")
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := []Change{
+			{JobID: "a", Field: "title", OldValue: "Clerk", NewValue: "Clerk Grade II", ChangedAt: 3000},
+			{JobID: "a", Field: "closing_date", OldValue: "2026-03-01", NewValue: "2026-03-15", ChangedAt: 3000},
+		}
+		if len(history) != len(want) {
+			t.Fatalf("expected %d changes, got %+v", len(want), history)
+		}
+		for i := range want {
+			if history[i] != want[i] {
+				t.Errorf("change %d: got %+v, want %+v", i, history[i], want[i])
+			}
+		}
+	})
+}
+
+func TestStore_StructuredDetails(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store Store) {
+		ctx := context.Background()
+		job := Job{ID: "a", Title: "Junior Engineer", LastSeenAt: 1000,
+			AdvertisementNo: "01/2026",
+			Qualifications:  []string{"Diploma", "B.E./B.Tech"},
+			PayScale:        "Rs. 35,400-1,12,400",
+			MinAge:          18,
+			MaxAge:          27,
+			ApplicationMode: ApplicationOnline,
+			Attachments:     []Attachment{{Title: "Corrigendum", URL: "https://ssc.gov.in/corr.pdf"}},
+		}
+		if _, err := store.UpsertJobs(ctx, []Job{job}); err != nil {
+			t.Fatal(err)
+		}
+
+		// A run without adverts leaves the details alone...
+		bare := Job{ID: "a", Title: "Junior Engineer", LastSeenAt: 2000}
+		summary, err := store.UpsertJobs(ctx, []Job{bare})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if summary.UpsertCounts != (UpsertCounts{Unchanged: 1}) {
+			t.Errorf("bare upsert: %+v", summary)
+		}
+		got, err := store.GetJob("a")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got.Qualifications, job.Qualifications) || !reflect.DeepEqual(got.Attachments, job.Attachments) ||
+			got.AdvertisementNo != job.AdvertisementNo || got.PayScale != job.PayScale ||
+			got.MinAge != 18 || got.MaxAge != 27 || got.ApplicationMode != ApplicationOnline {
+			t.Errorf("details not kept: %+v", got)
+		}
+
+		// ...and a changed detail is recorded.
+		job.MaxAge = 30
+		job.LastSeenAt = 3000
+		if _, err := store.UpsertJobs(ctx, []Job{job}); err != nil {
+			t.Fatal(err)
+		}
+		history, err := store.JobHistory("a")
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := []Change{{JobID: "a", Field: "max_age", OldValue: "27", NewValue: "30", ChangedAt: 3000}}
+		if !reflect.DeepEqual(history, want) {
+			t.Errorf("got history %+v, want %+v", history, want)
+		}
+	})
+}
+
+func TestStore_LinksDuplicatesUnderCanonicalID(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store Store) {
+		ctx := context.Background()
+
+		if _, err := store.UpsertJobs(ctx, []Job{
+			{ID: "nic-je", Title: "Junior Engineer", Source: "nic", Fingerprint: "fp-je", LastSeenAt: 1000},
+			{ID: "clerk", Title: "Clerk", Source: "nic", Fingerprint: "fp-clerk", LastSeenAt: 1000},
+		}); err != nil {
+			t.Fatal(err)
+		}
+		// The same vacancy on a second board, seen later.
+		for _, seen := range []int64{2000, 3000} {
+			if _, err := store.UpsertJobs(ctx, []Job{
+				{ID: "nic-je", Title: "Junior Engineer", Source: "nic", Fingerprint: "fp-je", LastSeenAt: seen},
+				{ID: "ssc-je", Title: "Junior Engineer", Source: "ssc", Fingerprint: "fp-je", LastSeenAt: seen},
+				{ID: "clerk", Title: "Clerk", Source: "nic", Fingerprint: "fp-clerk", LastSeenAt: seen},
+			}); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		want := map[string]string{"nic-je": "nic-je", "ssc-je": "nic-je", "clerk": "clerk"}
+		for id, canonical := range want {
+			job, err := store.GetJob(id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if job.CanonicalID != canonical {
+				t.Errorf("%s: canonical ID %q, want %q", id, job.CanonicalID, canonical)
+			}
+		}
+	})
+}
+
+func TestStore_UpsertJobsCancelledWritesNothing(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store Store) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		if _, err := store.UpsertJobs(ctx, []Job{{ID: "a", Title: "Clerk"}}); err == nil {
+			t.Fatal("expected error for cancelled context")
+		}
+		if all, _ := store.AllJobs(); len(all) != 0 {
+			t.Errorf("cancelled batch wrote %d jobs", len(all))
+		}
+	})
+}
+
+func TestUpsertJobs_RollsBackOnFailure(t *testing.T) {
+	database := openTestDB(t)
+
+	if _, err := database.UpsertJob(Job{ID: "kept", Title: "Original"}); err != nil {
+		t.Fatal(err)
+	}
+	_, err := database.conn.Exec(`CREATE TRIGGER reject BEFORE INSERT ON jobs
+		WHEN new.title = 'boom' BEGIN SELECT RAISE(ABORT, 'rejected'); END`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = database.UpsertJobs(context.Background(), []Job{
+		{ID: "kept", Title: "Updated"},
+		{ID: "new", Title: "Fresh"},
+		{ID: "bad", Title: "boom"},
+	})
+	if err == nil {
+		t.Fatal("expected batch to fail")
+	}
+
+	kept, err := database.GetJob("kept")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if kept.Title != "Original" {
+		t.Errorf("earlier row in failed batch was committed: %q", kept.Title)
+	}
+	if _, err := database.GetJob("new"); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("new row in failed batch was committed: %v", err)
+	}
+}
+
+func TestUpsertJobs_UpdatesInPlace(t *testing.T) {
+	database := openTestDB(t)
+
+	rowid := func() int64 {
+		t.Helper()
+		var id int64
+		if err := database.conn.QueryRow(`SELECT rowid FROM jobs WHERE id = 'a'`).Scan(&id); err != nil {
+			t.Fatal(err)
+		}
+		return id
+	}
+
+	// A replaced row would move past "b" to a new rowid.
+	for _, j := range []Job{{ID: "a", Title: "Clerk"}, {ID: "b", Title: "Driver"}} {
+		if _, err := database.UpsertJob(j); err != nil {
+			t.Fatal(err)
+		}
+	}
+	before := rowid()
+	result, err := database.UpsertJob(Job{ID: "a", Title: "Senior Clerk"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result != Updated {
+		t.Errorf("expected %v, got %v", Updated, result)
+	}
+	if after := rowid(); after != before {
+		t.Errorf("row was replaced: rowid %d -> %d", before, after)
+	}
+}
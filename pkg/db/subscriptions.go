@@ -0,0 +1,183 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Subscription is one subscriber's standing search. Empty filters match
+// every job; see pkg/digest for how they are applied.
+type Subscription struct {
+	ID            int64
+	Subscriber    string   // Delivery address, e.g. an email address
+	Name          string   // Optional label shown in the digest
+	Keywords      []string // Any of them in the title, department or advert text
+	Departments   []string // Any of them in the department
+	Locations     []string // Any of them in the location
+	Qualification string   // Subscriber's highest qualification; "" for any
+	Format        string   // Digest format: "html", "text" or "json"
+	CreatedAt     int64    // Unix timestamp
+	LastDigestAt  int64    // Unix timestamp of the last digest sent; 0 if none
+}
+
+// subscriptionMigrations is the schema of the subscriptions database. It is
+// kept out of jobs.db, which is published, because it holds subscribers'
+// addresses.
+var subscriptionMigrations = []migration{
+	{
+		version: 1,
+		name:    "create subscriptions table",
+		up: func(tx *sql.Tx) error {
+			// List columns hold JSON arrays of strings.
+			_, err := tx.Exec(`
+			CREATE TABLE IF NOT EXISTS subscriptions (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				subscriber TEXT NOT NULL,
+				name TEXT NOT NULL DEFAULT '',
+				keywords TEXT NOT NULL DEFAULT '[]',
+				departments TEXT NOT NULL DEFAULT '[]',
+				locations TEXT NOT NULL DEFAULT '[]',
+				qualification TEXT NOT NULL DEFAULT '',
+				format TEXT NOT NULL DEFAULT 'html',
+				created_at INTEGER NOT NULL,
+				last_digest_at INTEGER NOT NULL DEFAULT 0
+			);
+
+			CREATE INDEX IF NOT EXISTS idx_subscriptions_subscriber ON subscriptions(subscriber);
+			`)
+			return err
+		},
+	},
+}
+
+// SubscriptionDB stores subscriptions in their own SQLite file.
+type SubscriptionDB struct {
+	conn *sql.DB
+}
+
+// OpenSubscriptions opens the subscriptions database at filepath, creating
+// it and applying pending migrations as needed.
+func OpenSubscriptions(filepath string) (*SubscriptionDB, error) {
+	conn, err := sql.Open("sqlite", filepath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open subscriptions db: %w", err)
+	}
+	if err := migrate(conn, subscriptionMigrations); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return &SubscriptionDB{conn: conn}, nil
+}
+
+// Close closes the database.
+func (s *SubscriptionDB) Close() error {
+	return s.conn.Close()
+}
+
+// AddSubscription stores sub and returns its ID. CreatedAt defaults to now
+// and Format to "html".
+func (s *SubscriptionDB) AddSubscription(sub Subscription) (int64, error) {
+	if sub.Subscriber == "" {
+		return 0, errors.New("subscription has no subscriber")
+	}
+	if sub.CreatedAt == 0 {
+		sub.CreatedAt = time.Now().Unix()
+	}
+	if sub.Format == "" {
+		sub.Format = "html"
+	}
+
+	res, err := s.conn.Exec(`
+	INSERT INTO subscriptions (subscriber, name, keywords, departments, locations, qualification, format, created_at, last_digest_at)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		sub.Subscriber, sub.Name,
+		EncodeList(sub.Keywords), EncodeList(sub.Departments), EncodeList(sub.Locations),
+		sub.Qualification, sub.Format, sub.CreatedAt, sub.LastDigestAt,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to add subscription for %s: %w", sub.Subscriber, err)
+	}
+	return res.LastInsertId()
+}
+
+// Subscriptions returns every subscription ordered by ID.
+func (s *SubscriptionDB) Subscriptions() ([]Subscription, error) {
+	rows, err := s.conn.Query(`
+	SELECT id, subscriber, name, keywords, departments, locations, qualification, format, created_at, last_digest_at
+	FROM subscriptions ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list subscriptions: %w", err)
+	}
+	defer rows.Close()
+
+	var subs []Subscription
+	for rows.Next() {
+		var (
+			sub                              Subscription
+			keywords, departments, locations string
+		)
+		err := rows.Scan(&sub.ID, &sub.Subscriber, &sub.Name, &keywords, &departments, &locations,
+			&sub.Qualification, &sub.Format, &sub.CreatedAt, &sub.LastDigestAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read subscription: %w", err)
+		}
+		if sub.Keywords, err = DecodeList(keywords); err != nil {
+			return nil, fmt.Errorf("subscription %d: keywords: %w", sub.ID, err)
+		}
+		if sub.Departments, err = DecodeList(departments); err != nil {
+			return nil, fmt.Errorf("subscription %d: departments: %w", sub.ID, err)
+		}
+		if sub.Locations, err = DecodeList(locations); err != nil {
+			return nil, fmt.Errorf("subscription %d: locations: %w", sub.ID, err)
+		}
+		subs = append(subs, sub)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list subscriptions: %w", err)
+	}
+	return subs, nil
+}
+
+// DeleteSubscription removes the subscription with the given ID. It returns
+// sql.ErrNoRows if there is none.
+func (s *SubscriptionDB) DeleteSubscription(id int64) error {
+	res, err := s.conn.Exec(`DELETE FROM subscriptions WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete subscription %d: %w", id, err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// MarkDigested records that subscription id was sent a digest covering jobs
+// first seen up to at. The next digest starts from there.
+func (s *SubscriptionDB) MarkDigested(id, at int64) error {
+	if _, err := s.conn.Exec(`UPDATE subscriptions SET last_digest_at = ? WHERE id = ?`, at, id); err != nil {
+		return fmt.Errorf("failed to update subscription %d: %w", id, err)
+	}
+	return nil
+}
+
+// EncodeList stores a string list as a JSON array, as in the subscription
+// and jobs.qualifications columns; nil becomes [].
+func EncodeList(list []string) string {
+	if len(list) == 0 {
+		return "[]"
+	}
+	data, _ := json.Marshal(list) // []string always encodes
+	return string(data)
+}
+
+// DecodeList parses a JSON array written by EncodeList.
+func DecodeList(s string) ([]string, error) {
+	var list []string
+	if err := json.Unmarshal([]byte(s), &list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
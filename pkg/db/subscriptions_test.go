@@ -0,0 +1,98 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func openTestSubscriptions(t *testing.T) *SubscriptionDB {
+	t.Helper()
+	subs, err := OpenSubscriptions(filepath.Join(t.TempDir(), "subscriptions.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { subs.Close() })
+	return subs
+}
+
+func TestSubscriptions_AddListDelete(t *testing.T) {
+	subs := openTestSubscriptions(t)
+
+	id, err := subs.AddSubscription(Subscription{
+		Subscriber:    "a@example.org",
+		Name:          "Engineering",
+		Keywords:      []string{"engineer", "technical assistant"},
+		Locations:     []string{"Delhi"},
+		Qualification: "graduate",
+		CreatedAt:     1000,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := subs.AddSubscription(Subscription{Subscriber: "b@example.org", Format: "text"}); err != nil {
+		t.Fatal(err)
+	}
+
+	list, err := subs.Subscriptions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 2 {
+		t.Fatalf("expected 2 subscriptions, got %d", len(list))
+	}
+	want := Subscription{
+		ID:            id,
+		Subscriber:    "a@example.org",
+		Name:          "Engineering",
+		Keywords:      []string{"engineer", "technical assistant"},
+		Departments:   []string{},
+		Locations:     []string{"Delhi"},
+		Qualification: "graduate",
+		Format:        "html",
+		CreatedAt:     1000,
+	}
+	if !reflect.DeepEqual(list[0], want) {
+		t.Errorf("got %+v, want %+v", list[0], want)
+	}
+	if list[1].Format != "text" || list[1].CreatedAt == 0 {
+		t.Errorf("unexpected defaults: %+v", list[1])
+	}
+
+	if err := subs.DeleteSubscription(id); err != nil {
+		t.Fatal(err)
+	}
+	if err := subs.DeleteSubscription(id); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("expected sql.ErrNoRows deleting twice, got %v", err)
+	}
+	if list, _ := subs.Subscriptions(); len(list) != 1 {
+		t.Errorf("expected 1 subscription after delete, got %d", len(list))
+	}
+}
+
+func TestSubscriptions_RequireSubscriber(t *testing.T) {
+	subs := openTestSubscriptions(t)
+	if _, err := subs.AddSubscription(Subscription{Keywords: []string{"clerk"}}); err == nil {
+		t.Error("expected an error for a subscription without a subscriber")
+	}
+}
+
+func TestSubscriptions_MarkDigested(t *testing.T) {
+	subs := openTestSubscriptions(t)
+	id, err := subs.AddSubscription(Subscription{Subscriber: "a@example.org"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := subs.MarkDigested(id, 5000); err != nil {
+		t.Fatal(err)
+	}
+	list, err := subs.Subscriptions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if list[0].LastDigestAt != 5000 {
+		t.Errorf("expected LastDigestAt 5000, got %d", list[0].LastDigestAt)
+	}
+}
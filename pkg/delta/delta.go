@@ -0,0 +1,354 @@
+// Package delta produces incremental sync artifacts for jobs.db.
+//
+// Every pipeline run that changes the job set bumps a version number and
+// writes a delta file listing the jobs added, changed and removed since the
+// previous version. metadata.json publishes the chain of recent deltas, so a
+// client on version N downloads only the deltas after N instead of the whole
+// database. Clients older than the oldest kept delta fall back to the full
+// jobs.db snapshot.
+package delta
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/entreya/job-aggregation/pkg/db"
+)
+
+const stateFile = "state.json"
+
+// Config controls where deltas are written and how many are kept.
+type Config struct {
+	Dir  string // Output directory (default "deltas")
+	Keep int    // Number of most recent deltas to keep (default 30)
+}
+
+// DefaultConfig returns sensible defaults.
+func DefaultConfig() Config {
+	return Config{
+		Dir:  "deltas",
+		Keep: 30,
+	}
+}
+
+// withDefaults fills unset fields of c from DefaultConfig.
+func (c Config) withDefaults() Config {
+	if c.Dir == "" {
+		c.Dir = DefaultConfig().Dir
+	}
+	if c.Keep <= 0 {
+		c.Keep = DefaultConfig().Keep
+	}
+	return c
+}
+
+// Record is one job as shipped in a delta. JSON keys match the jobs table
+// columns so clients can write records straight into their local database.
+//
+// last_seen_at is left out on purpose: it changes on every run and would
+// turn every job into a change.
+type Record struct {
+	ID          string `json:"id"`
+	Title       string `json:"title"`
+	Department  string `json:"department"`
+	Location    string `json:"location"`
+	PostedDate  int64  `json:"posted_date"`
+	URL         string `json:"url"`
+	Vacancies   int    `json:"vacancies"`
+	PayLevel    string `json:"pay_level"`
+	AgeLimit    string `json:"age_limit"`
+	ClosingDate string `json:"closing_date"`
+	AdvertText  string `json:"advert_text"`
+	Source      string `json:"source"`
+	FirstSeenAt int64  `json:"first_seen_at"`
+	Status      string `json:"status"`
+	Fingerprint string `json:"fingerprint"`
+	CanonicalID string `json:"canonical_id"`
+
+	AdvertisementNo string `json:"advertisement_no"`
+	Qualifications  string `json:"qualifications"` // JSON array, as stored
+	PayScale        string `json:"pay_scale"`
+	MinAge          int    `json:"min_age"`
+	MaxAge          int    `json:"max_age"`
+	ApplicationMode string `json:"application_mode"`
+	Attachments     string `json:"attachments"` // JSON array, as stored
+
+	DepartmentID string `json:"department_id"`
+	LocationID   string `json:"location_id"`
+
+	ClosingDateConfidence string `json:"closing_date_confidence"`
+}
+
+// RecordFromJob converts a stored job to its delta record.
+func RecordFromJob(j db.Job) Record {
+	return Record{
+		ID:          j.ID,
+		Title:       j.Title,
+		Department:  j.Department,
+		Location:    j.Location,
+		PostedDate:  j.PostedDate,
+		URL:         j.URL,
+		Vacancies:   j.Vacancies,
+		PayLevel:    j.PayLevel,
+		AgeLimit:    j.AgeLimit,
+		ClosingDate: j.ClosingDate,
+		AdvertText:  j.AdvertText,
+		Source:      j.Source,
+		FirstSeenAt: j.FirstSeenAt,
+		Status:      j.Status,
+		Fingerprint: j.Fingerprint,
+		CanonicalID: j.CanonicalID,
+
+		AdvertisementNo: j.AdvertisementNo,
+		Qualifications:  db.EncodeList(j.Qualifications),
+		PayScale:        j.PayScale,
+		MinAge:          j.MinAge,
+		MaxAge:          j.MaxAge,
+		ApplicationMode: j.ApplicationMode,
+		Attachments:     db.EncodeAttachments(j.Attachments),
+
+		DepartmentID: j.DepartmentID,
+		LocationID:   j.LocationID,
+
+		ClosingDateConfidence: j.ClosingDateConfidence,
+	}
+}
+
+// hash returns a short content hash of r.
+func (r Record) hash() string {
+	// Marshalling a struct of strings and ints cannot fail.
+	data, _ := json.Marshal(r)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:16])
+}
+
+// Delta is the set of changes between two consecutive versions.
+type Delta struct {
+	From      int      `json:"from"`
+	To        int      `json:"to"`
+	CreatedAt int64    `json:"created_at"`
+	Added     []Record `json:"added"`
+	Changed   []Record `json:"changed"`
+	Removed   []string `json:"removed"`
+}
+
+// Apply updates jobs, keyed by ID, to the delta's target version.
+func (d *Delta) Apply(jobs map[string]Record) {
+	for _, r := range d.Added {
+		jobs[r.ID] = r
+	}
+	for _, r := range d.Changed {
+		jobs[r.ID] = r
+	}
+	for _, id := range d.Removed {
+		delete(jobs, id)
+	}
+}
+
+// Info describes a published delta file. The chain of Infos is written to
+// metadata.json.
+type Info struct {
+	From     int    `json:"from"`
+	To       int    `json:"to"`
+	File     string `json:"file"`     // Path relative to the repository root
+	Checksum string `json:"checksum"` // SHA-256 of the file
+	Size     int64  `json:"size"`
+}
+
+// State is the pipeline's record of the latest version: the content hash of
+// every job at that version and the deltas still published.
+type State struct {
+	Version int               `json:"version"`
+	Hashes  map[string]string `json:"hashes"`
+	Chain   []Info            `json:"chain"`
+}
+
+// Load reads the state from dir. A missing state file yields an empty state
+// at version 0.
+func Load(dir string) (*State, error) {
+	data, err := os.ReadFile(filepath.Join(dir, stateFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return &State{Hashes: map[string]string{}}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read delta state: %w", err)
+	}
+
+	var s State
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("parse delta state: %w", err)
+	}
+	if s.Hashes == nil {
+		s.Hashes = map[string]string{}
+	}
+	return &s, nil
+}
+
+// Update is Prepare followed by Commit, for callers that publish nothing
+// else alongside the deltas.
+func Update(cfg Config, records []Record, now time.Time) (*State, error) {
+	state, err := Prepare(cfg, records, now)
+	if err != nil {
+		return nil, err
+	}
+	return state, Commit(cfg, state)
+}
+
+// Prepare compares records, the full current job set, with the last version
+// and, if anything changed, writes the next delta and returns the advanced
+// state, its chain cut to cfg.Keep deltas. state.json is left alone until
+// Commit, so a run that fails before publishing the new version leaves the
+// previous one in place; the unreferenced delta file is overwritten by the
+// next run.
+//
+// The first run only records a baseline (version 1): there is no earlier
+// version a client could hold.
+func Prepare(cfg Config, records []Record, now time.Time) (*State, error) {
+	cfg = cfg.withDefaults()
+
+	state, err := Load(cfg.Dir)
+	if err != nil {
+		return nil, err
+	}
+
+	d, hashes := diff(state.Hashes, records)
+	if state.Version == 0 {
+		state.Version = 1
+		state.Hashes = hashes
+		return state, nil
+	}
+	if len(d.Added)+len(d.Changed)+len(d.Removed) == 0 {
+		return state, nil
+	}
+
+	d.From = state.Version
+	d.To = state.Version + 1
+	d.CreatedAt = now.Unix()
+
+	info, err := writeDelta(cfg.Dir, d)
+	if err != nil {
+		return nil, err
+	}
+
+	state.Version = d.To
+	state.Hashes = hashes
+	state.Chain = append(state.Chain, info)
+	if len(state.Chain) > cfg.Keep {
+		state.Chain = state.Chain[len(state.Chain)-cfg.Keep:]
+	}
+	return state, nil
+}
+
+// Commit records state, as returned by Prepare, in state.json and deletes
+// the delta files its chain no longer lists. Call it once the new version
+// has been published.
+func Commit(cfg Config, state *State) error {
+	cfg = cfg.withDefaults()
+	if err := save(cfg.Dir, state); err != nil {
+		return err
+	}
+
+	kept := make(map[string]bool, len(state.Chain))
+	for _, info := range state.Chain {
+		kept[filepath.Clean(filepath.FromSlash(info.File))] = true
+	}
+	files, err := filepath.Glob(filepath.Join(cfg.Dir, "delta-*.json"))
+	if err != nil {
+		return fmt.Errorf("list deltas: %w", err)
+	}
+	for _, f := range files {
+		if kept[filepath.Clean(f)] {
+			continue
+		}
+		if err := os.Remove(f); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("prune delta %s: %w", f, err)
+		}
+	}
+	return nil
+}
+
+// diff classifies records against the previous hashes and returns the
+// changes along with the hashes of the new version. Removed IDs are sorted
+// so delta files are reproducible.
+func diff(prev map[string]string, records []Record) (*Delta, map[string]string) {
+	d := &Delta{
+		Added:   make([]Record, 0),
+		Changed: make([]Record, 0),
+		Removed: make([]string, 0),
+	}
+	hashes := make(map[string]string, len(records))
+
+	for _, r := range records {
+		h := r.hash()
+		hashes[r.ID] = h
+		old, existed := prev[r.ID]
+		switch {
+		case !existed:
+			d.Added = append(d.Added, r)
+		case old != h:
+			d.Changed = append(d.Changed, r)
+		}
+	}
+	for id := range prev {
+		if _, ok := hashes[id]; !ok {
+			d.Removed = append(d.Removed, id)
+		}
+	}
+	sort.Strings(d.Removed)
+
+	return d, hashes
+}
+
+// writeDelta writes d as compact JSON and describes the file.
+func writeDelta(dir string, d *Delta) (Info, error) {
+	data, err := json.Marshal(d)
+	if err != nil {
+		return Info{}, fmt.Errorf("marshal delta: %w", err)
+	}
+
+	path := filepath.Join(dir, fmt.Sprintf("delta-%d.json", d.To))
+	if err := writeFileAtomic(path, data); err != nil {
+		return Info{}, err
+	}
+
+	sum := sha256.Sum256(data)
+	return Info{
+		From:     d.From,
+		To:       d.To,
+		File:     filepath.ToSlash(path),
+		Checksum: hex.EncodeToString(sum[:]),
+		Size:     int64(len(data)),
+	}, nil
+}
+
+// save writes the state file.
+func save(dir string, s *State) error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("marshal delta state: %w", err)
+	}
+	return writeFileAtomic(filepath.Join(dir, stateFile), data)
+}
+
+// writeFileAtomic writes data to a temporary file and renames it into place,
+// so an interrupted run never leaves a truncated file behind.
+func writeFileAtomic(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("create delta dir: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,199 @@
+package delta
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func rec(id, title string) Record {
+	return Record{ID: id, Title: title, Status: "active"}
+}
+
+func readDelta(t *testing.T, info Info) *Delta {
+	t.Helper()
+	data, err := os.ReadFile(info.File)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var d Delta
+	if err := json.Unmarshal(data, &d); err != nil {
+		t.Fatal(err)
+	}
+	return &d
+}
+
+func TestUpdate_FirstRunRecordsBaseline(t *testing.T) {
+	cfg := Config{Dir: t.TempDir(), Keep: 5}
+
+	state, err := Update(cfg, []Record{rec("a", "Clerk")}, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.Version != 1 || len(state.Chain) != 0 {
+		t.Errorf("expected baseline version 1 without deltas, got %+v", state)
+	}
+}
+
+func TestUpdate_WritesDeltaOfChanges(t *testing.T) {
+	cfg := Config{Dir: t.TempDir(), Keep: 5}
+	now := time.Unix(1772300000, 0)
+
+	if _, err := Update(cfg, []Record{rec("a", "Clerk"), rec("b", "Driver"), rec("c", "Peon")}, now); err != nil {
+		t.Fatal(err)
+	}
+
+	changedB := rec("b", "Driver (Grade II)")
+	state, err := Update(cfg, []Record{rec("a", "Clerk"), changedB, rec("d", "Typist")}, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.Version != 2 || len(state.Chain) != 1 {
+		t.Fatalf("expected version 2 with one delta, got %+v", state)
+	}
+
+	info := state.Chain[0]
+	if info.From != 1 || info.To != 2 || info.Checksum == "" || info.Size == 0 {
+		t.Errorf("unexpected delta info %+v", info)
+	}
+
+	d := readDelta(t, info)
+	if len(d.Added) != 1 || d.Added[0].ID != "d" {
+		t.Errorf("expected d added, got %+v", d.Added)
+	}
+	if len(d.Changed) != 1 || d.Changed[0] != changedB {
+		t.Errorf("expected b changed, got %+v", d.Changed)
+	}
+	if len(d.Removed) != 1 || d.Removed[0] != "c" {
+		t.Errorf("expected c removed, got %v", d.Removed)
+	}
+	if d.CreatedAt != now.Unix() {
+		t.Errorf("unexpected created_at %d", d.CreatedAt)
+	}
+}
+
+func TestUpdate_NoChangesKeepsVersion(t *testing.T) {
+	cfg := Config{Dir: t.TempDir(), Keep: 5}
+	records := []Record{rec("a", "Clerk")}
+
+	for i := 0; i < 3; i++ {
+		state, err := Update(cfg, records, time.Now())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if state.Version != 1 {
+			t.Fatalf("run %d: version moved to %d without changes", i, state.Version)
+		}
+	}
+}
+
+func TestUpdate_ChainReplaysToLatest(t *testing.T) {
+	cfg := Config{Dir: t.TempDir(), Keep: 10}
+
+	runs := [][]Record{
+		{rec("a", "Clerk"), rec("b", "Driver")},
+		{rec("a", "Clerk"), rec("b", "Driver"), rec("c", "Peon")},
+		{rec("a", "Senior Clerk"), rec("c", "Peon")},
+		{rec("c", "Peon"), rec("d", "Typist")},
+	}
+	var state *State
+	var err error
+	for _, records := range runs {
+		if state, err = Update(cfg, records, time.Now()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// A client holding version 1 replays every delta.
+	client := map[string]Record{}
+	for _, r := range runs[0] {
+		client[r.ID] = r
+	}
+	for _, info := range state.Chain {
+		readDelta(t, info).Apply(client)
+	}
+
+	latest := runs[len(runs)-1]
+	if len(client) != len(latest) {
+		t.Fatalf("expected %d jobs after replay, got %d", len(latest), len(client))
+	}
+	for _, r := range latest {
+		if client[r.ID] != r {
+			t.Errorf("job %s: got %+v, want %+v", r.ID, client[r.ID], r)
+		}
+	}
+}
+
+func TestUpdate_PrunesOldDeltas(t *testing.T) {
+	cfg := Config{Dir: t.TempDir(), Keep: 2}
+
+	var state *State
+	var err error
+	for i := 0; i < 5; i++ {
+		title := string(rune('A' + i))
+		if state, err = Update(cfg, []Record{rec("a", title)}, time.Now()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if state.Version != 5 || len(state.Chain) != 2 {
+		t.Fatalf("expected version 5 with 2 deltas, got version %d with %d", state.Version, len(state.Chain))
+	}
+	if state.Chain[0].From != 3 {
+		t.Errorf("expected oldest kept delta to start at 3, got %d", state.Chain[0].From)
+	}
+
+	files, _ := filepath.Glob(filepath.Join(cfg.Dir, "delta-*.json"))
+	if len(files) != 2 {
+		t.Errorf("expected pruned delta files to be deleted, found %v", files)
+	}
+}
+
+func TestLoad_StateSurvivesReload(t *testing.T) {
+	cfg := Config{Dir: t.TempDir(), Keep: 5}
+	if _, err := Update(cfg, []Record{rec("a", "Clerk")}, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Update(cfg, []Record{rec("a", "Clerk"), rec("b", "Driver")}, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+
+	state, err := Load(cfg.Dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.Version != 2 || len(state.Hashes) != 2 || len(state.Chain) != 1 {
+		t.Errorf("unexpected reloaded state %+v", state)
+	}
+}
+
+func TestPrepare_OnlyCommitAdvancesState(t *testing.T) {
+	cfg := Config{Dir: t.TempDir(), Keep: 5}
+	if _, err := Update(cfg, []Record{rec("a", "Clerk")}, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+
+	// A run that fails after Prepare: the next one starts from version 1 again.
+	if _, err := Prepare(cfg, []Record{rec("a", "Clerk"), rec("b", "Driver")}, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	state, err := Prepare(cfg, []Record{rec("a", "Clerk"), rec("c", "Peon")}, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded, _ := Load(cfg.Dir); loaded.Version != 1 {
+		t.Fatalf("expected uncommitted state to stay at version 1, got %d", loaded.Version)
+	}
+	if d := readDelta(t, state.Chain[0]); d.From != 1 || len(d.Added) != 1 || d.Added[0].ID != "c" {
+		t.Errorf("expected the retried delta to replace the failed one, got %+v", d)
+	}
+
+	if err := Commit(cfg, state); err != nil {
+		t.Fatal(err)
+	}
+	if loaded, _ := Load(cfg.Dir); loaded.Version != 2 || len(loaded.Chain) != 1 {
+		t.Errorf("unexpected committed state %+v", loaded)
+	}
+}
@@ -0,0 +1,175 @@
+// Package digest matches newly seen jobs against subscribers' standing
+// searches (db.Subscription) and renders one digest per subscriber as HTML,
+// plain text or JSON, so subscribers get a periodic summary instead of one
+// alert per job.
+package digest
+
+import (
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/entreya/job-aggregation/pkg/db"
+)
+
+// Digest is the set of new jobs matching one subscription.
+type Digest struct {
+	Subscription db.Subscription
+	Since        time.Time // Jobs first seen after Since...
+	Until        time.Time // ...and up to Until
+	Jobs         []db.Job  // Newest first
+}
+
+// Generate builds a digest for every subscription with at least one
+// matching job first seen since its last digest (or since it was created)
+// and up to until. Only active canonical jobs are considered, so a job
+// posted on several boards appears once.
+func Generate(subs []db.Subscription, jobs []db.Job, until time.Time) []Digest {
+	candidates := make([]candidate, 0, len(jobs))
+	for _, j := range jobs {
+		if j.Status != db.StatusActive || (j.CanonicalID != "" && j.CanonicalID != j.ID) {
+			continue
+		}
+		candidates = append(candidates, newCandidate(j))
+	}
+	sort.Slice(candidates, func(a, b int) bool {
+		if candidates[a].job.FirstSeenAt != candidates[b].job.FirstSeenAt {
+			return candidates[a].job.FirstSeenAt > candidates[b].job.FirstSeenAt
+		}
+		return candidates[a].job.ID < candidates[b].job.ID
+	})
+
+	var digests []Digest
+	for _, sub := range subs {
+		since := sub.LastDigestAt
+		if since == 0 {
+			since = sub.CreatedAt
+		}
+		m := newMatcher(sub)
+
+		d := Digest{Subscription: sub, Since: time.Unix(since, 0), Until: until}
+		for _, c := range candidates {
+			if c.job.FirstSeenAt <= since || c.job.FirstSeenAt > until.Unix() {
+				continue
+			}
+			if m.match(c) {
+				d.Jobs = append(d.Jobs, c.job)
+			}
+		}
+		if len(d.Jobs) > 0 {
+			digests = append(digests, d)
+		}
+	}
+	return digests
+}
+
+// candidate is a job with the lower-cased text the matchers look at.
+type candidate struct {
+	job           db.Job
+	text          string // Title, department and advert text
+	department    string
+	location      string
+	qualification Qualification
+}
+
+func newCandidate(j db.Job) candidate {
+	text := j.Title + "\n" + j.Department + "\n" + j.AdvertText
+	return candidate{
+		job:           j,
+		text:          strings.ToLower(text),
+		department:    strings.ToLower(j.Department),
+		location:      strings.ToLower(j.Location),
+		qualification: RequiredQualification(j.Title + "\n" + j.AdvertText),
+	}
+}
+
+// matcher applies one subscription's filters. A job must pass every
+// non-empty filter; within a filter any one term is enough.
+type matcher struct {
+	keywords      []string
+	departments   []string
+	locations     []string
+	qualification Qualification
+}
+
+func newMatcher(sub db.Subscription) matcher {
+	// An unparseable level was rejected when the subscription was added;
+	// treat one written by hand as no restriction.
+	qual, _ := ParseQualification(sub.Qualification)
+	return matcher{
+		keywords:      lowerAll(sub.Keywords),
+		departments:   lowerAll(sub.Departments),
+		locations:     lowerAll(sub.Locations),
+		qualification: qual,
+	}
+}
+
+func (m matcher) match(c candidate) bool {
+	if len(m.keywords) > 0 && !containsAnyWord(c.text, m.keywords) {
+		return false
+	}
+	if len(m.departments) > 0 && !containsAnyWord(c.department, m.departments) {
+		return false
+	}
+	// Jobs open across the country match any location.
+	if len(m.locations) > 0 && c.location != "" && c.location != "all india" &&
+		!containsAnyWord(c.location, m.locations) {
+		return false
+	}
+	// A job whose requirement could not be read is kept rather than hidden.
+	if m.qualification != QualificationUnknown && c.qualification > m.qualification {
+		return false
+	}
+	return true
+}
+
+func lowerAll(terms []string) []string {
+	var out []string
+	for _, t := range terms {
+		if t = strings.ToLower(strings.TrimSpace(t)); t != "" {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// containsAnyWord reports whether text contains any of terms as whole words,
+// so "it" does not match "title". Both must be lower-case.
+func containsAnyWord(text string, terms []string) bool {
+	for _, term := range terms {
+		if containsWord(text, term) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsWord(text, term string) bool {
+	for offset := 0; ; {
+		i := strings.Index(text[offset:], term)
+		if i < 0 {
+			return false
+		}
+		start, end := offset+i, offset+i+len(term)
+		if !wordRuneBefore(text, start) && !wordRuneAfter(text, end) {
+			return true
+		}
+		offset = start + 1
+	}
+}
+
+func wordRuneBefore(s string, i int) bool {
+	r, size := utf8.DecodeLastRuneInString(s[:i])
+	return size > 0 && isWordRune(r)
+}
+
+func wordRuneAfter(s string, i int) bool {
+	r, size := utf8.DecodeRuneInString(s[i:])
+	return size > 0 && isWordRune(r)
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,147 @@
+package digest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/entreya/job-aggregation/pkg/db"
+)
+
+func activeJob(id, title string, firstSeen int64) db.Job {
+	return db.Job{ID: id, Title: title, FirstSeenAt: firstSeen, Status: db.StatusActive, CanonicalID: id}
+}
+
+func digestIDs(d Digest) []string {
+	var ids []string
+	for _, j := range d.Jobs {
+		ids = append(ids, j.ID)
+	}
+	return ids
+}
+
+func TestGenerate_MatchesFilters(t *testing.T) {
+	jobs := []db.Job{
+		{ID: "je", Title: "Junior Engineer (Civil)", Department: "Staff Selection Commission", Location: "Delhi",
+			AdvertText: "Essential: Diploma in Civil Engineering.", FirstSeenAt: 200, Status: db.StatusActive},
+		{ID: "ae", Title: "Assistant Engineer", Department: "CPWD", Location: "Mumbai",
+			AdvertText: "Bachelor's degree in Engineering.", FirstSeenAt: 300, Status: db.StatusActive},
+		{ID: "clerk", Title: "Lower Division Clerk", Department: "Staff Selection Commission", Location: "All India",
+			AdvertText: "12th pass.", FirstSeenAt: 400, Status: db.StatusActive},
+		{ID: "sci", Title: "Scientist B", Department: "DRDO", Location: "Bengaluru",
+			AdvertText: "Post Graduate degree in Physics.", FirstSeenAt: 500, Status: db.StatusActive},
+	}
+
+	tests := []struct {
+		name string
+		sub  db.Subscription
+		want []string
+	}{
+		{"no filters", db.Subscription{}, []string{"sci", "clerk", "ae", "je"}},
+		{"keyword", db.Subscription{Keywords: []string{"engineer"}}, []string{"ae", "je"}},
+		{"keyword matches advert text", db.Subscription{Keywords: []string{"physics"}}, []string{"sci"}},
+		{"keyword is a whole word", db.Subscription{Keywords: []string{"engine"}}, nil},
+		{"department", db.Subscription{Departments: []string{"staff selection commission"}}, []string{"clerk", "je"}},
+		{"location keeps all-india jobs", db.Subscription{Locations: []string{"Delhi"}}, []string{"clerk", "je"}},
+		{"qualification", db.Subscription{Qualification: "diploma"}, []string{"clerk", "je"}},
+		{"graduate excludes postgraduate", db.Subscription{Qualification: "graduate"}, []string{"clerk", "ae", "je"}},
+		{"filters combine", db.Subscription{Keywords: []string{"engineer"}, Locations: []string{"mumbai"}}, []string{"ae"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.sub.ID = 1
+			digests := Generate([]db.Subscription{tt.sub}, jobs, time.Unix(1000, 0))
+			if tt.want == nil {
+				if len(digests) != 0 {
+					t.Errorf("expected no digest, got %v", digestIDs(digests[0]))
+				}
+				return
+			}
+			if len(digests) != 1 {
+				t.Fatalf("expected 1 digest, got %d", len(digests))
+			}
+			got := digestIDs(digests[0])
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("got %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerate_OnlyNewActiveCanonicalJobs(t *testing.T) {
+	dup := activeJob("dup", "Clerk", 300)
+	dup.CanonicalID = "new"
+	removed := activeJob("removed", "Clerk", 300)
+	removed.Status = db.StatusRemoved
+	jobs := []db.Job{
+		activeJob("old", "Clerk", 100),
+		activeJob("new", "Clerk", 300),
+		activeJob("future", "Clerk", 2000),
+		dup,
+		removed,
+	}
+
+	subs := []db.Subscription{
+		{ID: 1, CreatedAt: 50},
+		{ID: 2, CreatedAt: 50, LastDigestAt: 200},
+		{ID: 3, CreatedAt: 500},
+	}
+	digests := Generate(subs, jobs, time.Unix(1000, 0))
+	if len(digests) != 2 {
+		t.Fatalf("expected digests for subscriptions 1 and 2, got %d", len(digests))
+	}
+	if got := digestIDs(digests[0]); len(got) != 2 || got[0] != "new" || got[1] != "old" {
+		t.Errorf("subscription 1: got %v", got)
+	}
+	if got := digestIDs(digests[1]); len(got) != 1 || got[0] != "new" {
+		t.Errorf("subscription 2: got %v", got)
+	}
+	if !digests[1].Since.Equal(time.Unix(200, 0)) {
+		t.Errorf("expected digest since the last one, got %v", digests[1].Since)
+	}
+}
+
+func TestRequiredQualification(t *testing.T) {
+	tests := []struct {
+		text string
+		want Qualification
+	}{
+		{"Matriculation or equivalent", Qualification10th},
+		{"Class X pass", Qualification10th},
+		{"10+2 from a recognised board", Qualification12th},
+		{"Class XII with Science", Qualification12th},
+		{"ITI certificate in Fitter trade", QualificationDiploma},
+		{"B.Tech in Computer Science", QualificationGraduate},
+		{"Graduate Apprentice", QualificationGraduate},
+		{"Post-Graduate degree in Economics", QualificationPostgraduate},
+		{"M.Sc. or Ph.D. in Chemistry", QualificationPostgraduate},
+		{"Essential: Bachelor's degree. Desirable: MBA.", QualificationGraduate},
+		{"Stenographer Grade C", QualificationUnknown},
+	}
+	for _, tt := range tests {
+		if got := RequiredQualification(tt.text); got != tt.want {
+			t.Errorf("RequiredQualification(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestParseQualification(t *testing.T) {
+	for in, want := range map[string]Qualification{
+		"":         QualificationUnknown,
+		"Graduate": QualificationGraduate,
+		" 12th ":   Qualification12th,
+		"PG":       QualificationPostgraduate,
+	} {
+		got, err := ParseQualification(in)
+		if err != nil || got != want {
+			t.Errorf("ParseQualification(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParseQualification("doctorate"); err == nil {
+		t.Error("expected an error for an unknown level")
+	}
+}
@@ -0,0 +1,94 @@
+package digest
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Qualification is an educational level. Levels are ordered, so a
+// subscriber qualified at one level is eligible for jobs at or below it.
+type Qualification int
+
+const (
+	QualificationUnknown Qualification = iota
+	Qualification10th
+	Qualification12th
+	QualificationDiploma
+	QualificationGraduate
+	QualificationPostgraduate
+)
+
+var qualificationNames = map[Qualification]string{
+	QualificationUnknown:      "",
+	Qualification10th:         "10th",
+	Qualification12th:         "12th",
+	QualificationDiploma:      "diploma",
+	QualificationGraduate:     "graduate",
+	QualificationPostgraduate: "postgraduate",
+}
+
+// String returns the name accepted by ParseQualification.
+func (q Qualification) String() string {
+	return qualificationNames[q]
+}
+
+// qualificationAliases maps accepted spellings to levels.
+var qualificationAliases = map[string]Qualification{
+	"":              QualificationUnknown,
+	"any":           QualificationUnknown,
+	"10th":          Qualification10th,
+	"matric":        Qualification10th,
+	"matriculation": Qualification10th,
+	"12th":          Qualification12th,
+	"10+2":          Qualification12th,
+	"intermediate":  Qualification12th,
+	"diploma":       QualificationDiploma,
+	"iti":           QualificationDiploma,
+	"graduate":      QualificationGraduate,
+	"graduation":    QualificationGraduate,
+	"degree":        QualificationGraduate,
+	"postgraduate":  QualificationPostgraduate,
+	"post-graduate": QualificationPostgraduate,
+	"pg":            QualificationPostgraduate,
+	"masters":       QualificationPostgraduate,
+}
+
+// ParseQualification parses a level name such as "12th" or "graduate".
+// "" and "any" give QualificationUnknown, which places no restriction.
+func ParseQualification(s string) (Qualification, error) {
+	q, ok := qualificationAliases[strings.ToLower(strings.TrimSpace(s))]
+	if !ok {
+		return QualificationUnknown, fmt.Errorf("unknown qualification %q (want 10th, 12th, diploma, graduate or postgraduate)", s)
+	}
+	return q, nil
+}
+
+// qualificationPatterns find mentions of each level in advertisement text,
+// highest first: postgraduate mentions are removed before looking for
+// graduate ones, so "post graduate" does not also count as "graduate".
+var qualificationPatterns = []struct {
+	level Qualification
+	re    *regexp.Regexp
+}{
+	{QualificationPostgraduate, regexp.MustCompile(`(?i)\b(post[\s-]?graduat\w*(\s+degree)?|master'?s?\s+degree|m\.?\s?tech|m\.?sc|m\.?com|mba|ph\.?\s?d)\b`)},
+	{QualificationGraduate, regexp.MustCompile(`(?i)\b(graduat\w*|bachelor'?s?|degree|b\.?\s?tech|b\.?sc|b\.?com|mbbs|llb)\b`)},
+	{QualificationDiploma, regexp.MustCompile(`(?i)\b(diploma|iti)\b`)},
+	{Qualification12th, regexp.MustCompile(`(?i)(\b12th\b|\bintermediate\b|\bhigher secondary\b|\b10\s?\+\s?2\b|\bclass\s+xii\b)`)},
+	{Qualification10th, regexp.MustCompile(`(?i)\b(10th|matric\w*|high school|class\s+x)\b`)},
+}
+
+// RequiredQualification guesses the minimum qualification a job asks for
+// from its text: the lowest level mentioned, since adverts list the
+// essential qualification alongside desirable higher ones. It returns
+// QualificationUnknown when no level is mentioned.
+func RequiredQualification(text string) Qualification {
+	required := QualificationUnknown
+	for _, p := range qualificationPatterns {
+		if p.re.MatchString(text) {
+			required = p.level
+			text = p.re.ReplaceAllString(text, " ")
+		}
+	}
+	return required
+}
@@ -0,0 +1,199 @@
+package digest
+
+import (
+	"encoding/json"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"strings"
+	texttemplate "text/template"
+	"time"
+)
+
+// Digest formats, as stored in db.Subscription.Format.
+const (
+	FormatHTML = "html"
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// ValidFormat reports whether format is one Render accepts.
+func ValidFormat(format string) bool {
+	switch format {
+	case FormatHTML, FormatText, FormatJSON:
+		return true
+	}
+	return false
+}
+
+// ContentType returns the MIME type of digests in format.
+func ContentType(format string) string {
+	switch format {
+	case FormatText:
+		return "text/plain; charset=utf-8"
+	case FormatJSON:
+		return "application/json"
+	default:
+		return "text/html; charset=utf-8"
+	}
+}
+
+// Extension returns the file extension for digests in format.
+func Extension(format string) string {
+	switch format {
+	case FormatText:
+		return ".txt"
+	case FormatJSON:
+		return ".json"
+	default:
+		return ".html"
+	}
+}
+
+// Subject is a one-line summary of d, e.g. for an email subject.
+func Subject(d Digest) string {
+	noun := "jobs"
+	if len(d.Jobs) == 1 {
+		noun = "job"
+	}
+	if d.Subscription.Name != "" {
+		return fmt.Sprintf("%d new %s for %s", len(d.Jobs), noun, d.Subscription.Name)
+	}
+	return fmt.Sprintf("%d new %s", len(d.Jobs), noun)
+}
+
+// Render writes d in its subscription's format ("" means HTML).
+func Render(w io.Writer, d Digest) error {
+	switch d.Subscription.Format {
+	case FormatHTML, "":
+		return htmlTemplate.Execute(w, view(d))
+	case FormatText:
+		return textTemplate.Execute(w, view(d))
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(jsonDigestFrom(d))
+	default:
+		return fmt.Errorf("unknown digest format %q", d.Subscription.Format)
+	}
+}
+
+// Job is the part of a job shown in a digest.
+type Job struct {
+	ID          string `json:"id"`
+	Title       string `json:"title"`
+	Department  string `json:"department"`
+	Location    string `json:"location"`
+	URL         string `json:"url"`
+	Vacancies   int    `json:"vacancies,omitempty"`
+	PayLevel    string `json:"pay_level,omitempty"`
+	AgeLimit    string `json:"age_limit,omitempty"`
+	ClosingDate string `json:"closing_date,omitempty"`
+	FirstSeenAt int64  `json:"first_seen_at"`
+}
+
+type jsonDigest struct {
+	Subscriber string    `json:"subscriber"`
+	Name       string    `json:"name,omitempty"`
+	Since      time.Time `json:"since"`
+	Until      time.Time `json:"until"`
+	Jobs       []Job     `json:"jobs"`
+}
+
+func jsonDigestFrom(d Digest) jsonDigest {
+	return jsonDigest{
+		Subscriber: d.Subscription.Subscriber,
+		Name:       d.Subscription.Name,
+		Since:      d.Since.UTC(),
+		Until:      d.Until.UTC(),
+		Jobs:       jobsFrom(d),
+	}
+}
+
+func jobsFrom(d Digest) []Job {
+	jobs := make([]Job, 0, len(d.Jobs))
+	for _, j := range d.Jobs {
+		jobs = append(jobs, Job{
+			ID:          j.ID,
+			Title:       j.Title,
+			Department:  j.Department,
+			Location:    j.Location,
+			URL:         j.URL,
+			Vacancies:   j.Vacancies,
+			PayLevel:    j.PayLevel,
+			AgeLimit:    j.AgeLimit,
+			ClosingDate: j.ClosingDate,
+			FirstSeenAt: j.FirstSeenAt,
+		})
+	}
+	return jobs
+}
+
+// templateData is what the HTML and text templates see.
+type templateData struct {
+	Subject string
+	Name    string
+	Since   string
+	Until   string
+	Jobs    []Job
+}
+
+func view(d Digest) templateData {
+	return templateData{
+		Subject: Subject(d),
+		Name:    d.Subscription.Name,
+		Since:   d.Since.UTC().Format("2 Jan 2006 15:04 MST"),
+		Until:   d.Until.UTC().Format("2 Jan 2006 15:04 MST"),
+		Jobs:    jobsFrom(d),
+	}
+}
+
+func where(j Job) string {
+	var parts []string
+	for _, p := range []string{j.Department, j.Location} {
+		if p != "" {
+			parts = append(parts, p)
+		}
+	}
+	return strings.Join(parts, " — ")
+}
+
+var htmlTemplate = htmltemplate.Must(htmltemplate.New("digest").
+	Funcs(htmltemplate.FuncMap{"where": where}).
+	Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>{{.Subject}}</title></head>
+<body>
+<h1>{{.Subject}}</h1>
+<p>Jobs first seen between {{.Since}} and {{.Until}}.</p>
+<ul>
+{{- range .Jobs}}
+<li>
+<a href="{{.URL}}">{{.Title}}</a>
+{{- with where .}}<br>{{.}}{{end}}
+{{- if .Vacancies}}<br>Vacancies: {{.Vacancies}}{{end}}
+{{- if .PayLevel}}<br>Pay: {{.PayLevel}}{{end}}
+{{- if .ClosingDate}}<br>Apply by: {{.ClosingDate}}{{end}}
+</li>
+{{- end}}
+</ul>
+</body>
+</html>
+`))
+
+var textTemplate = texttemplate.Must(texttemplate.New("digest").
+	Funcs(texttemplate.FuncMap{"where": where}).
+	Parse(`{{.Subject}}
+Jobs first seen between {{.Since}} and {{.Until}}.
+{{range .Jobs}}
+* {{.Title}}
+{{- with where .}}
+  {{.}}{{end}}
+{{- if .Vacancies}}
+  Vacancies: {{.Vacancies}}{{end}}
+{{- if .PayLevel}}
+  Pay: {{.PayLevel}}{{end}}
+{{- if .ClosingDate}}
+  Apply by: {{.ClosingDate}}{{end}}
+  {{.URL}}
+{{end}}`))
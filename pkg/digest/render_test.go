@@ -0,0 +1,78 @@
+package digest
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/entreya/job-aggregation/pkg/db"
+)
+
+func testDigest(format string) Digest {
+	return Digest{
+		Subscription: db.Subscription{ID: 7, Subscriber: "a@example.org", Name: "Engineering", Format: format},
+		Since:        time.Unix(1772200000, 0),
+		Until:        time.Unix(1772300000, 0),
+		Jobs: []db.Job{{
+			ID: "je", Title: "Junior Engineer <Civil>", Department: "SSC", Location: "Delhi",
+			URL: "https://ssc.gov.in/je.pdf", Vacancies: 12, ClosingDate: "2026-03-15", FirstSeenAt: 1772250000,
+		}},
+	}
+}
+
+func TestRender_HTML(t *testing.T) {
+	var b bytes.Buffer
+	if err := Render(&b, testDigest(FormatHTML)); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+	for _, want := range []string{
+		"<h1>1 new job for Engineering</h1>",
+		`<a href="https://ssc.gov.in/je.pdf">Junior Engineer &lt;Civil&gt;</a>`,
+		"SSC — Delhi",
+		"Vacancies: 12",
+		"Apply by: 2026-03-15",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("HTML missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestRender_Text(t *testing.T) {
+	var b bytes.Buffer
+	if err := Render(&b, testDigest(FormatText)); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+	for _, want := range []string{
+		"1 new job for Engineering\n",
+		"* Junior Engineer <Civil>\n  SSC — Delhi\n  Vacancies: 12\n  Apply by: 2026-03-15\n  https://ssc.gov.in/je.pdf\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("text missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestRender_JSON(t *testing.T) {
+	var b bytes.Buffer
+	if err := Render(&b, testDigest(FormatJSON)); err != nil {
+		t.Fatal(err)
+	}
+	var got jsonDigest
+	if err := json.Unmarshal(b.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Subscriber != "a@example.org" || len(got.Jobs) != 1 || got.Jobs[0].Vacancies != 12 {
+		t.Errorf("unexpected digest %+v", got)
+	}
+}
+
+func TestRender_UnknownFormat(t *testing.T) {
+	if err := Render(&bytes.Buffer{}, testDigest("pdf")); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
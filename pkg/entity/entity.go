@@ -0,0 +1,343 @@
+// Package entity maps the department and location names boards write to
+// canonical entities: a ministry → department → organisation hierarchy, and
+// India → state or union territory → city. "M/o Electronics & IT", "MeitY"
+// and "Ministry of Electronics and Information Technology" are all the
+// entity "meity".
+//
+// An entity's ID is the path of slugs from its root, e.g. "meity/nic" or
+// "in/br/patna", so everything under an entity shares its ID as a prefix.
+// The bundled dataset lives in data/; Default loads it.
+//
+// Migration 9 in pkg/db keeps its own copy of the matching rules and dataset
+// as released.
+package entity
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Kind is the level of an entity in its hierarchy.
+type Kind string
+
+const (
+	KindMinistry       Kind = "ministry"
+	KindDepartment     Kind = "department"
+	KindOrganisation   Kind = "organisation"
+	KindCountry        Kind = "country"
+	KindState          Kind = "state"
+	KindUnionTerritory Kind = "union_territory"
+	KindCity           Kind = "city"
+)
+
+// Entity is a canonical department or location.
+type Entity struct {
+	ID      string // Slug path from the root, e.g. "meity/nic"
+	Name    string // Canonical name
+	Kind    Kind
+	Parent  string // ID of the parent entity; "" at the top
+	Aliases []string
+}
+
+// Depth is the number of ancestors of e.
+func (e Entity) Depth() int {
+	return strings.Count(e.ID, "/")
+}
+
+// Within reports whether id is ancestor or one of its descendants.
+func Within(id, ancestor string) bool {
+	return id == ancestor || strings.HasPrefix(id, ancestor+"/")
+}
+
+// Catalog is a loaded dataset. Safe for concurrent use.
+type Catalog struct {
+	entities    map[string]Entity
+	departments *index
+	locations   *index
+}
+
+//go:embed data/departments.json data/locations.json
+var dataset embed.FS
+
+// Default returns the catalog of the bundled dataset.
+var Default = sync.OnceValue(func() *Catalog {
+	departments, err := dataset.Open("data/departments.json")
+	if err != nil {
+		panic(err)
+	}
+	defer departments.Close()
+	locations, err := dataset.Open("data/locations.json")
+	if err != nil {
+		panic(err)
+	}
+	defer locations.Close()
+
+	c, err := Load(departments, locations)
+	if err != nil {
+		panic(fmt.Sprintf("bundled entity dataset: %v", err))
+	}
+	return c
+})
+
+// node is an entity as written in a dataset file.
+type node struct {
+	ID       string   `json:"id"`
+	Name     string   `json:"name"`
+	Kind     Kind     `json:"kind"`
+	Aliases  []string `json:"aliases"`
+	Children []node   `json:"children"`
+}
+
+// Load reads department and location datasets: JSON arrays of
+// {"id", "name", "kind", "aliases", "children"} trees. IDs are slugs unique
+// among their siblings, and no two entities of a dataset may share a name or
+// alias. All problems are reported together.
+func Load(departments, locations io.Reader) (*Catalog, error) {
+	c := &Catalog{entities: make(map[string]Entity)}
+	var errs []error
+	var err error
+	if c.departments, err = c.load("departments", departments); err != nil {
+		errs = append(errs, err)
+	}
+	if c.locations, err = c.load("locations", locations); err != nil {
+		errs = append(errs, err)
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return c, nil
+}
+
+// load adds one dataset's entities to c and indexes their names.
+func (c *Catalog) load(name string, r io.Reader) (*index, error) {
+	var roots []node
+	if err := json.NewDecoder(r).Decode(&roots); err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+
+	idx := &index{exact: make(map[string]string)}
+	var errs []error
+	var walk func(n node, parent string)
+	walk = func(n node, parent string) {
+		id := n.ID
+		if parent != "" {
+			id = parent + "/" + n.ID
+		}
+		switch {
+		case n.ID == "" || strings.ContainsAny(n.ID, "/ "):
+			errs = append(errs, fmt.Errorf("%s: %q under %q: id must be a non-empty slug", name, n.ID, parent))
+			return
+		case n.Name == "":
+			errs = append(errs, fmt.Errorf("%s: %s: missing name", name, id))
+		}
+		if _, dup := c.entities[id]; dup {
+			errs = append(errs, fmt.Errorf("%s: duplicate id %s", name, id))
+			return
+		}
+
+		e := Entity{ID: id, Name: n.Name, Kind: n.Kind, Parent: parent, Aliases: n.Aliases}
+		c.entities[id] = e
+		for _, a := range append([]string{n.Name}, n.Aliases...) {
+			if err := idx.add(a, e); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			}
+		}
+		for _, child := range n.Children {
+			walk(child, id)
+		}
+	}
+	for _, root := range roots {
+		walk(root, "")
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	idx.sort()
+	return idx, nil
+}
+
+// Get returns the entity with the given ID.
+func (c *Catalog) Get(id string) (Entity, bool) {
+	e, ok := c.entities[id]
+	return e, ok
+}
+
+// Path returns the entity with the given ID and its ancestors, root first.
+func (c *Catalog) Path(id string) []Entity {
+	var path []Entity
+	for id != "" {
+		e, ok := c.entities[id]
+		if !ok {
+			break
+		}
+		path = append([]Entity{e}, path...)
+		id = e.Parent
+	}
+	return path
+}
+
+// Department returns the department named by raw. See match.
+func (c *Catalog) Department(raw string) (Entity, bool) {
+	return c.match(c.departments, raw)
+}
+
+// Location returns the location named by raw. See match.
+func (c *Catalog) Location(raw string) (Entity, bool) {
+	return c.match(c.locations, raw)
+}
+
+// match returns the entity raw names outright, else the most specific entity
+// mentioned in it: "NIC State Centre, Patna, Bihar" is Patna. Abbreviations
+// of two letters, such as "UP", only match on their own so "up to 27 years"
+// stays unmatched.
+func (c *Catalog) match(idx *index, raw string) (Entity, bool) {
+	id, ok := idx.find(Key(raw))
+	if !ok {
+		return Entity{}, false
+	}
+	return c.entities[id], true
+}
+
+// index finds entities by name.
+type index struct {
+	exact   map[string]string // Key of every name and alias → ID
+	aliases []alias           // Names found inside longer text, longest first
+}
+
+type alias struct {
+	tokens []string
+	id     string
+	depth  int
+}
+
+// minContainedLen is the shortest alias, in letters, found inside longer text.
+const minContainedLen = 3
+
+func (idx *index) add(name string, e Entity) error {
+	key := Key(name)
+	if key == "" {
+		return fmt.Errorf("%s: empty alias", e.ID)
+	}
+	if other, dup := idx.exact[key]; dup {
+		if other == e.ID {
+			return nil
+		}
+		return fmt.Errorf("%q names both %s and %s", name, other, e.ID)
+	}
+	idx.exact[key] = e.ID
+	if utf8.RuneCountInString(strings.ReplaceAll(key, " ", "")) >= minContainedLen {
+		idx.aliases = append(idx.aliases, alias{tokens: strings.Fields(key), id: e.ID, depth: e.Depth()})
+	}
+	return nil
+}
+
+func (idx *index) sort() {
+	sort.SliceStable(idx.aliases, func(i, j int) bool {
+		return len(idx.aliases[i].tokens) > len(idx.aliases[j].tokens)
+	})
+}
+
+// find looks key up as a whole, else picks among the aliases found in it:
+// longer aliases claim their words first ("New Delhi" before "Delhi"), then
+// the deepest entity wins, then the longest alias, then the earliest.
+func (idx *index) find(key string) (string, bool) {
+	if key == "" {
+		return "", false
+	}
+	if id, ok := idx.exact[key]; ok {
+		return id, true
+	}
+
+	tokens := strings.Fields(key)
+	claimed := make([]bool, len(tokens))
+	var best *alias
+	bestAt := 0
+	for i := range idx.aliases {
+		a := &idx.aliases[i]
+		at := indexTokens(tokens, a.tokens, claimed)
+		if at < 0 {
+			continue
+		}
+		for j := range a.tokens {
+			claimed[at+j] = true
+		}
+		if best == nil || a.depth > best.depth ||
+			(a.depth == best.depth && len(a.tokens) > len(best.tokens)) ||
+			(a.depth == best.depth && len(a.tokens) == len(best.tokens) && at < bestAt) {
+			best, bestAt = a, at
+		}
+	}
+	if best == nil {
+		return "", false
+	}
+	return best.id, true
+}
+
+// indexTokens returns where needle first occurs in tokens without touching a
+// claimed token, or -1.
+func indexTokens(tokens, needle []string, claimed []bool) int {
+outer:
+	for i := 0; i+len(needle) <= len(tokens); i++ {
+		for j, t := range needle {
+			if claimed[i+j] || tokens[i+j] != t {
+				continue outer
+			}
+		}
+		return i
+	}
+	return -1
+}
+
+var (
+	// ofAbbrevRegex matches "M/o", "D/o" and "O/o".
+	ofAbbrevRegex = regexp.MustCompile(`(?i)\b([mdo])/o\b`)
+
+	ofAbbrevs = map[string]string{"m": " ministry of ", "d": " department of ", "o": " office of "}
+
+	// synonyms rewrite words to the spelling aliases are keyed by.
+	synonyms = map[string]string{
+		"govt":         "government",
+		"dept":         "department",
+		"deptt":        "department",
+		"it":           "information technology",
+		"center":       "centre",
+		"organization": "organisation",
+		"the":          "",
+	}
+)
+
+// Key normalises a name for lookup: lower case, "&" as "and", "M/o" as
+// "ministry of", common abbreviations spelled out and punctuation removed.
+func Key(s string) string {
+	s = strings.ToLower(s)
+	s = ofAbbrevRegex.ReplaceAllStringFunc(s, func(m string) string {
+		return ofAbbrevs[m[:1]]
+	})
+	s = strings.ReplaceAll(s, "&", " and ")
+	s = strings.ReplaceAll(s, "\u093c", "") // Devanagari nukta
+
+	words := strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && !unicode.IsMark(r)
+	})
+	out := words[:0]
+	for _, w := range words {
+		if syn, ok := synonyms[w]; ok {
+			if syn == "" {
+				continue
+			}
+			w = syn
+		}
+		out = append(out, w)
+	}
+	return strings.Join(out, " ")
+}
@@ -0,0 +1,112 @@
+package entity
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDefault_Department(t *testing.T) {
+	tests := map[string]string{
+		"M/o Electronics & IT":                               "meity",
+		"Ministry of Electronics and Information Technology": "meity",
+		"MeitY":                                 "meity",
+		"NIC":                                   "meity/nic",
+		"National Informatics Center":           "meity/nic",
+		"Staff Selection Commission":            "mopgp/dopt/ssc",
+		"D/o Personnel & Training":              "mopgp/dopt",
+		"Govt. of India, Ministry of Railways":  "railways",
+		"NIC, Ministry of Electronics & IT":     "meity/nic",
+		"Bhabha Atomic Research Centre, Mumbai": "dae/barc",
+		"कर्मचारी चयन आयोग":                     "mopgp/dopt/ssc",
+		"Municipal Corporation of Greater Pune": "",
+		"":                                      "",
+	}
+	for raw, want := range tests {
+		got, ok := Default().Department(raw)
+		if got.ID != want || ok != (want != "") {
+			t.Errorf("Department(%q) = %q, %v; want %q", raw, got.ID, ok, want)
+		}
+	}
+}
+
+func TestDefault_Location(t *testing.T) {
+	tests := map[string]string{
+		"All India":                      "in",
+		"Pan India":                      "in",
+		"UP":                             "in/up",
+		"U.P.":                           "in/up",
+		"Orissa":                         "in/od",
+		"Bombay":                         "in/mh/mumbai",
+		"Bangalore, Karnataka":           "in/ka/bengaluru",
+		"NIC State Centre, Patna, Bihar": "in/br/patna",
+		"New Delhi":                      "in/dl/new-delhi",
+		"NCT of Delhi":                   "in/dl",
+		"J&K":                            "in/jk",
+		"Jammu & Kashmir":                "in/jk",
+		"पटना":                           "in/br/patna",
+		"age up to 27 years":             "",
+		"Head Office":                    "",
+	}
+	for raw, want := range tests {
+		got, ok := Default().Location(raw)
+		if got.ID != want || ok != (want != "") {
+			t.Errorf("Location(%q) = %q, %v; want %q", raw, got.ID, ok, want)
+		}
+	}
+}
+
+func TestCatalog_Path(t *testing.T) {
+	path := Default().Path("mopgp/dopt/ssc")
+	var names []string
+	for _, e := range path {
+		names = append(names, e.Name)
+	}
+	want := "Ministry of Personnel, Public Grievances and Pensions > Department of Personnel and Training > Staff Selection Commission"
+	if got := strings.Join(names, " > "); got != want {
+		t.Errorf("Path = %q, want %q", got, want)
+	}
+	if path[2].Kind != KindOrganisation || path[2].Parent != "mopgp/dopt" {
+		t.Errorf("unexpected entity %+v", path[2])
+	}
+}
+
+func TestWithin(t *testing.T) {
+	if !Within("in/br/patna", "in/br") || !Within("in/br", "in/br") {
+		t.Error("expected Patna within Bihar")
+	}
+	if Within("in/brx", "in/br") || Within("in/br", "in/br/patna") {
+		t.Error("unexpected match across siblings or upwards")
+	}
+}
+
+func TestKey(t *testing.T) {
+	tests := map[string]string{
+		"M/o Electronics & IT":        "ministry of electronics and information technology",
+		"Govt. of India":              "government of india",
+		"  The  Deptt. of Posts":      "department of posts",
+		"National Informatics Center": "national informatics centre",
+	}
+	for in, want := range tests {
+		if got := Key(in); got != want {
+			t.Errorf("Key(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestLoad_ReportsAllProblems(t *testing.T) {
+	departments := `[
+		{"id": "a", "name": "Alpha", "aliases": ["Shared"]},
+		{"id": "b", "name": "Beta", "aliases": ["shared"]},
+		{"id": "a", "name": "Alpha again"},
+		{"id": "bad/id", "name": "Bad"}
+	]`
+	_, err := Load(strings.NewReader(departments), strings.NewReader(`[{"id": "x"}]`))
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	for _, want := range []string{`"shared" names both a and b`, "duplicate id a", `"bad/id"`, "x: missing name"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %q", err, want)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+// Package jobid derives job identities: a stable ID from the posting's
+// canonical URL, and a content fingerprint that matches the same vacancy
+// listed under different URLs or on different boards.
+//
+// It has no dependencies on the rest of the module. Migration 6 in pkg/db
+// keeps its own copy of the canonicalisation rules as released.
+package jobid
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// trackingParams are query parameters that never identify a document.
+var trackingParams = map[string]bool{
+	"fbclid":     true,
+	"gclid":      true,
+	"jsessionid": true,
+	"phpsessid":  true,
+}
+
+// CanonicalURL normalises rawURL so trivially different spellings of the same
+// link compare equal: the scheme becomes https, host is lowercased without
+// "www." or a default port, the fragment, session and utm_* tracking
+// parameters are dropped, remaining query parameters are sorted and a
+// trailing slash is removed. The path keeps its case, since servers may not
+// ignore it. Unparseable or relative URLs are returned trimmed.
+func CanonicalURL(rawURL string) string {
+	rawURL = strings.TrimSpace(rawURL)
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return rawURL
+	}
+
+	if u.Scheme == "http" || u.Scheme == "https" {
+		u.Scheme = "https"
+	}
+	host := strings.ToLower(u.Hostname())
+	host = strings.TrimPrefix(host, "www.")
+	if port := u.Port(); port != "" && port != "80" && port != "443" {
+		host += ":" + port
+	}
+	u.Host = host
+	u.User = nil
+	u.Fragment = ""
+	u.RawFragment = ""
+
+	// Java servlets append the session to the path: /a.pdf;jsessionid=...
+	if i := strings.Index(strings.ToLower(u.Path), ";jsessionid="); i >= 0 {
+		u.Path = u.Path[:i]
+		u.RawPath = ""
+	}
+	if len(u.Path) > 1 {
+		u.Path = strings.TrimRight(u.Path, "/")
+		u.RawPath = strings.TrimRight(u.RawPath, "/")
+	}
+	if u.Path == "/" {
+		u.Path = ""
+	}
+
+	query := u.Query()
+	for key := range query {
+		lower := strings.ToLower(key)
+		if trackingParams[lower] || strings.HasPrefix(lower, "utm_") {
+			query.Del(key)
+		}
+	}
+	u.RawQuery = query.Encode() // Encode sorts by key
+	u.ForceQuery = false
+
+	return u.String()
+}
+
+// FromURL returns the job ID for a posting link: 32 hex characters of the
+// SHA-256 of its canonical URL, safe for use as a primary key.
+func FromURL(rawURL string) string {
+	hash := sha256.Sum256([]byte(CanonicalURL(rawURL)))
+	return hex.EncodeToString(hash[:16])
+}
+
+// stopWords carry no information about which vacancy a title describes.
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "for": true, "in": true,
+	"of": true, "the": true, "to": true,
+	"advertisement": true, "advt": true, "apply": true, "notice": true,
+	"notification": true, "online": true, "post": true, "posts": true,
+	"recruitment": true, "vacancies": true, "vacancy": true,
+}
+
+// Fingerprint returns a content hash of a posting for duplicate detection,
+// or "" when title has no meaningful words. Titles and departments are
+// lowercased, stripped of punctuation and stop words, and their words sorted,
+// so "Recruitment of Junior Engineer (Civil)" and "Junior Engineer - Civil
+// Posts" match. date should be the closing date when known, else the
+// posting date, in YYYY-MM-DD.
+func Fingerprint(title, department, date string) string {
+	titleKey := normalise(title)
+	if titleKey == "" {
+		return ""
+	}
+	key := titleKey + "|" + normalise(department) + "|" + strings.TrimSpace(date)
+	hash := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(hash[:16])
+}
+
+// normalise reduces text to its sorted, de-duplicated significant words.
+func normalise(text string) string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	seen := make(map[string]bool, len(words))
+	kept := words[:0]
+	for _, w := range words {
+		if stopWords[w] || seen[w] {
+			continue
+		}
+		seen[w] = true
+		kept = append(kept, w)
+	}
+	sort.Strings(kept)
+	return strings.Join(kept, " ")
+}
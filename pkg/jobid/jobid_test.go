@@ -0,0 +1,78 @@
+package jobid
+
+import "testing"
+
+func TestCanonicalURL(t *testing.T) {
+	tests := map[string]string{
+		"https://example.gov.in/advt/123.pdf":                        "https://example.gov.in/advt/123.pdf",
+		"http://example.gov.in/advt/123.pdf":                         "https://example.gov.in/advt/123.pdf",
+		"https://WWW.Example.gov.in:443/advt/123.pdf":                "https://example.gov.in/advt/123.pdf",
+		"https://example.gov.in/advt/":                               "https://example.gov.in/advt",
+		"https://example.gov.in/":                                    "https://example.gov.in",
+		"https://example.gov.in/advt/123.pdf#page=2":                 "https://example.gov.in/advt/123.pdf",
+		"https://example.gov.in/view?b=2&a=1":                        "https://example.gov.in/view?a=1&b=2",
+		"https://example.gov.in/view?id=7&utm_source=x&fbclid=y":     "https://example.gov.in/view?id=7",
+		"https://example.gov.in/advt/123.pdf;jsessionid=ABC":         "https://example.gov.in/advt/123.pdf",
+		"https://example.gov.in:8080/Advt/123.PDF":                   "https://example.gov.in:8080/Advt/123.PDF",
+		"  https://example.gov.in/advt/123.pdf  ":                    "https://example.gov.in/advt/123.pdf",
+		"/relative/link.pdf":                                         "/relative/link.pdf",
+		"https://example.gov.in/search?q=junior+engineer&utm_medium": "https://example.gov.in/search?q=junior+engineer",
+	}
+	for in, want := range tests {
+		if got := CanonicalURL(in); got != want {
+			t.Errorf("CanonicalURL(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestFromURL_SameForEquivalentURLs(t *testing.T) {
+	id := FromURL("https://example.gov.in/advt/123.pdf")
+	for _, u := range []string{
+		"http://example.gov.in/advt/123.pdf",
+		"https://www.example.gov.in/advt/123.pdf/",
+		"https://example.gov.in/advt/123.pdf?utm_campaign=jobs",
+	} {
+		if got := FromURL(u); got != id {
+			t.Errorf("FromURL(%q) = %s, want %s", u, got, id)
+		}
+	}
+	if FromURL("https://example.gov.in/advt/124.pdf") == id {
+		t.Error("different documents must get different IDs")
+	}
+	if len(id) != 32 {
+		t.Errorf("expected 32 hex chars, got %d", len(id))
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	base := Fingerprint("Recruitment of Junior Engineer (Civil)", "Staff Selection Commission", "2026-03-15")
+	if base == "" {
+		t.Fatal("expected a fingerprint")
+	}
+
+	same := []struct{ title, department, date string }{
+		{"Junior Engineer - Civil Posts", "Staff Selection Commission", "2026-03-15"},
+		{"JUNIOR ENGINEER (CIVIL)", "staff selection commission", "2026-03-15"},
+		{"Civil Junior Engineer vacancy", "Staff  Selection  Commission.", " 2026-03-15 "},
+	}
+	for _, s := range same {
+		if got := Fingerprint(s.title, s.department, s.date); got != base {
+			t.Errorf("Fingerprint(%q, %q, %q) should match", s.title, s.department, s.date)
+		}
+	}
+
+	different := []struct{ title, department, date string }{
+		{"Junior Engineer (Electrical)", "Staff Selection Commission", "2026-03-15"},
+		{"Junior Engineer (Civil)", "Union Public Service Commission", "2026-03-15"},
+		{"Junior Engineer (Civil)", "Staff Selection Commission", "2026-04-01"},
+	}
+	for _, d := range different {
+		if got := Fingerprint(d.title, d.department, d.date); got == base {
+			t.Errorf("Fingerprint(%q, %q, %q) should differ", d.title, d.department, d.date)
+		}
+	}
+
+	if got := Fingerprint("Recruitment Notification", "SSC", "2026-03-15"); got != "" {
+		t.Errorf("expected no fingerprint for a title of stop words, got %q", got)
+	}
+}
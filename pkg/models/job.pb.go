@@ -9,6 +9,7 @@ package models
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -21,6 +22,63 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// ApplicationMode is how candidates apply for a job.
+type ApplicationMode int32
+
+const (
+	// Not stated.
+	ApplicationMode_APPLICATION_MODE_UNSPECIFIED ApplicationMode = 0
+	// Through an online portal.
+	ApplicationMode_APPLICATION_MODE_ONLINE ApplicationMode = 1
+	// By post or in person.
+	ApplicationMode_APPLICATION_MODE_OFFLINE ApplicationMode = 2
+	// Either online or offline.
+	ApplicationMode_APPLICATION_MODE_BOTH ApplicationMode = 3
+)
+
+// Enum value maps for ApplicationMode.
+var (
+	ApplicationMode_name = map[int32]string{
+		0: "APPLICATION_MODE_UNSPECIFIED",
+		1: "APPLICATION_MODE_ONLINE",
+		2: "APPLICATION_MODE_OFFLINE",
+		3: "APPLICATION_MODE_BOTH",
+	}
+	ApplicationMode_value = map[string]int32{
+		"APPLICATION_MODE_UNSPECIFIED": 0,
+		"APPLICATION_MODE_ONLINE":      1,
+		"APPLICATION_MODE_OFFLINE":     2,
+		"APPLICATION_MODE_BOTH":        3,
+	}
+)
+
+func (x ApplicationMode) Enum() *ApplicationMode {
+	p := new(ApplicationMode)
+	*p = x
+	return p
+}
+
+func (x ApplicationMode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ApplicationMode) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_job_proto_enumTypes[0].Descriptor()
+}
+
+func (ApplicationMode) Type() protoreflect.EnumType {
+	return &file_proto_job_proto_enumTypes[0]
+}
+
+func (x ApplicationMode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ApplicationMode.Descriptor instead.
+func (ApplicationMode) EnumDescriptor() ([]byte, []int) {
+	return file_proto_job_proto_rawDescGZIP(), []int{0}
+}
+
 // JobPosting represents a single job scraping result.
 type JobPosting struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
@@ -34,8 +92,46 @@ type JobPosting struct {
 	Location string `protobuf:"bytes,4,opt,name=location,proto3" json:"location,omitempty"`
 	// URL to the job posting or PDF.
 	Url string `protobuf:"bytes,5,opt,name=url,proto3" json:"url,omitempty"`
-	// Date of the job posting or deadline.
-	Date          string `protobuf:"bytes,6,opt,name=date,proto3" json:"date,omitempty"`
+	// Posting date (YYYY-MM-DD), when the listing states one; see posted_at.
+	Date string `protobuf:"bytes,6,opt,name=date,proto3" json:"date,omitempty"`
+	// Last date to apply (YYYY-MM-DD), when stated; see closing_at.
+	LastDate string `protobuf:"bytes,7,opt,name=last_date,json=lastDate,proto3" json:"last_date,omitempty"`
+	// Advertisement number as printed by the recruiting body.
+	AdvertisementNo string `protobuf:"bytes,8,opt,name=advertisement_no,json=advertisementNo,proto3" json:"advertisement_no,omitempty"`
+	// Number of vacancies stated in the advertisement PDF (0 = unknown).
+	Vacancies int32 `protobuf:"varint,9,opt,name=vacancies,proto3" json:"vacancies,omitempty"`
+	// Pay level in the 7th CPC pay matrix, e.g. "Level 7".
+	PayLevel string `protobuf:"bytes,10,opt,name=pay_level,json=payLevel,proto3" json:"pay_level,omitempty"`
+	// Age limit as stated in the advertisement, e.g. "18-27 years".
+	AgeLimit string `protobuf:"bytes,11,opt,name=age_limit,json=ageLimit,proto3" json:"age_limit,omitempty"`
+	// Unix timestamp of when the job was first scraped; resumes WatchJobs.
+	FirstSeenAt int64 `protobuf:"varint,12,opt,name=first_seen_at,json=firstSeenAt,proto3" json:"first_seen_at,omitempty"`
+	// Posting date as a timestamp (midnight UTC); unset when unknown.
+	PostedAt *timestamppb.Timestamp `protobuf:"bytes,13,opt,name=posted_at,json=postedAt,proto3" json:"posted_at,omitempty"`
+	// Last date to apply as a timestamp (midnight UTC); unset when unknown.
+	ClosingAt *timestamppb.Timestamp `protobuf:"bytes,14,opt,name=closing_at,json=closingAt,proto3" json:"closing_at,omitempty"`
+	// Qualifications the advertisement asks for, e.g. "Diploma", "B.E./B.Tech".
+	Qualifications []string `protobuf:"bytes,15,rep,name=qualifications,proto3" json:"qualifications,omitempty"`
+	// Pay scale or band as printed, e.g. "Rs. 35,400-1,12,400".
+	PayScale string `protobuf:"bytes,16,opt,name=pay_scale,json=payScale,proto3" json:"pay_scale,omitempty"`
+	// Minimum age in years (0 = not stated).
+	MinAge int32 `protobuf:"varint,17,opt,name=min_age,json=minAge,proto3" json:"min_age,omitempty"`
+	// Maximum age in years (0 = not stated).
+	MaxAge int32 `protobuf:"varint,18,opt,name=max_age,json=maxAge,proto3" json:"max_age,omitempty"`
+	// How applications are submitted.
+	ApplicationMode ApplicationMode `protobuf:"varint,19,opt,name=application_mode,json=applicationMode,proto3,enum=models.ApplicationMode" json:"application_mode,omitempty"`
+	// Name of the source the job was scraped from, e.g. "nic".
+	Source string `protobuf:"bytes,20,opt,name=source,proto3" json:"source,omitempty"`
+	// Other documents linked from the listing, e.g. corrigenda.
+	Attachments []*Attachment `protobuf:"bytes,21,rep,name=attachments,proto3" json:"attachments,omitempty"`
+	// How last_date was read: "high" or "medium" when stated with a four- or
+	// two-digit year, "low" when inferred, e.g. counted from the posting
+	// date; "" when unknown. Jobs are not expired on a "low" date.
+	LastDateConfidence string `protobuf:"bytes,22,opt,name=last_date_confidence,json=lastDateConfidence,proto3" json:"last_date_confidence,omitempty"`
+	// Normalised department, e.g. "meity/nic"; "" when not recognised.
+	DepartmentId string `protobuf:"bytes,23,opt,name=department_id,json=departmentId,proto3" json:"department_id,omitempty"`
+	// Normalised location, e.g. "in/br/patna"; "" when not recognised.
+	LocationId    string `protobuf:"bytes,24,opt,name=location_id,json=locationId,proto3" json:"location_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -112,6 +208,187 @@ func (x *JobPosting) GetDate() string {
 	return ""
 }
 
+func (x *JobPosting) GetLastDate() string {
+	if x != nil {
+		return x.LastDate
+	}
+	return ""
+}
+
+func (x *JobPosting) GetAdvertisementNo() string {
+	if x != nil {
+		return x.AdvertisementNo
+	}
+	return ""
+}
+
+func (x *JobPosting) GetVacancies() int32 {
+	if x != nil {
+		return x.Vacancies
+	}
+	return 0
+}
+
+func (x *JobPosting) GetPayLevel() string {
+	if x != nil {
+		return x.PayLevel
+	}
+	return ""
+}
+
+func (x *JobPosting) GetAgeLimit() string {
+	if x != nil {
+		return x.AgeLimit
+	}
+	return ""
+}
+
+func (x *JobPosting) GetFirstSeenAt() int64 {
+	if x != nil {
+		return x.FirstSeenAt
+	}
+	return 0
+}
+
+func (x *JobPosting) GetPostedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.PostedAt
+	}
+	return nil
+}
+
+func (x *JobPosting) GetClosingAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ClosingAt
+	}
+	return nil
+}
+
+func (x *JobPosting) GetQualifications() []string {
+	if x != nil {
+		return x.Qualifications
+	}
+	return nil
+}
+
+func (x *JobPosting) GetPayScale() string {
+	if x != nil {
+		return x.PayScale
+	}
+	return ""
+}
+
+func (x *JobPosting) GetMinAge() int32 {
+	if x != nil {
+		return x.MinAge
+	}
+	return 0
+}
+
+func (x *JobPosting) GetMaxAge() int32 {
+	if x != nil {
+		return x.MaxAge
+	}
+	return 0
+}
+
+func (x *JobPosting) GetApplicationMode() ApplicationMode {
+	if x != nil {
+		return x.ApplicationMode
+	}
+	return ApplicationMode_APPLICATION_MODE_UNSPECIFIED
+}
+
+func (x *JobPosting) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *JobPosting) GetAttachments() []*Attachment {
+	if x != nil {
+		return x.Attachments
+	}
+	return nil
+}
+
+func (x *JobPosting) GetLastDateConfidence() string {
+	if x != nil {
+		return x.LastDateConfidence
+	}
+	return ""
+}
+
+func (x *JobPosting) GetDepartmentId() string {
+	if x != nil {
+		return x.DepartmentId
+	}
+	return ""
+}
+
+func (x *JobPosting) GetLocationId() string {
+	if x != nil {
+		return x.LocationId
+	}
+	return ""
+}
+
+// Attachment is a document linked from a job listing.
+type Attachment struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Link text, e.g. "Corrigendum".
+	Title string `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
+	// Absolute URL of the document.
+	Url           string `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Attachment) Reset() {
+	*x = Attachment{}
+	mi := &file_proto_job_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Attachment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Attachment) ProtoMessage() {}
+
+func (x *Attachment) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_job_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Attachment.ProtoReflect.Descriptor instead.
+func (*Attachment) Descriptor() ([]byte, []int) {
+	return file_proto_job_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Attachment) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *Attachment) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
 // JobList is a wrapper for a list of jobs, suitable for serialization.
 type JobList struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
@@ -125,7 +402,7 @@ type JobList struct {
 
 func (x *JobList) Reset() {
 	*x = JobList{}
-	mi := &file_proto_job_proto_msgTypes[1]
+	mi := &file_proto_job_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -137,7 +414,7 @@ func (x *JobList) String() string {
 func (*JobList) ProtoMessage() {}
 
 func (x *JobList) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_job_proto_msgTypes[1]
+	mi := &file_proto_job_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -150,7 +427,7 @@ func (x *JobList) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use JobList.ProtoReflect.Descriptor instead.
 func (*JobList) Descriptor() ([]byte, []int) {
-	return file_proto_job_proto_rawDescGZIP(), []int{1}
+	return file_proto_job_proto_rawDescGZIP(), []int{2}
 }
 
 func (x *JobList) GetJobs() []*JobPosting {
@@ -167,11 +444,525 @@ func (x *JobList) GetLastUpdated() int64 {
 	return 0
 }
 
+// ListJobsRequest filters and pages ListJobs. Empty fields do not filter.
+type ListJobsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Full-text query; results are ranked by relevance.
+	Query string `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	// Case-insensitive substring of the department.
+	Department string `protobuf:"bytes,2,opt,name=department,proto3" json:"department,omitempty"`
+	// Case-insensitive substring of the location.
+	Location string `protobuf:"bytes,3,opt,name=location,proto3" json:"location,omitempty"`
+	// "active" (default), "expired", "removed" or "all".
+	Status string `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
+	// Earliest posting date, YYYY-MM-DD, inclusive.
+	PostedFrom string `protobuf:"bytes,5,opt,name=posted_from,json=postedFrom,proto3" json:"posted_from,omitempty"`
+	// Latest posting date, YYYY-MM-DD, inclusive.
+	PostedTo string `protobuf:"bytes,6,opt,name=posted_to,json=postedTo,proto3" json:"posted_to,omitempty"`
+	// Maximum number of jobs (default 50, max 200).
+	Limit int32 `protobuf:"varint,7,opt,name=limit,proto3" json:"limit,omitempty"`
+	// Jobs to skip, for paging.
+	Offset int32 `protobuf:"varint,8,opt,name=offset,proto3" json:"offset,omitempty"`
+	// Department ID; matches it and everything under it.
+	DepartmentId string `protobuf:"bytes,9,opt,name=department_id,json=departmentId,proto3" json:"department_id,omitempty"`
+	// Location ID; "in/br" includes "in/br/patna".
+	LocationId    string `protobuf:"bytes,10,opt,name=location_id,json=locationId,proto3" json:"location_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListJobsRequest) Reset() {
+	*x = ListJobsRequest{}
+	mi := &file_proto_job_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListJobsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListJobsRequest) ProtoMessage() {}
+
+func (x *ListJobsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_job_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListJobsRequest.ProtoReflect.Descriptor instead.
+func (*ListJobsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_job_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ListJobsRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *ListJobsRequest) GetDepartment() string {
+	if x != nil {
+		return x.Department
+	}
+	return ""
+}
+
+func (x *ListJobsRequest) GetLocation() string {
+	if x != nil {
+		return x.Location
+	}
+	return ""
+}
+
+func (x *ListJobsRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ListJobsRequest) GetPostedFrom() string {
+	if x != nil {
+		return x.PostedFrom
+	}
+	return ""
+}
+
+func (x *ListJobsRequest) GetPostedTo() string {
+	if x != nil {
+		return x.PostedTo
+	}
+	return ""
+}
+
+func (x *ListJobsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListJobsRequest) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *ListJobsRequest) GetDepartmentId() string {
+	if x != nil {
+		return x.DepartmentId
+	}
+	return ""
+}
+
+func (x *ListJobsRequest) GetLocationId() string {
+	if x != nil {
+		return x.LocationId
+	}
+	return ""
+}
+
+// ListJobsResponse is one page of jobs.
+type ListJobsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Jobs on this page.
+	Jobs []*JobPosting `protobuf:"bytes,1,rep,name=jobs,proto3" json:"jobs,omitempty"`
+	// Number of matching jobs across all pages.
+	Total int32 `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	// Offset of the next page; 0 on the last page.
+	NextOffset    int32 `protobuf:"varint,3,opt,name=next_offset,json=nextOffset,proto3" json:"next_offset,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListJobsResponse) Reset() {
+	*x = ListJobsResponse{}
+	mi := &file_proto_job_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListJobsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListJobsResponse) ProtoMessage() {}
+
+func (x *ListJobsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_job_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListJobsResponse.ProtoReflect.Descriptor instead.
+func (*ListJobsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_job_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListJobsResponse) GetJobs() []*JobPosting {
+	if x != nil {
+		return x.Jobs
+	}
+	return nil
+}
+
+func (x *ListJobsResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *ListJobsResponse) GetNextOffset() int32 {
+	if x != nil {
+		return x.NextOffset
+	}
+	return 0
+}
+
+// GetJobRequest names a job.
+type GetJobRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// ID of the job.
+	Id            string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetJobRequest) Reset() {
+	*x = GetJobRequest{}
+	mi := &file_proto_job_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetJobRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetJobRequest) ProtoMessage() {}
+
+func (x *GetJobRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_job_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetJobRequest.ProtoReflect.Descriptor instead.
+func (*GetJobRequest) Descriptor() ([]byte, []int) {
+	return file_proto_job_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetJobRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// SearchJobsRequest is a full-text search.
+type SearchJobsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Words to search for; each must match, as a prefix.
+	Query string `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	// Maximum number of results (default 50, max 200).
+	Limit int32 `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	// Results to skip, for paging.
+	Offset int32 `protobuf:"varint,3,opt,name=offset,proto3" json:"offset,omitempty"`
+	// Also return expired and removed jobs.
+	IncludeInactive bool `protobuf:"varint,4,opt,name=include_inactive,json=includeInactive,proto3" json:"include_inactive,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *SearchJobsRequest) Reset() {
+	*x = SearchJobsRequest{}
+	mi := &file_proto_job_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchJobsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchJobsRequest) ProtoMessage() {}
+
+func (x *SearchJobsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_job_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchJobsRequest.ProtoReflect.Descriptor instead.
+func (*SearchJobsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_job_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *SearchJobsRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *SearchJobsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *SearchJobsRequest) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *SearchJobsRequest) GetIncludeInactive() bool {
+	if x != nil {
+		return x.IncludeInactive
+	}
+	return false
+}
+
+// SearchJobsResponse lists search results, best match first.
+type SearchJobsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Matching jobs.
+	Results       []*SearchResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchJobsResponse) Reset() {
+	*x = SearchJobsResponse{}
+	mi := &file_proto_job_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchJobsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchJobsResponse) ProtoMessage() {}
+
+func (x *SearchJobsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_job_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchJobsResponse.ProtoReflect.Descriptor instead.
+func (*SearchJobsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_job_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *SearchJobsResponse) GetResults() []*SearchResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+// SearchResult is one job matching a search.
+type SearchResult struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The matching job.
+	Job *JobPosting `protobuf:"bytes,1,opt,name=job,proto3" json:"job,omitempty"`
+	// bm25 score; lower is better.
+	Rank float64 `protobuf:"fixed64,2,opt,name=rank,proto3" json:"rank,omitempty"`
+	// Matching excerpt with hits wrapped in [ and ].
+	Snippet       string `protobuf:"bytes,3,opt,name=snippet,proto3" json:"snippet,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchResult) Reset() {
+	*x = SearchResult{}
+	mi := &file_proto_job_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchResult) ProtoMessage() {}
+
+func (x *SearchResult) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_job_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchResult.ProtoReflect.Descriptor instead.
+func (*SearchResult) Descriptor() ([]byte, []int) {
+	return file_proto_job_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *SearchResult) GetJob() *JobPosting {
+	if x != nil {
+		return x.Job
+	}
+	return nil
+}
+
+func (x *SearchResult) GetRank() float64 {
+	if x != nil {
+		return x.Rank
+	}
+	return 0
+}
+
+func (x *SearchResult) GetSnippet() string {
+	if x != nil {
+		return x.Snippet
+	}
+	return ""
+}
+
+// WatchJobsRequest starts a stream of new postings.
+type WatchJobsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Unix timestamp; jobs first seen after it are sent. 0 streams only
+	// jobs seen after the call. To resume, pass the first_seen_at of the
+	// last job received.
+	Since int64 `protobuf:"varint,1,opt,name=since,proto3" json:"since,omitempty"`
+	// Case-insensitive substring of the department.
+	Department string `protobuf:"bytes,2,opt,name=department,proto3" json:"department,omitempty"`
+	// Case-insensitive substring of the location.
+	Location string `protobuf:"bytes,3,opt,name=location,proto3" json:"location,omitempty"`
+	// ID of the last job received at since, to resume a stream without
+	// missing jobs first seen in the same second.
+	AfterId string `protobuf:"bytes,4,opt,name=after_id,json=afterId,proto3" json:"after_id,omitempty"`
+	// Department ID; matches it and everything under it.
+	DepartmentId string `protobuf:"bytes,5,opt,name=department_id,json=departmentId,proto3" json:"department_id,omitempty"`
+	// Location ID; "in/br" includes "in/br/patna".
+	LocationId    string `protobuf:"bytes,6,opt,name=location_id,json=locationId,proto3" json:"location_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchJobsRequest) Reset() {
+	*x = WatchJobsRequest{}
+	mi := &file_proto_job_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchJobsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchJobsRequest) ProtoMessage() {}
+
+func (x *WatchJobsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_job_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchJobsRequest.ProtoReflect.Descriptor instead.
+func (*WatchJobsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_job_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *WatchJobsRequest) GetSince() int64 {
+	if x != nil {
+		return x.Since
+	}
+	return 0
+}
+
+func (x *WatchJobsRequest) GetDepartment() string {
+	if x != nil {
+		return x.Department
+	}
+	return ""
+}
+
+func (x *WatchJobsRequest) GetLocation() string {
+	if x != nil {
+		return x.Location
+	}
+	return ""
+}
+
+func (x *WatchJobsRequest) GetAfterId() string {
+	if x != nil {
+		return x.AfterId
+	}
+	return ""
+}
+
+func (x *WatchJobsRequest) GetDepartmentId() string {
+	if x != nil {
+		return x.DepartmentId
+	}
+	return ""
+}
+
+func (x *WatchJobsRequest) GetLocationId() string {
+	if x != nil {
+		return x.LocationId
+	}
+	return ""
+}
+
 var File_proto_job_proto protoreflect.FileDescriptor
 
 const file_proto_job_proto_rawDesc = "" +
 	"\n" +
-	"\x0fproto/job.proto\x12\x06models\"\x94\x01\n" +
+	"\x0fproto/job.proto\x12\x06models\x1a\x1fgoogle/protobuf/timestamp.proto\"\xcd\x06\n" +
 	"\n" +
 	"JobPosting\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
@@ -181,10 +972,91 @@ const file_proto_job_proto_rawDesc = "" +
 	"department\x12\x1a\n" +
 	"\blocation\x18\x04 \x01(\tR\blocation\x12\x10\n" +
 	"\x03url\x18\x05 \x01(\tR\x03url\x12\x12\n" +
-	"\x04date\x18\x06 \x01(\tR\x04date\"T\n" +
+	"\x04date\x18\x06 \x01(\tR\x04date\x12\x1b\n" +
+	"\tlast_date\x18\a \x01(\tR\blastDate\x12)\n" +
+	"\x10advertisement_no\x18\b \x01(\tR\x0fadvertisementNo\x12\x1c\n" +
+	"\tvacancies\x18\t \x01(\x05R\tvacancies\x12\x1b\n" +
+	"\tpay_level\x18\n" +
+	" \x01(\tR\bpayLevel\x12\x1b\n" +
+	"\tage_limit\x18\v \x01(\tR\bageLimit\x12\"\n" +
+	"\rfirst_seen_at\x18\f \x01(\x03R\vfirstSeenAt\x127\n" +
+	"\tposted_at\x18\r \x01(\v2\x1a.google.protobuf.TimestampR\bpostedAt\x129\n" +
+	"\n" +
+	"closing_at\x18\x0e \x01(\v2\x1a.google.protobuf.TimestampR\tclosingAt\x12&\n" +
+	"\x0equalifications\x18\x0f \x03(\tR\x0equalifications\x12\x1b\n" +
+	"\tpay_scale\x18\x10 \x01(\tR\bpayScale\x12\x17\n" +
+	"\amin_age\x18\x11 \x01(\x05R\x06minAge\x12\x17\n" +
+	"\amax_age\x18\x12 \x01(\x05R\x06maxAge\x12B\n" +
+	"\x10application_mode\x18\x13 \x01(\x0e2\x17.models.ApplicationModeR\x0fapplicationMode\x12\x16\n" +
+	"\x06source\x18\x14 \x01(\tR\x06source\x124\n" +
+	"\vattachments\x18\x15 \x03(\v2\x12.models.AttachmentR\vattachments\x120\n" +
+	"\x14last_date_confidence\x18\x16 \x01(\tR\x12lastDateConfidence\x12#\n" +
+	"\rdepartment_id\x18\x17 \x01(\tR\fdepartmentId\x12\x1f\n" +
+	"\vlocation_id\x18\x18 \x01(\tR\n" +
+	"locationId\"4\n" +
+	"\n" +
+	"Attachment\x12\x14\n" +
+	"\x05title\x18\x01 \x01(\tR\x05title\x12\x10\n" +
+	"\x03url\x18\x02 \x01(\tR\x03url\"T\n" +
 	"\aJobList\x12&\n" +
 	"\x04jobs\x18\x01 \x03(\v2\x12.models.JobPostingR\x04jobs\x12!\n" +
-	"\flast_updated\x18\x02 \x01(\x03R\vlastUpdatedB/Z-github.com/entreya/job-aggregation/pkg/modelsb\x06proto3"
+	"\flast_updated\x18\x02 \x01(\x03R\vlastUpdated\"\xad\x02\n" +
+	"\x0fListJobsRequest\x12\x14\n" +
+	"\x05query\x18\x01 \x01(\tR\x05query\x12\x1e\n" +
+	"\n" +
+	"department\x18\x02 \x01(\tR\n" +
+	"department\x12\x1a\n" +
+	"\blocation\x18\x03 \x01(\tR\blocation\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\x12\x1f\n" +
+	"\vposted_from\x18\x05 \x01(\tR\n" +
+	"postedFrom\x12\x1b\n" +
+	"\tposted_to\x18\x06 \x01(\tR\bpostedTo\x12\x14\n" +
+	"\x05limit\x18\a \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06offset\x18\b \x01(\x05R\x06offset\x12#\n" +
+	"\rdepartment_id\x18\t \x01(\tR\fdepartmentId\x12\x1f\n" +
+	"\vlocation_id\x18\n" +
+	" \x01(\tR\n" +
+	"locationId\"q\n" +
+	"\x10ListJobsResponse\x12&\n" +
+	"\x04jobs\x18\x01 \x03(\v2\x12.models.JobPostingR\x04jobs\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x05R\x05total\x12\x1f\n" +
+	"\vnext_offset\x18\x03 \x01(\x05R\n" +
+	"nextOffset\"\x1f\n" +
+	"\rGetJobRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x82\x01\n" +
+	"\x11SearchJobsRequest\x12\x14\n" +
+	"\x05query\x18\x01 \x01(\tR\x05query\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06offset\x18\x03 \x01(\x05R\x06offset\x12)\n" +
+	"\x10include_inactive\x18\x04 \x01(\bR\x0fincludeInactive\"D\n" +
+	"\x12SearchJobsResponse\x12.\n" +
+	"\aresults\x18\x01 \x03(\v2\x14.models.SearchResultR\aresults\"b\n" +
+	"\fSearchResult\x12$\n" +
+	"\x03job\x18\x01 \x01(\v2\x12.models.JobPostingR\x03job\x12\x12\n" +
+	"\x04rank\x18\x02 \x01(\x01R\x04rank\x12\x18\n" +
+	"\asnippet\x18\x03 \x01(\tR\asnippet\"\xc5\x01\n" +
+	"\x10WatchJobsRequest\x12\x14\n" +
+	"\x05since\x18\x01 \x01(\x03R\x05since\x12\x1e\n" +
+	"\n" +
+	"department\x18\x02 \x01(\tR\n" +
+	"department\x12\x1a\n" +
+	"\blocation\x18\x03 \x01(\tR\blocation\x12\x19\n" +
+	"\bafter_id\x18\x04 \x01(\tR\aafterId\x12#\n" +
+	"\rdepartment_id\x18\x05 \x01(\tR\fdepartmentId\x12\x1f\n" +
+	"\vlocation_id\x18\x06 \x01(\tR\n" +
+	"locationId*\x89\x01\n" +
+	"\x0fApplicationMode\x12 \n" +
+	"\x1cAPPLICATION_MODE_UNSPECIFIED\x10\x00\x12\x1b\n" +
+	"\x17APPLICATION_MODE_ONLINE\x10\x01\x12\x1c\n" +
+	"\x18APPLICATION_MODE_OFFLINE\x10\x02\x12\x19\n" +
+	"\x15APPLICATION_MODE_BOTH\x10\x032\x82\x02\n" +
+	"\n" +
+	"JobService\x12=\n" +
+	"\bListJobs\x12\x17.models.ListJobsRequest\x1a\x18.models.ListJobsResponse\x123\n" +
+	"\x06GetJob\x12\x15.models.GetJobRequest\x1a\x12.models.JobPosting\x12C\n" +
+	"\n" +
+	"SearchJobs\x12\x19.models.SearchJobsRequest\x1a\x1a.models.SearchJobsResponse\x12;\n" +
+	"\tWatchJobs\x12\x18.models.WatchJobsRequest\x1a\x12.models.JobPosting0\x01B/Z-github.com/entreya/job-aggregation/pkg/modelsb\x06proto3"
 
 var (
 	file_proto_job_proto_rawDescOnce sync.Once
@@ -198,18 +1070,44 @@ func file_proto_job_proto_rawDescGZIP() []byte {
 	return file_proto_job_proto_rawDescData
 }
 
-var file_proto_job_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_proto_job_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_proto_job_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
 var file_proto_job_proto_goTypes = []any{
-	(*JobPosting)(nil), // 0: models.JobPosting
-	(*JobList)(nil),    // 1: models.JobList
+	(ApplicationMode)(0),          // 0: models.ApplicationMode
+	(*JobPosting)(nil),            // 1: models.JobPosting
+	(*Attachment)(nil),            // 2: models.Attachment
+	(*JobList)(nil),               // 3: models.JobList
+	(*ListJobsRequest)(nil),       // 4: models.ListJobsRequest
+	(*ListJobsResponse)(nil),      // 5: models.ListJobsResponse
+	(*GetJobRequest)(nil),         // 6: models.GetJobRequest
+	(*SearchJobsRequest)(nil),     // 7: models.SearchJobsRequest
+	(*SearchJobsResponse)(nil),    // 8: models.SearchJobsResponse
+	(*SearchResult)(nil),          // 9: models.SearchResult
+	(*WatchJobsRequest)(nil),      // 10: models.WatchJobsRequest
+	(*timestamppb.Timestamp)(nil), // 11: google.protobuf.Timestamp
 }
 var file_proto_job_proto_depIdxs = []int32{
-	0, // 0: models.JobList.jobs:type_name -> models.JobPosting
-	1, // [1:1] is the sub-list for method output_type
-	1, // [1:1] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	11, // 0: models.JobPosting.posted_at:type_name -> google.protobuf.Timestamp
+	11, // 1: models.JobPosting.closing_at:type_name -> google.protobuf.Timestamp
+	0,  // 2: models.JobPosting.application_mode:type_name -> models.ApplicationMode
+	2,  // 3: models.JobPosting.attachments:type_name -> models.Attachment
+	1,  // 4: models.JobList.jobs:type_name -> models.JobPosting
+	1,  // 5: models.ListJobsResponse.jobs:type_name -> models.JobPosting
+	9,  // 6: models.SearchJobsResponse.results:type_name -> models.SearchResult
+	1,  // 7: models.SearchResult.job:type_name -> models.JobPosting
+	4,  // 8: models.JobService.ListJobs:input_type -> models.ListJobsRequest
+	6,  // 9: models.JobService.GetJob:input_type -> models.GetJobRequest
+	7,  // 10: models.JobService.SearchJobs:input_type -> models.SearchJobsRequest
+	10, // 11: models.JobService.WatchJobs:input_type -> models.WatchJobsRequest
+	5,  // 12: models.JobService.ListJobs:output_type -> models.ListJobsResponse
+	1,  // 13: models.JobService.GetJob:output_type -> models.JobPosting
+	8,  // 14: models.JobService.SearchJobs:output_type -> models.SearchJobsResponse
+	1,  // 15: models.JobService.WatchJobs:output_type -> models.JobPosting
+	12, // [12:16] is the sub-list for method output_type
+	8,  // [8:12] is the sub-list for method input_type
+	8,  // [8:8] is the sub-list for extension type_name
+	8,  // [8:8] is the sub-list for extension extendee
+	0,  // [0:8] is the sub-list for field type_name
 }
 
 func init() { file_proto_job_proto_init() }
@@ -222,13 +1120,14 @@ func file_proto_job_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_job_proto_rawDesc), len(file_proto_job_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   2,
+			NumEnums:      1,
+			NumMessages:   10,
 			NumExtensions: 0,
-			NumServices:   0,
+			NumServices:   1,
 		},
 		GoTypes:           file_proto_job_proto_goTypes,
 		DependencyIndexes: file_proto_job_proto_depIdxs,
+		EnumInfos:         file_proto_job_proto_enumTypes,
 		MessageInfos:      file_proto_job_proto_msgTypes,
 	}.Build()
 	File_proto_job_proto = out.File
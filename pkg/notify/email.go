@@ -0,0 +1,95 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	"time"
+)
+
+// EmailSink sends each event as a plain-text email over SMTP. The server
+// must offer STARTTLS for authentication to be used.
+type EmailSink struct {
+	Addr     string   // SMTP server, host:port
+	Username string   // Optional; PLAIN auth when set
+	Password string   // Optional
+	From     string   // Envelope and header sender
+	To       []string // Recipients
+}
+
+// Name implements Sink.
+func (m *EmailSink) Name() string { return "email" }
+
+// Send implements Sink. SMTP has no cancellation, so ctx is only checked
+// before sending.
+func (m *EmailSink) Send(ctx context.Context, e Event) error {
+	subject := "New job: " + e.Job.Title
+	switch e.Type {
+	case EventJobChanged:
+		subject = "Job updated: " + e.Job.Title
+	case EventSummary:
+		subject = "More job updates"
+	}
+	return m.send(ctx, m.To, subject, "text/plain; charset=utf-8", e.At, []byte(formatText(e)))
+}
+
+// SendMessage emails body, of the given MIME content type, to the given
+// recipients instead of m.To. Errors are classified as for Send.
+func (m *EmailSink) SendMessage(ctx context.Context, to []string, subject, contentType string, body []byte) error {
+	return m.send(ctx, to, subject, contentType, time.Now(), body)
+}
+
+func (m *EmailSink) send(ctx context.Context, to []string, subject, contentType string, date time.Time, body []byte) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if len(to) == 0 {
+		return Permanent(errors.New("email: no recipients"))
+	}
+
+	var auth smtp.Auth
+	if m.Username != "" {
+		host, _, err := net.SplitHostPort(m.Addr)
+		if err != nil {
+			return Permanent(fmt.Errorf("email: invalid SMTP address %q: %w", m.Addr, err))
+		}
+		auth = smtp.PlainAuth("", m.Username, m.Password, host)
+	}
+
+	msg := m.message(to, subject, contentType, date, body)
+	if err := smtp.SendMail(m.Addr, auth, m.From, to, msg); err != nil {
+		// 5xx replies (bad recipient, auth refused) will not succeed on retry.
+		var tpErr *textproto.Error
+		if errors.As(err, &tpErr) && tpErr.Code >= 500 {
+			return Permanent(fmt.Errorf("email: %w", err))
+		}
+		return fmt.Errorf("email: %w", err)
+	}
+	return nil
+}
+
+// message renders an RFC 5322 message with a UTF-8 body.
+func (m *EmailSink) message(to []string, subject, contentType string, date time.Time, body []byte) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", m.From)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", date.Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&b, "Content-Type: %s\r\n", contentType)
+	b.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(toCRLF(string(body)))
+	b.WriteString("\r\n")
+	return []byte(b.String())
+}
+
+// toCRLF normalises line endings to the CRLF that SMTP requires.
+func toCRLF(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "\r\n", "\n"), "\n", "\r\n")
+}
@@ -0,0 +1,49 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// FileSink appends each event as one JSON line to a local file, e.g. for a
+// workflow step that posts a summary or for auditing what was sent.
+type FileSink struct {
+	Path string
+
+	mu sync.Mutex
+}
+
+// Name implements Sink.
+func (f *FileSink) Name() string { return "file" }
+
+// Send implements Sink.
+func (f *FileSink) Send(ctx context.Context, e Event) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	line, err := json.Marshal(e)
+	if err != nil {
+		return Permanent(fmt.Errorf("failed to encode event: %w", err))
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(f.Path), 0755); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", f.Path, err)
+	}
+	file, err := os.OpenFile(f.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", f.Path, err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write %s: %w", f.Path, err)
+	}
+	return nil
+}
@@ -0,0 +1,306 @@
+// Package notify dispatches change-detection events — a new job appeared,
+// a known job changed — to pluggable sinks: a generic webhook, SMTP email,
+// a Telegram-bot-compatible HTTP API and a local file.
+//
+// Every sink runs with its own retry policy and rate limit, so a slow or
+// failing sink never delays or loses events for the others.
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/entreya/job-aggregation/pkg/db"
+)
+
+// EventType distinguishes new jobs from changed ones.
+type EventType string
+
+const (
+	EventNewJob     EventType = "new_job"
+	EventJobChanged EventType = "job_changed"
+	// EventSummary stands in for events over a sink's Options.MaxEvents.
+	EventSummary EventType = "summary"
+)
+
+// Job is the part of a job sent to sinks.
+type Job struct {
+	ID          string `json:"id"`
+	Title       string `json:"title"`
+	Department  string `json:"department"`
+	Location    string `json:"location"`
+	URL         string `json:"url"`
+	Source      string `json:"source"`
+	Vacancies   int    `json:"vacancies,omitempty"`
+	ClosingDate string `json:"closing_date,omitempty"`
+}
+
+// Change is one changed field of a job.
+type Change struct {
+	Field    string `json:"field"`
+	OldValue string `json:"old_value"`
+	NewValue string `json:"new_value"`
+}
+
+// Event is one notification.
+type Event struct {
+	Type    EventType `json:"type"`
+	Job     Job       `json:"job"`
+	Changes []Change  `json:"changes,omitempty"` // Set for EventJobChanged
+	Summary *Summary  `json:"summary,omitempty"` // Set for EventSummary
+	At      time.Time `json:"at"`
+}
+
+// Summary counts the events an EventSummary replaces.
+type Summary struct {
+	NewJobs     int `json:"new_jobs"`
+	ChangedJobs int `json:"changed_jobs"`
+}
+
+// NewJobEvent describes a job seen for the first time.
+func NewJobEvent(j db.Job, at time.Time) Event {
+	return Event{Type: EventNewJob, Job: jobFromDB(j), At: at}
+}
+
+// JobChangedEvent describes the changes to a known job.
+func JobChangedEvent(j db.Job, changes []db.Change, at time.Time) Event {
+	e := Event{Type: EventJobChanged, Job: jobFromDB(j), At: at}
+	for _, c := range changes {
+		e.Changes = append(e.Changes, Change{Field: c.Field, OldValue: c.OldValue, NewValue: c.NewValue})
+	}
+	return e
+}
+
+// SummaryEvent describes events in one event, for sinks that cannot take
+// them all.
+func SummaryEvent(events []Event, at time.Time) Event {
+	var sum Summary
+	for _, e := range events {
+		switch e.Type {
+		case EventNewJob:
+			sum.NewJobs++
+		case EventJobChanged:
+			sum.ChangedJobs++
+		case EventSummary:
+			sum.NewJobs += e.Summary.NewJobs
+			sum.ChangedJobs += e.Summary.ChangedJobs
+		}
+	}
+	return Event{Type: EventSummary, Summary: &sum, At: at}
+}
+
+func jobFromDB(j db.Job) Job {
+	return Job{
+		ID:          j.ID,
+		Title:       j.Title,
+		Department:  j.Department,
+		Location:    j.Location,
+		URL:         j.URL,
+		Source:      j.Source,
+		Vacancies:   j.Vacancies,
+		ClosingDate: j.ClosingDate,
+	}
+}
+
+// Sink delivers events to one destination.
+type Sink interface {
+	// Name identifies the sink in logs.
+	Name() string
+	// Send delivers one event. Errors wrapped with Permanent are not retried.
+	Send(ctx context.Context, e Event) error
+}
+
+// permanentError marks an error that retrying cannot fix.
+type permanentError struct{ err error }
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent marks err as not worth retrying, e.g. a rejected request.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// IsPermanent reports whether err was marked with Permanent.
+func IsPermanent(err error) bool {
+	var p *permanentError
+	return errors.As(err, &p)
+}
+
+// retryAfterError asks for the next attempt to wait at least delay, as
+// sent by a rate-limited API.
+type retryAfterError struct {
+	err   error
+	delay time.Duration
+}
+
+func (e *retryAfterError) Error() string { return e.err.Error() }
+func (e *retryAfterError) Unwrap() error { return e.err }
+
+// Options controls delivery to one sink.
+type Options struct {
+	MaxRetries  int           // Attempts after the first (0 = no retries)
+	BaseDelay   time.Duration // Backoff before the first retry, doubled each time
+	MinInterval time.Duration // Minimum time between sends, for the sink's rate limit (0 = none)
+	// MaxEvents caps the events sent per Notify: the first MaxEvents-1 go
+	// out one by one and a single EventSummary covers the rest (0 = no cap).
+	MaxEvents int
+}
+
+// DefaultOptions returns sensible defaults: three retries from 2s, no rate
+// limit.
+func DefaultOptions() Options {
+	return Options{
+		MaxRetries: 3,
+		BaseDelay:  2 * time.Second,
+	}
+}
+
+// sinkRunner is a sink with its delivery options and rate-limit state.
+type sinkRunner struct {
+	sink     Sink
+	opts     Options
+	lastSend time.Time
+}
+
+// Notifier sends events to every registered sink.
+type Notifier struct {
+	logger *slog.Logger
+	sinks  []*sinkRunner
+}
+
+// New creates a Notifier without sinks.
+func New(logger *slog.Logger) *Notifier {
+	return &Notifier{logger: logger}
+}
+
+// Add registers sink with its delivery options.
+func (n *Notifier) Add(sink Sink, opts Options) {
+	n.sinks = append(n.sinks, &sinkRunner{sink: sink, opts: opts})
+}
+
+// Len returns the number of registered sinks.
+func (n *Notifier) Len() int {
+	return len(n.sinks)
+}
+
+// Notify delivers events to every sink. Sinks run concurrently; each gets
+// the events in order, spaced by its MinInterval, capped by its MaxEvents
+// and retried per its Options. An event that still fails is logged and
+// skipped. The returned error joins one error per sink that lost events.
+func (n *Notifier) Notify(ctx context.Context, events []Event) error {
+	if len(events) == 0 || len(n.sinks) == 0 {
+		return nil
+	}
+
+	errs := make([]error, len(n.sinks))
+	var wg sync.WaitGroup
+	for i, r := range n.sinks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = n.deliver(ctx, r, events)
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// deliver sends events to one sink, capped by its MaxEvents.
+func (n *Notifier) deliver(ctx context.Context, r *sinkRunner, events []Event) error {
+	if max := r.opts.MaxEvents; max > 0 && len(events) > max {
+		n.logger.Info("too many events for sink — summarising the rest",
+			slog.String("sink", r.sink.Name()),
+			slog.Int("events", len(events)),
+			slog.Int("sent_individually", max-1),
+		)
+		rest := events[max-1:]
+		events = append(events[:max-1:max-1], SummaryEvent(rest, rest[0].At))
+	}
+
+	failed := 0
+	var lastErr error
+	for i, e := range events {
+		if err := r.wait(ctx); err != nil {
+			return fmt.Errorf("sink %s: %d of %d events not sent: %w", r.sink.Name(), failed+len(events)-i, len(events), err)
+		}
+		if err := r.send(ctx, e); err != nil {
+			failed++
+			lastErr = err
+			n.logger.Warn("notification failed",
+				slog.String("sink", r.sink.Name()),
+				slog.String("event", string(e.Type)),
+				slog.String("job_id", e.Job.ID),
+				slog.String("error", err.Error()),
+			)
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("sink %s: %d of %d events not sent: %w", r.sink.Name(), failed, len(events), lastErr)
+	}
+	n.logger.Info("notifications sent",
+		slog.String("sink", r.sink.Name()),
+		slog.Int("events", len(events)),
+	)
+	return nil
+}
+
+// wait blocks until the sink's rate limit allows the next send.
+func (r *sinkRunner) wait(ctx context.Context) error {
+	if r.opts.MinInterval <= 0 || r.lastSend.IsZero() {
+		return ctx.Err()
+	}
+	return sleep(ctx, time.Until(r.lastSend.Add(r.opts.MinInterval)))
+}
+
+// send delivers e with exponential backoff. Permanent errors and
+// cancellation end it early; a retry-after hint from the sink lengthens
+// the wait.
+func (r *sinkRunner) send(ctx context.Context, e Event) error {
+	maxRetries := r.opts.MaxRetries
+	if maxRetries < 0 {
+		maxRetries = 0
+	}
+	delay := r.opts.BaseDelay
+
+	var err error
+	for attempt := 0; ; attempt++ {
+		err = r.sink.Send(ctx, e)
+		r.lastSend = time.Now()
+		if err == nil || IsPermanent(err) || ctx.Err() != nil || attempt >= maxRetries {
+			return err
+		}
+
+		wait := delay
+		var ra *retryAfterError
+		if errors.As(err, &ra) && ra.delay > wait {
+			wait = ra.delay
+		}
+		if err := sleep(ctx, wait); err != nil {
+			return err
+		}
+		delay *= 2
+	}
+}
+
+// sleep waits for d or until ctx is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,173 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/entreya/job-aggregation/pkg/db"
+)
+
+func testNotifyLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelWarn}))
+}
+
+// fakeSink fails the first failures sends with err, then records events.
+type fakeSink struct {
+	name     string
+	failures int
+	err      error
+
+	mu    sync.Mutex
+	calls int
+	sent  []Event
+	times []time.Time
+}
+
+func (f *fakeSink) Name() string { return f.name }
+
+func (f *fakeSink) Send(ctx context.Context, e Event) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls++
+	f.times = append(f.times, time.Now())
+	if f.calls <= f.failures {
+		return f.err
+	}
+	f.sent = append(f.sent, e)
+	return nil
+}
+
+func testEvents(n int) []Event {
+	events := make([]Event, n)
+	for i := range events {
+		events[i] = NewJobEvent(db.Job{ID: string(rune('a' + i)), Title: "Clerk"}, time.Now())
+	}
+	return events
+}
+
+func fastOptions() Options {
+	return Options{MaxRetries: 3, BaseDelay: time.Millisecond}
+}
+
+func TestNotify_RetriesTransientErrors(t *testing.T) {
+	sink := &fakeSink{name: "flaky", failures: 2, err: errors.New("connection reset")}
+	n := New(testNotifyLogger())
+	n.Add(sink, fastOptions())
+
+	if err := n.Notify(context.Background(), testEvents(1)); err != nil {
+		t.Fatal(err)
+	}
+	if sink.calls != 3 || len(sink.sent) != 1 {
+		t.Errorf("expected 3 attempts and 1 delivery, got %d and %d", sink.calls, len(sink.sent))
+	}
+}
+
+func TestNotify_DoesNotRetryPermanentErrors(t *testing.T) {
+	sink := &fakeSink{name: "rejecting", failures: 10, err: Permanent(errors.New("400 bad request"))}
+	n := New(testNotifyLogger())
+	n.Add(sink, fastOptions())
+
+	err := n.Notify(context.Background(), testEvents(2))
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if sink.calls != 2 {
+		t.Errorf("expected one attempt per event, got %d", sink.calls)
+	}
+}
+
+func TestNotify_FailingSinkDoesNotAffectOthers(t *testing.T) {
+	broken := &fakeSink{name: "broken", failures: 100, err: errors.New("timeout")}
+	healthy := &fakeSink{name: "healthy"}
+	n := New(testNotifyLogger())
+	n.Add(broken, Options{MaxRetries: 1, BaseDelay: time.Millisecond})
+	n.Add(healthy, fastOptions())
+
+	err := n.Notify(context.Background(), testEvents(3))
+	if err == nil {
+		t.Fatal("expected the broken sink to be reported")
+	}
+	if len(healthy.sent) != 3 {
+		t.Errorf("healthy sink got %d of 3 events", len(healthy.sent))
+	}
+	if broken.calls != 6 {
+		t.Errorf("expected 2 attempts for each of 3 events, got %d", broken.calls)
+	}
+}
+
+func TestNotify_RespectsMinInterval(t *testing.T) {
+	sink := &fakeSink{name: "limited"}
+	n := New(testNotifyLogger())
+	n.Add(sink, Options{MinInterval: 30 * time.Millisecond})
+
+	if err := n.Notify(context.Background(), testEvents(3)); err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i < len(sink.times); i++ {
+		if gap := sink.times[i].Sub(sink.times[i-1]); gap < 25*time.Millisecond {
+			t.Errorf("send %d came %v after the previous one", i, gap)
+		}
+	}
+}
+
+func TestNotify_StopsOnCancel(t *testing.T) {
+	sink := &fakeSink{name: "slow"}
+	n := New(testNotifyLogger())
+	n.Add(sink, Options{MinInterval: time.Hour})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	if err := n.Notify(ctx, testEvents(3)); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline error, got %v", err)
+	}
+	if len(sink.sent) != 1 {
+		t.Errorf("expected only the first event before the rate limit, got %d", len(sink.sent))
+	}
+}
+
+func TestNotify_SummarisesEventsOverMaxEvents(t *testing.T) {
+	capped := &fakeSink{name: "capped"}
+	uncapped := &fakeSink{name: "uncapped"}
+	n := New(testNotifyLogger())
+	n.Add(capped, Options{MaxEvents: 3})
+	n.Add(uncapped, Options{})
+
+	events := testEvents(5)
+	events[4] = JobChangedEvent(db.Job{ID: "e", Title: "Clerk"}, nil, time.Now())
+	if err := n.Notify(context.Background(), events); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(uncapped.sent) != 5 {
+		t.Errorf("uncapped sink got %d of 5 events", len(uncapped.sent))
+	}
+	if len(capped.sent) != 3 {
+		t.Fatalf("expected 2 events and a summary, got %+v", capped.sent)
+	}
+	if capped.sent[1].Job.ID != "b" {
+		t.Errorf("expected events before the cap in order, got %+v", capped.sent[1])
+	}
+	sum := capped.sent[2]
+	if sum.Type != EventSummary || sum.Summary.NewJobs != 2 || sum.Summary.ChangedJobs != 1 {
+		t.Errorf("expected a summary of 2 new and 1 changed job, got %+v", sum)
+	}
+	if events[2].Type != EventNewJob {
+		t.Error("summarising must not modify the caller's events")
+	}
+}
+
+func TestJobChangedEvent(t *testing.T) {
+	e := JobChangedEvent(db.Job{ID: "a", Title: "Clerk"}, []db.Change{
+		{JobID: "a", Field: "closing_date", OldValue: "2026-03-01", NewValue: "2026-03-15"},
+	}, time.Now())
+
+	if e.Type != EventJobChanged || len(e.Changes) != 1 || e.Changes[0].NewValue != "2026-03-15" {
+		t.Errorf("unexpected event %+v", e)
+	}
+}
@@ -0,0 +1,293 @@
+package notify
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/entreya/job-aggregation/pkg/db"
+)
+
+func testEvent() Event {
+	return NewJobEvent(db.Job{
+		ID: "je-1", Title: "Junior Engineer (Civil)", Department: "SSC", Location: "Delhi",
+		URL: "https://ssc.gov.in/je.pdf", Source: "ssc", Vacancies: 12, ClosingDate: "2026-03-15",
+	}, time.Unix(1772300000, 0))
+}
+
+func TestWebhookSink_PostsEventJSON(t *testing.T) {
+	var got Event
+	var auth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Error(err)
+		}
+	}))
+	defer srv.Close()
+
+	sink := &WebhookSink{URL: srv.URL, Headers: map[string]string{"Authorization": "Bearer secret"}}
+	if err := sink.Send(context.Background(), testEvent()); err != nil {
+		t.Fatal(err)
+	}
+	if got.Type != EventNewJob || got.Job.ID != "je-1" || got.Job.Vacancies != 12 {
+		t.Errorf("unexpected payload %+v", got)
+	}
+	if auth != "Bearer secret" {
+		t.Errorf("expected custom header, got %q", auth)
+	}
+}
+
+func TestWebhookSink_ClassifiesStatus(t *testing.T) {
+	tests := []struct {
+		status    int
+		permanent bool
+	}{
+		{http.StatusInternalServerError, false},
+		{http.StatusTooManyRequests, false},
+		{http.StatusBadRequest, true},
+		{http.StatusNotFound, true},
+	}
+	for _, tt := range tests {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(tt.status)
+		}))
+		err := (&WebhookSink{URL: srv.URL}).Send(context.Background(), testEvent())
+		srv.Close()
+
+		if err == nil {
+			t.Errorf("status %d: expected an error", tt.status)
+			continue
+		}
+		if IsPermanent(err) != tt.permanent {
+			t.Errorf("status %d: permanent = %v, want %v", tt.status, IsPermanent(err), tt.permanent)
+		}
+	}
+}
+
+func TestWebhookSink_RetriedThroughNotifier(t *testing.T) {
+	var mu sync.Mutex
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+
+	n := New(testNotifyLogger())
+	n.Add(&WebhookSink{URL: srv.URL}, Options{MaxRetries: 2, BaseDelay: time.Millisecond})
+	if err := n.Notify(context.Background(), []Event{testEvent()}); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 2 {
+		t.Errorf("expected one retry, got %d calls", calls)
+	}
+}
+
+func TestTelegramSink_SendsMessage(t *testing.T) {
+	var path string
+	var msg struct {
+		ChatID string `json:"chat_id"`
+		Text   string `json:"text"`
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		json.NewDecoder(r.Body).Decode(&msg)
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer srv.Close()
+
+	sink := &TelegramSink{APIBase: srv.URL, Token: "123:ABC", ChatID: "@jobs"}
+	if err := sink.Send(context.Background(), testEvent()); err != nil {
+		t.Fatal(err)
+	}
+	if path != "/bot123:ABC/sendMessage" {
+		t.Errorf("unexpected path %q", path)
+	}
+	if msg.ChatID != "@jobs" {
+		t.Errorf("unexpected chat %q", msg.ChatID)
+	}
+	for _, want := range []string{"New job: Junior Engineer (Civil)", "SSC — Delhi", "Vacancies: 12", "Apply by: 2026-03-15", "https://ssc.gov.in/je.pdf"} {
+		if !strings.Contains(msg.Text, want) {
+			t.Errorf("message missing %q:\n%s", want, msg.Text)
+		}
+	}
+}
+
+func TestTelegramSink_RateLimitedAndRedacted(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte(`{"ok":false,"error_code":429,"parameters":{"retry_after":7}}`))
+	}))
+	defer srv.Close()
+
+	err := (&TelegramSink{APIBase: srv.URL, Token: "123:ABC", ChatID: "1"}).Send(context.Background(), testEvent())
+	if err == nil || IsPermanent(err) {
+		t.Fatalf("expected a retryable error, got %v", err)
+	}
+
+	// A transport error quotes the URL, which carries the token.
+	srv.Close()
+	err = (&TelegramSink{APIBase: srv.URL, Token: "123:ABC", ChatID: "1"}).Send(context.Background(), testEvent())
+	if err == nil || strings.Contains(err.Error(), "123:ABC") {
+		t.Errorf("expected an error without the token, got %v", err)
+	}
+}
+
+func TestTelegramRetryAfter(t *testing.T) {
+	if got := telegramRetryAfter([]byte(`{"ok":false,"parameters":{"retry_after":7}}`)); got != 7*time.Second {
+		t.Errorf("expected 7s, got %v", got)
+	}
+	if got := telegramRetryAfter([]byte(`not json`)); got != 0 {
+		t.Errorf("expected 0 for an unparseable body, got %v", got)
+	}
+}
+
+func TestFormatText_Changes(t *testing.T) {
+	e := JobChangedEvent(db.Job{ID: "a", Title: "Clerk", URL: "https://x/a.pdf"}, []db.Change{
+		{Field: "closing_date", OldValue: "2026-03-01", NewValue: "2026-03-15"},
+		{Field: "advert_text", OldValue: "", NewValue: "(changed)"},
+	}, time.Now())
+
+	text := formatText(e)
+	for _, want := range []string{"Job updated: Clerk", "closing date: 2026-03-01 → 2026-03-15", "advertisement text changed"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("text missing %q:\n%s", want, text)
+		}
+	}
+}
+
+// fakeSMTP accepts one plain SMTP session, answering RCPT with rcptReply,
+// and passes on the message data.
+func fakeSMTP(t *testing.T, rcptReply string) (addr string, data <-chan string) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	out := make(chan string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		reply := func(s string) { conn.Write([]byte(s + "\r\n")) }
+
+		reply("220 localhost ESMTP")
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			cmd := strings.ToUpper(strings.TrimSpace(line))
+			switch {
+			case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+				reply("250 localhost")
+			case strings.HasPrefix(cmd, "RCPT"):
+				reply(rcptReply)
+			case cmd == "DATA":
+				reply("354 go ahead")
+				var b strings.Builder
+				for {
+					l, err := r.ReadString('\n')
+					if err != nil || l == ".\r\n" {
+						break
+					}
+					b.WriteString(l)
+				}
+				out <- b.String()
+				reply("250 queued")
+			case cmd == "QUIT":
+				reply("221 bye")
+				return
+			default:
+				reply("250 ok")
+			}
+		}
+	}()
+	return ln.Addr().String(), out
+}
+
+func TestEmailSink_SendsMessage(t *testing.T) {
+	addr, data := fakeSMTP(t, "250 ok")
+
+	sink := &EmailSink{Addr: addr, From: "alerts@example.org", To: []string{"team@example.org"}}
+	if err := sink.Send(context.Background(), testEvent()); err != nil {
+		t.Fatal(err)
+	}
+
+	msg := <-data
+	for _, want := range []string{"Subject: New job: Junior Engineer (Civil)", "To: team@example.org", "Apply by: 2026-03-15"} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("message missing %q:\n%s", want, msg)
+		}
+	}
+}
+
+func TestEmailSink_RejectedRecipientIsPermanent(t *testing.T) {
+	addr, _ := fakeSMTP(t, "550 no such user")
+
+	sink := &EmailSink{Addr: addr, From: "alerts@example.org", To: []string{"nobody@example.org"}}
+	if err := sink.Send(context.Background(), testEvent()); !IsPermanent(err) {
+		t.Errorf("expected a permanent error, got %v", err)
+	}
+}
+
+func TestFileSink_AppendsJSONLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out", "events.jsonl")
+	sink := &FileSink{Path: path}
+
+	for i := 0; i < 2; i++ {
+		if err := sink.Send(context.Background(), testEvent()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %d", len(lines))
+	}
+	var e Event
+	if err := json.Unmarshal([]byte(lines[1]), &e); err != nil || e.Job.ID != "je-1" {
+		t.Errorf("unexpected line %q: %v", lines[1], err)
+	}
+}
+
+func TestEmailSink_SendMessage(t *testing.T) {
+	addr, data := fakeSMTP(t, "250 ok")
+
+	sink := &EmailSink{Addr: addr, From: "digest@example.org"}
+	err := sink.SendMessage(context.Background(), []string{"a@example.org"}, "2 new jobs",
+		"text/html; charset=utf-8", []byte("<h1>2 new jobs</h1>\n<ul></ul>\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msg := <-data
+	for _, want := range []string{"To: a@example.org\r\n", "Content-Type: text/html; charset=utf-8\r\n", "<h1>2 new jobs</h1>\r\n<ul></ul>\r\n"} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("message missing %q:\n%s", want, msg)
+		}
+	}
+}
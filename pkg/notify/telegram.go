@@ -0,0 +1,131 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// DefaultTelegramAPI is the Telegram Bot API root.
+const DefaultTelegramAPI = "https://api.telegram.org"
+
+// TelegramSink posts each event as a message through the Telegram Bot API
+// sendMessage method. Any server implementing that method works.
+//
+// Telegram allows about one message per second per chat; register the sink
+// with a MinInterval of at least that.
+type TelegramSink struct {
+	APIBase string // Defaults to DefaultTelegramAPI
+	Token   string // Bot token
+	ChatID  string // Chat, group or "@channel" to post to
+	Client  *http.Client
+}
+
+// Name implements Sink.
+func (t *TelegramSink) Name() string { return "telegram" }
+
+// Send implements Sink.
+func (t *TelegramSink) Send(ctx context.Context, e Event) error {
+	base := t.APIBase
+	if base == "" {
+		base = DefaultTelegramAPI
+	}
+	url := strings.TrimRight(base, "/") + "/bot" + t.Token + "/sendMessage"
+
+	msg := map[string]any{
+		"chat_id":                  t.ChatID,
+		"text":                     formatText(e),
+		"disable_web_page_preview": true,
+	}
+	if err := postJSON(ctx, t.Client, url, nil, msg); err != nil {
+		return fmt.Errorf("telegram sendMessage: %w", &redactedError{err: err, secret: t.Token})
+	}
+	return nil
+}
+
+// redactedError hides a secret, such as a bot token in a request URL, from
+// an error's message while keeping it unwrappable for retry decisions.
+type redactedError struct {
+	err    error
+	secret string
+}
+
+func (e *redactedError) Error() string {
+	if e.secret == "" {
+		return e.err.Error()
+	}
+	return strings.ReplaceAll(e.err.Error(), e.secret, "<redacted>")
+}
+
+func (e *redactedError) Unwrap() error { return e.err }
+
+// telegramRetryAfter reads parameters.retry_after from a Bot API error
+// body, which Telegram sends instead of a Retry-After header.
+func telegramRetryAfter(body []byte) time.Duration {
+	var resp struct {
+		Parameters struct {
+			RetryAfter int `json:"retry_after"`
+		} `json:"parameters"`
+	}
+	if json.Unmarshal(body, &resp) != nil || resp.Parameters.RetryAfter <= 0 {
+		return 0
+	}
+	return time.Duration(resp.Parameters.RetryAfter) * time.Second
+}
+
+// formatText renders e as a short plain-text message.
+func formatText(e Event) string {
+	if e.Type == EventSummary {
+		return fmt.Sprintf("%d more new jobs and %d more updated jobs this run, not listed individually.",
+			e.Summary.NewJobs, e.Summary.ChangedJobs)
+	}
+
+	var b strings.Builder
+	switch e.Type {
+	case EventJobChanged:
+		fmt.Fprintf(&b, "Job updated: %s\n", e.Job.Title)
+	default:
+		fmt.Fprintf(&b, "New job: %s\n", e.Job.Title)
+	}
+
+	if where := joinNonEmpty(" — ", e.Job.Department, e.Job.Location); where != "" {
+		b.WriteString(where + "\n")
+	}
+	if e.Type == EventNewJob {
+		if e.Job.Vacancies > 0 {
+			fmt.Fprintf(&b, "Vacancies: %d\n", e.Job.Vacancies)
+		}
+		if e.Job.ClosingDate != "" {
+			fmt.Fprintf(&b, "Apply by: %s\n", e.Job.ClosingDate)
+		}
+	}
+	for _, c := range e.Changes {
+		if c.Field == "advert_text" {
+			b.WriteString("• advertisement text changed\n")
+			continue
+		}
+		fmt.Fprintf(&b, "• %s: %s → %s\n", strings.ReplaceAll(c.Field, "_", " "), orNone(c.OldValue), orNone(c.NewValue))
+	}
+	b.WriteString(e.Job.URL)
+	return b.String()
+}
+
+func joinNonEmpty(sep string, parts ...string) string {
+	kept := parts[:0:0]
+	for _, p := range parts {
+		if p != "" {
+			kept = append(kept, p)
+		}
+	}
+	return strings.Join(kept, sep)
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "(none)"
+	}
+	return s
+}
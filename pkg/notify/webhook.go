@@ -0,0 +1,88 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// httpTimeout bounds each HTTP request made by a sink.
+const httpTimeout = 15 * time.Second
+
+// WebhookSink POSTs each event as JSON to a URL.
+type WebhookSink struct {
+	URL     string
+	Headers map[string]string // Extra request headers, e.g. Authorization
+	Client  *http.Client      // Optional; defaults to a client with a 15s timeout
+}
+
+// Name implements Sink.
+func (w *WebhookSink) Name() string { return "webhook" }
+
+// Send implements Sink.
+func (w *WebhookSink) Send(ctx context.Context, e Event) error {
+	return postJSON(ctx, w.Client, w.URL, w.Headers, e)
+}
+
+// postJSON sends body as JSON and classifies the response: 2xx succeeds,
+// 429 and 5xx are retried (honouring Retry-After), any other status is
+// permanent.
+func postJSON(ctx context.Context, client *http.Client, url string, headers map[string]string, body any) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return Permanent(fmt.Errorf("failed to encode request: %w", err))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return Permanent(fmt.Errorf("failed to build request: %w", err))
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	if client == nil {
+		client = &http.Client{Timeout: httpTimeout}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+
+	snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	err = fmt.Errorf("unexpected status %d: %s", resp.StatusCode, bytes.TrimSpace(snippet))
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		delay := parseRetryAfter(resp.Header.Get("Retry-After"))
+		if delay == 0 {
+			delay = telegramRetryAfter(snippet)
+		}
+		return &retryAfterError{err: err, delay: delay}
+	case resp.StatusCode >= 500:
+		return err
+	default:
+		return Permanent(err)
+	}
+}
+
+// parseRetryAfter reads a Retry-After header given in seconds. HTTP dates
+// and invalid values yield 0, leaving the normal backoff in charge.
+func parseRetryAfter(value string) time.Duration {
+	seconds, err := strconv.Atoi(value)
+	if err != nil || seconds < 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
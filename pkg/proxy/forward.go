@@ -0,0 +1,189 @@
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	xproxy "golang.org/x/net/proxy"
+)
+
+// SplitCredentials separates proxyURL into the server address, without
+// credentials, and the username and password it carried (empty if none).
+// Chrome's --proxy-server ignores credentials, so they must be answered to
+// the proxy's authentication challenge instead.
+func SplitCredentials(proxyURL string) (server, username, password string, err error) {
+	parsed, err := url.Parse(proxyURL)
+	if err != nil {
+		return "", "", "", fmt.Errorf("invalid proxy URL: %w", err)
+	}
+	if parsed.User != nil {
+		username = parsed.User.Username()
+		password, _ = parsed.User.Password()
+	}
+	server = (&url.URL{Scheme: parsed.Scheme, Host: parsed.Host}).String()
+	return server, username, password, nil
+}
+
+// IsSOCKS reports whether proxyURL is a SOCKS5 proxy (socks5 or socks5h).
+func IsSOCKS(proxyURL string) bool {
+	return strings.HasPrefix(proxyURL, "socks5://") || strings.HasPrefix(proxyURL, "socks5h://")
+}
+
+// Forwarder is a local HTTP proxy without authentication that relays every
+// request through an upstream SOCKS5 proxy, credentials included. Chrome
+// cannot authenticate to SOCKS5 proxies, so it is pointed at a Forwarder.
+type Forwarder struct {
+	ln        net.Listener
+	srv       *http.Server
+	dialer    xproxy.ContextDialer
+	transport *http.Transport
+
+	mu    sync.Mutex
+	conns map[net.Conn]struct{} // Hijacked tunnels, closed by Close
+}
+
+// NewForwarder starts a Forwarder on a free loopback port relaying through
+// upstreamURL, a socks5:// or socks5h:// URL with optional user:password.
+// Close it when done.
+func NewForwarder(upstreamURL string) (*Forwarder, error) {
+	parsed, err := url.Parse(upstreamURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy URL: %w", err)
+	}
+	if !IsSOCKS(upstreamURL) {
+		return nil, fmt.Errorf("unsupported upstream scheme %q (expected socks5 or socks5h)", parsed.Scheme)
+	}
+	d, err := xproxy.FromURL(parsed, &net.Dialer{Timeout: 30 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to build SOCKS5 dialer: %w", err)
+	}
+	dialer, ok := d.(xproxy.ContextDialer)
+	if !ok {
+		return nil, fmt.Errorf("SOCKS5 dialer does not support contexts")
+	}
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen: %w", err)
+	}
+	f := &Forwarder{
+		ln:     ln,
+		dialer: dialer,
+		transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			IdleConnTimeout:     30 * time.Second,
+			TLSHandshakeTimeout: 15 * time.Second,
+		},
+		conns: make(map[net.Conn]struct{}),
+	}
+	f.srv = &http.Server{Handler: f, ReadHeaderTimeout: 30 * time.Second}
+	go func() { _ = f.srv.Serve(ln) }()
+	return f, nil
+}
+
+// URL returns the address to give Chrome, e.g. "http://127.0.0.1:40123".
+func (f *Forwarder) URL() string {
+	return "http://" + f.ln.Addr().String()
+}
+
+// Close stops the Forwarder and tears down open tunnels.
+func (f *Forwarder) Close() error {
+	err := f.srv.Close()
+	f.mu.Lock()
+	for c := range f.conns {
+		c.Close()
+	}
+	f.mu.Unlock()
+	f.transport.CloseIdleConnections()
+	return err
+}
+
+// ServeHTTP opens a tunnel for CONNECT (HTTPS) and relays plain HTTP
+// requests with absolute URLs. Upstream failures answer 502, which Chrome
+// reports as a proxy failure.
+func (f *Forwarder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodConnect {
+		f.tunnel(w, r)
+		return
+	}
+	if !r.URL.IsAbs() {
+		http.Error(w, "forwarder only accepts proxy requests", http.StatusBadRequest)
+		return
+	}
+
+	out := r.Clone(r.Context())
+	out.RequestURI = ""
+	out.Header.Del("Proxy-Connection")
+	out.Header.Del("Proxy-Authorization")
+	resp, err := f.transport.RoundTrip(out)
+	if err != nil {
+		http.Error(w, "upstream proxy: "+err.Error(), http.StatusBadGateway)
+		return
+	}
+	defer resp.Body.Close()
+	for k, v := range resp.Header {
+		w.Header()[k] = v
+	}
+	w.WriteHeader(resp.StatusCode)
+	_, _ = io.Copy(w, resp.Body)
+}
+
+func (f *Forwarder) tunnel(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	upstream, err := f.dialer.DialContext(ctx, "tcp", r.Host)
+	cancel()
+	if err != nil {
+		http.Error(w, "upstream proxy: "+err.Error(), http.StatusBadGateway)
+		return
+	}
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		upstream.Close()
+		http.Error(w, "tunnelling not supported", http.StatusInternalServerError)
+		return
+	}
+	client, buf, err := hijacker.Hijack()
+	if err != nil {
+		upstream.Close()
+		return
+	}
+	f.track(client, true)
+	f.track(upstream, true)
+	defer func() {
+		f.track(client, false)
+		f.track(upstream, false)
+		client.Close()
+		upstream.Close()
+	}()
+
+	if _, err := client.Write([]byte("HTTP/1.1 200 Connection Established\r\n\r\n")); err != nil {
+		return
+	}
+	done := make(chan struct{}, 2)
+	go func() {
+		_, _ = io.Copy(upstream, buf.Reader)
+		done <- struct{}{}
+	}()
+	go func() {
+		_, _ = io.Copy(client, upstream)
+		done <- struct{}{}
+	}()
+	<-done
+}
+
+func (f *Forwarder) track(c net.Conn, open bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if open {
+		f.conns[c] = struct{}{}
+	} else {
+		delete(f.conns, c)
+	}
+}
@@ -0,0 +1,133 @@
+package proxy
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/netip"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// regionPattern matches an ISO 3166-1 country code ("IN") or an ISO 3166-2
+// subdivision code ("IN-MH").
+var regionPattern = regexp.MustCompile(`^[A-Z]{2}(-[A-Z0-9]{1,3})?$`)
+
+// ParseRegion normalises an egress region: a country code such as "IN" or a
+// region code such as "IN-MH", case-insensitively. "" stays "" (anywhere).
+func ParseRegion(s string) (string, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	if s != "" && !regionPattern.MatchString(s) {
+		return "", fmt.Errorf("invalid region %q: want a country code such as IN or a region code such as IN-MH", s)
+	}
+	return s, nil
+}
+
+// inRegion reports whether a proxy exiting in country and region ("" when
+// unknown) may serve a request that must leave from want (see ParseRegion).
+// Unknown parts of the location do not exclude a proxy.
+func inRegion(country, region, want string) bool {
+	if want == "" {
+		return true
+	}
+	wantCountry, _, wantsRegion := strings.Cut(want, "-")
+	if country != "" && country != wantCountry {
+		return false
+	}
+	return !wantsRegion || region == "" || region == want
+}
+
+// GeoDB is a local GeoIP database mapping IP ranges to countries and
+// regions. Safe for concurrent use.
+type GeoDB struct {
+	ranges []geoRange // Sorted by start, not overlapping
+}
+
+type geoRange struct {
+	start, end netip.Addr
+	country    string
+	region     string
+}
+
+// ReadGeoDB reads a GeoIP database in CSV form, one range per line:
+//
+//	start_ip,end_ip,country[,region]
+//	1.22.0.0,1.23.255.255,IN,IN-MH
+//
+// This is the layout of DB-IP's free IP-to-Country Lite CSV, with an
+// optional ISO 3166-2 region column. IPv4 and IPv6 ranges may be mixed;
+// blank lines and lines starting with "#" are ignored.
+func ReadGeoDB(r io.Reader) (*GeoDB, error) {
+	cr := csv.NewReader(bufio.NewReader(r))
+	cr.Comment = '#'
+	cr.FieldsPerRecord = -1
+	cr.ReuseRecord = true
+
+	db := &GeoDB{}
+	for {
+		rec, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("geoip: %w", err)
+		}
+		line, _ := cr.FieldPos(0)
+		if len(rec) < 3 {
+			return nil, fmt.Errorf("geoip: line %d: want start_ip,end_ip,country[,region]", line)
+		}
+		start, err1 := netip.ParseAddr(strings.TrimSpace(rec[0]))
+		end, err2 := netip.ParseAddr(strings.TrimSpace(rec[1]))
+		if err1 != nil || err2 != nil || start.Is4() != end.Is4() || end.Less(start) {
+			return nil, fmt.Errorf("geoip: line %d: invalid range %s-%s", line, rec[0], rec[1])
+		}
+		g := geoRange{start: start, end: end, country: strings.ToUpper(strings.TrimSpace(rec[2]))}
+		if len(rec) > 3 {
+			g.region = strings.ToUpper(strings.TrimSpace(rec[3]))
+		}
+		db.ranges = append(db.ranges, g)
+	}
+
+	sort.Slice(db.ranges, func(i, j int) bool { return db.ranges[i].start.Less(db.ranges[j].start) })
+	for i := 1; i < len(db.ranges); i++ {
+		if !db.ranges[i-1].end.Less(db.ranges[i].start) {
+			return nil, fmt.Errorf("geoip: ranges starting %s and %s overlap", db.ranges[i-1].start, db.ranges[i].start)
+		}
+	}
+	return db, nil
+}
+
+// OpenGeoDB reads the GeoIP database file at path (see ReadGeoDB).
+func OpenGeoDB(path string) (*GeoDB, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	db, err := ReadGeoDB(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return db, nil
+}
+
+// Lookup returns the country and region ("" when the database has none) of
+// ip, and whether ip is in the database.
+func (db *GeoDB) Lookup(ip netip.Addr) (country, region string, ok bool) {
+	ip = ip.Unmap()
+	// The first range ending at or after ip is the only one that can hold it.
+	i := sort.Search(len(db.ranges), func(i int) bool { return !db.ranges[i].end.Less(ip) })
+	if i == len(db.ranges) || ip.Less(db.ranges[i].start) || db.ranges[i].start.Is4() != ip.Is4() {
+		return "", "", false
+	}
+	return db.ranges[i].country, db.ranges[i].region, true
+}
+
+// Len returns the number of ranges in the database.
+func (db *GeoDB) Len() int {
+	return len(db.ranges)
+}
@@ -0,0 +1,115 @@
+package proxy
+
+import (
+	"net/netip"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseList_Region(t *testing.T) {
+	got, err := ParseList("http://p1.example.com:8080 region=in-mh, http://p2.example.com:8080 country=IN region=IN-KA")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Proxy{
+		{URL: "http://p1.example.com:8080", Weight: 1, Country: "IN", Region: "IN-MH"},
+		{URL: "http://p2.example.com:8080", Weight: 1, Country: "IN", Region: "IN-KA"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+
+	for _, list := range []string{
+		"http://p1.example.com:8080 region=IN",               // A country, not a region
+		"http://p1.example.com:8080 region=Maharashtra",      // Not a code
+		"http://p1.example.com:8080 country=US region=IN-MH", // Contradicts the country
+	} {
+		if _, err := ParseList(list); err == nil {
+			t.Errorf("%q: expected an error", list)
+		}
+	}
+}
+
+func TestParseRegion(t *testing.T) {
+	for in, want := range map[string]string{"": "", "in": "IN", " in-mh ": "IN-MH", "US-CA": "US-CA"} {
+		if got, err := ParseRegion(in); err != nil || got != want {
+			t.Errorf("ParseRegion(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"India", "I", "IN-", "IN_MH", "IN-MAHA"} {
+		if _, err := ParseRegion(in); err == nil {
+			t.Errorf("ParseRegion(%q): expected an error", in)
+		}
+	}
+}
+
+func TestInRegion(t *testing.T) {
+	tests := []struct {
+		country, region, want string
+		ok                    bool
+	}{
+		{"US", "", "", true},
+		{"IN", "IN-MH", "IN", true},
+		{"US", "", "IN", false},
+		{"IN", "IN-MH", "IN-MH", true},
+		{"IN", "IN-KA", "IN-MH", false},
+		{"IN", "", "IN-MH", true}, // Region unknown
+		{"", "", "IN-MH", true},   // Location unknown
+	}
+	for _, tt := range tests {
+		if got := inRegion(tt.country, tt.region, tt.want); got != tt.ok {
+			t.Errorf("inRegion(%q, %q, %q) = %v, want %v", tt.country, tt.region, tt.want, got, tt.ok)
+		}
+	}
+}
+
+const testGeoDB = `# start,end,country,region
+1.22.0.0,1.23.255.255,IN,IN-MH
+49.32.0.0,49.47.255.255,IN
+8.8.8.0,8.8.8.255,US,US-CA
+2401:4900::,2401:4900:ffff:ffff:ffff:ffff:ffff:ffff,IN,IN-DL
+`
+
+func TestGeoDB_Lookup(t *testing.T) {
+	db, err := ReadGeoDB(strings.NewReader(testGeoDB))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if db.Len() != 4 {
+		t.Errorf("Len() = %d, want 4", db.Len())
+	}
+
+	tests := []struct {
+		ip, country, region string
+		ok                  bool
+	}{
+		{"1.22.0.0", "IN", "IN-MH", true},
+		{"1.23.255.255", "IN", "IN-MH", true},
+		{"49.40.1.2", "IN", "", true},
+		{"::ffff:8.8.8.8", "US", "US-CA", true},
+		{"2401:4900:1c00::1", "IN", "IN-DL", true},
+		{"1.24.0.0", "", "", false},
+		{"9.9.9.9", "", "", false},
+		{"2001:db8::1", "", "", false},
+	}
+	for _, tt := range tests {
+		country, region, ok := db.Lookup(netip.MustParseAddr(tt.ip))
+		if country != tt.country || region != tt.region || ok != tt.ok {
+			t.Errorf("Lookup(%s) = %q, %q, %v; want %q, %q, %v", tt.ip, country, region, ok, tt.country, tt.region, tt.ok)
+		}
+	}
+}
+
+func TestReadGeoDB_Invalid(t *testing.T) {
+	for _, data := range []string{
+		"1.0.0.0,1.0.0.255\n",                          // Missing country
+		"1.0.0.9,1.0.0.0,IN\n",                         // End before start
+		"1.0.0.0,::1,IN\n",                             // Mixed families
+		"1.0.0.0,1.0.0.255,IN\n1.0.0.128,1.0.1.0,IN\n", // Overlap
+	} {
+		if _, err := ReadGeoDB(strings.NewReader(data)); err == nil {
+			t.Errorf("%q: expected an error", data)
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package proxy
+
+import (
+	"log/slog"
+	"time"
+)
+
+// HealthConfig controls how the rotator scores proxies and ejects failing ones.
+type HealthConfig struct {
+	FailureThreshold int           // Consecutive failures before a proxy is ejected (default 3)
+	Cooldown         time.Duration // How long an ejected proxy is skipped (default 5m)
+}
+
+const (
+	defaultFailureThreshold = 3
+	defaultCooldown         = 5 * time.Minute
+
+	// healthDecay is the weight of the newest result in the moving success
+	// rate and latency: about the last ten requests count.
+	healthDecay = 0.2
+
+	// latencyScale is the latency at which a proxy's score is halved.
+	latencyScale = 2 * time.Second
+)
+
+// withDefaults fills unset fields of c.
+func (c HealthConfig) withDefaults() HealthConfig {
+	if c.FailureThreshold <= 0 {
+		c.FailureThreshold = defaultFailureThreshold
+	}
+	if c.Cooldown <= 0 {
+		c.Cooldown = defaultCooldown
+	}
+	return c
+}
+
+// health is the record of one proxy. Guarded by ProxyRotator.mu.
+type health struct {
+	successes    int
+	failures     int
+	consecutive  int           // Failures since the last success
+	successRate  float64       // Moving average, 1 for a new proxy
+	latency      time.Duration // Moving average of successful requests; 0 until measured
+	ejectedUntil time.Time
+	lastFailure  time.Time
+	lastUsed     time.Time // When a strategy last picked the proxy
+	lastError    string
+	egress       egress // Where the proxy exits, once verified
+}
+
+func newHealth() *health {
+	return &health{successRate: 1}
+}
+
+// ejected reports whether the proxy is sitting out a cooldown at now.
+func (h *health) ejected(now time.Time) bool {
+	return now.Before(h.ejectedUntil)
+}
+
+// score rates the proxy from 0 to 1: its recent success rate, scaled down
+// as its latency grows. Unmeasured proxies score 1 so they get tried.
+func (h *health) score() float64 {
+	return h.successRate * float64(latencyScale) / float64(latencyScale+h.latency)
+}
+
+func (h *health) success(latency time.Duration) {
+	h.successes++
+	h.consecutive = 0
+	h.ejectedUntil = time.Time{}
+	h.successRate += healthDecay * (1 - h.successRate)
+	if h.latency == 0 {
+		h.latency = latency
+	} else {
+		h.latency += time.Duration(healthDecay * float64(latency-h.latency))
+	}
+}
+
+// failure records a failed request and reports whether it ejected the proxy.
+// A proxy back from a cooldown keeps its failure streak, so one more
+// failure ejects it again.
+func (h *health) failure(err error, now time.Time, cfg HealthConfig) bool {
+	h.failures++
+	h.consecutive++
+	h.lastFailure = now
+	h.successRate -= healthDecay * h.successRate
+	if err != nil {
+		h.lastError = err.Error()
+	}
+	if h.consecutive < cfg.FailureThreshold || h.ejected(now) {
+		return false
+	}
+	h.ejectedUntil = now.Add(cfg.Cooldown)
+	return true
+}
+
+// Stats is a snapshot of one proxy's health.
+type Stats struct {
+	Proxy               string        // Proxy URL with the password masked
+	Successes           int           // Successful requests reported
+	Failures            int           // Failed requests reported
+	ConsecutiveFailures int           // Failures since the last success
+	Latency             time.Duration // Moving average latency of successful requests
+	Score               float64       // 0 (failing, slow) to 1 (healthy, fast)
+	EjectedUntil        time.Time     // Zero unless the proxy is cooling down
+	LastError           string
+	EgressIP            string // IP the proxy exits from; "" until verification reached it
+	Country             string // Verified exit country, else the configured one
+	Region              string // Verified exit region, else the configured one
+	Verified            bool   // The exit location was verified (see StartVerify)
+}
+
+// ReportSuccess records a request through proxyURL that succeeded in
+// latency. A success ends an ejection early. Unknown proxies are ignored.
+func (r *ProxyRotator) ReportSuccess(proxyURL string, latency time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if h, ok := r.health[proxyURL]; ok {
+		h.success(latency)
+	}
+}
+
+// ReportFailure records a request through proxyURL that failed because of
+// the proxy. After HealthConfig.FailureThreshold failures in a row the proxy
+// is skipped for HealthConfig.Cooldown. Unknown proxies are ignored.
+func (r *ProxyRotator) ReportFailure(proxyURL string, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	h, ok := r.health[proxyURL]
+	if !ok || !h.failure(err, r.now(), r.healthCfg) {
+		return
+	}
+	r.logger.Warn("proxy ejected",
+		slog.String("proxy", sanitizeProxyLog(proxyURL)),
+		slog.Int("consecutive_failures", h.consecutive),
+		slog.Duration("cooldown", r.healthCfg.Cooldown),
+		slog.String("last_error", h.lastError),
+	)
+}
+
+// Stats returns the health of every proxy, in configuration order.
+func (r *ProxyRotator) Stats() []Stats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := r.now()
+	stats := make([]Stats, 0, len(r.proxies))
+	for _, p := range r.proxies {
+		h := r.health[p.URL]
+		s := Stats{
+			Proxy:               sanitizeProxyLog(p.URL),
+			Successes:           h.successes,
+			Failures:            h.failures,
+			ConsecutiveFailures: h.consecutive,
+			Latency:             h.latency,
+			Score:               h.score(),
+			LastError:           h.lastError,
+			Verified:            h.egress.verified,
+		}
+		s.Country, s.Region = location(p, h)
+		if h.egress.ip.IsValid() {
+			s.EgressIP = h.egress.ip.String()
+		}
+		if h.ejected(now) {
+			s.EjectedUntil = h.ejectedUntil
+		}
+		stats = append(stats, s)
+	}
+	return stats
+}
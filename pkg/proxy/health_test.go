@@ -0,0 +1,128 @@
+package proxy
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// testClock is a settable clock for cooldown tests.
+type testClock struct{ t time.Time }
+
+func (c *testClock) now() time.Time { return c.t }
+
+func newHealthRotator(t *testing.T, strategy string) (*ProxyRotator, *testClock) {
+	t.Helper()
+	r, err := New(Config{
+		URLs:     "http://p1.example.com:8080,http://p2.example.com:8080",
+		Strategy: strategy,
+		Health:   HealthConfig{FailureThreshold: 2, Cooldown: time.Minute},
+		Logger:   testLogger(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	clock := &testClock{t: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)}
+	r.now = clock.now
+	return r, clock
+}
+
+func TestReportFailure_EjectsAfterThreshold(t *testing.T) {
+	r, clock := newHealthRotator(t, "round-robin")
+	const p1, p2 = "http://p1.example.com:8080", "http://p2.example.com:8080"
+	errTunnel := errors.New("net::ERR_TUNNEL_CONNECTION_FAILED")
+
+	r.ReportFailure(p1, errTunnel)
+	if got := []string{r.Next(), r.Next()}; got[0] != p1 || got[1] != p2 {
+		t.Fatalf("one failure should not eject: got %v", got)
+	}
+
+	r.ReportFailure(p1, errTunnel)
+	for i := 0; i < 3; i++ {
+		if got := r.Next(); got != p2 {
+			t.Fatalf("call %d: got %q, want only %q while p1 is ejected", i, got, p2)
+		}
+	}
+
+	// Back after the cooldown, but still on its failure streak.
+	clock.t = clock.t.Add(time.Minute)
+	if got := []string{r.Next(), r.Next()}; got[0] == got[1] {
+		t.Fatalf("p1 should be back after the cooldown: got %v", got)
+	}
+	r.ReportFailure(p1, errTunnel)
+	if stats := r.Stats(); stats[0].EjectedUntil.IsZero() || stats[0].LastError != errTunnel.Error() {
+		t.Errorf("one failure after the cooldown should eject again: %+v", stats[0])
+	}
+
+	// A success ends the ejection early.
+	r.ReportSuccess(p1, 100*time.Millisecond)
+	if stats := r.Stats(); !stats[0].EjectedUntil.IsZero() || stats[0].ConsecutiveFailures != 0 {
+		t.Errorf("success should reinstate p1: %+v", stats[0])
+	}
+}
+
+func TestNext_AllEjectedFallsBackToDirect(t *testing.T) {
+	for _, strategy := range []string{"round-robin", "random"} {
+		t.Run(strategy, func(t *testing.T) {
+			r, _ := newHealthRotator(t, strategy)
+			for _, p := range r.proxies {
+				r.ReportFailure(p.URL, nil)
+				r.ReportFailure(p.URL, nil)
+			}
+			if got := r.Next(); got != "" {
+				t.Errorf("got %q, want direct connection", got)
+			}
+		})
+	}
+}
+
+func TestRoundRobin_SkipsLowScoringProxies(t *testing.T) {
+	r, _ := newHealthRotator(t, "round-robin")
+	const p1, p2 = "http://p1.example.com:8080", "http://p2.example.com:8080"
+	r.ReportSuccess(p1, 100*time.Millisecond)
+	r.ReportSuccess(p2, 10*time.Second)
+	for i := 0; i < 4; i++ {
+		if got := r.Next(); got != p1 {
+			t.Fatalf("call %d: got %q, want only the fast proxy", i, got)
+		}
+	}
+
+	// The slow proxy is still used once it is the only one available.
+	r.ReportFailure(p1, nil)
+	r.ReportFailure(p1, nil)
+	if got := r.Next(); got != p2 {
+		t.Errorf("got %q, want %q while p1 is ejected", got, p2)
+	}
+}
+
+func TestNextRandom_SkipsEjected(t *testing.T) {
+	r, _ := newHealthRotator(t, "random")
+	const p1 = "http://p1.example.com:8080"
+	r.ReportFailure(p1, nil)
+	r.ReportFailure(p1, nil)
+	for i := 0; i < 50; i++ {
+		if got := r.Next(); got == p1 {
+			t.Fatalf("call %d: picked ejected proxy", i)
+		}
+	}
+}
+
+func TestStats_Score(t *testing.T) {
+	r, _ := newHealthRotator(t, "round-robin")
+	const p1, p2 = "http://p1.example.com:8080", "http://p2.example.com:8080"
+	r.ReportSuccess(p1, 200*time.Millisecond)
+	r.ReportSuccess(p2, 4*time.Second)
+	r.ReportFailure(p2, nil)
+	r.ReportFailure("http://unknown.example.com:8080", nil)
+
+	stats := r.Stats()
+	if stats[0].Successes != 1 || stats[0].Latency != 200*time.Millisecond {
+		t.Errorf("unexpected p1 stats %+v", stats[0])
+	}
+	if stats[1].Failures != 1 || stats[1].ConsecutiveFailures != 1 {
+		t.Errorf("unexpected p2 stats %+v", stats[1])
+	}
+	if !(stats[0].Score > stats[1].Score) || stats[0].Score > 1 || stats[1].Score <= 0 {
+		t.Errorf("fast healthy proxy should outscore a slow failing one: %v vs %v", stats[0].Score, stats[1].Score)
+	}
+}
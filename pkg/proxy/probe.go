@@ -0,0 +1,117 @@
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ProbeConfig configures the background health probe.
+type ProbeConfig struct {
+	URL      string        // Fetched through every proxy; a 2xx or 3xx response is healthy
+	Interval time.Duration // Time between rounds (default 5m)
+	Timeout  time.Duration // Per-request timeout (default 15s)
+}
+
+const (
+	defaultProbeInterval = 5 * time.Minute
+	defaultProbeTimeout  = 15 * time.Second
+)
+
+// StartProbe probes every proxy once now and then every cfg.Interval until
+// ctx is cancelled, so dead proxies are ejected before a scrape reaches
+// them and recovered ones return before their cooldown ends. It does
+// nothing when cfg.URL is empty or no proxies are configured.
+func (r *ProxyRotator) StartProbe(ctx context.Context, cfg ProbeConfig) {
+	if cfg.URL == "" || !r.HasProxies() {
+		return
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = defaultProbeInterval
+	}
+
+	go func() {
+		ticker := time.NewTicker(cfg.Interval)
+		defer ticker.Stop()
+		for {
+			r.Probe(ctx, cfg)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Probe fetches cfg.URL through every proxy concurrently, ejected ones
+// included, and reports each outcome. It returns when all probes finish.
+func (r *ProxyRotator) Probe(ctx context.Context, cfg ProbeConfig) {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultProbeTimeout
+	}
+
+	r.mu.Lock()
+	proxies := make([]string, 0, len(r.proxies))
+	for _, p := range r.proxies {
+		proxies = append(proxies, p.URL)
+	}
+	r.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, p := range proxies {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			start := time.Now()
+			err := probe(ctx, p, cfg)
+			if ctx.Err() != nil {
+				return // Shutting down; not the proxy's fault.
+			}
+			if err != nil {
+				r.logger.Debug("proxy probe failed",
+					slog.String("proxy", sanitizeProxyLog(p)),
+					slog.String("error", err.Error()),
+				)
+				r.ReportFailure(p, err)
+				return
+			}
+			r.ReportSuccess(p, time.Since(start))
+		}()
+	}
+	wg.Wait()
+}
+
+// probe makes one request to cfg.URL through proxyURL.
+func probe(ctx context.Context, proxyURL string, cfg ProbeConfig) error {
+	ctx, cancel := context.WithTimeout(ctx, cfg.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, cfg.URL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to build probe request: %w", err)
+	}
+	transport := newTransport(proxyURL)
+	defer transport.CloseIdleConnections()
+	client := &http.Client{
+		Transport: transport,
+		// A redirect answered through the proxy is proof enough.
+		CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 400 {
+		return fmt.Errorf("probe returned HTTP %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package proxy
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestProbe_ReportsEachProxy(t *testing.T) {
+	// A plain HTTP proxy receives the absolute target URL; answering it
+	// directly stands in for forwarding.
+	var gotURL string
+	good := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		gotURL = req.URL.String()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer good.Close()
+	blocked := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		http.Error(w, "denied", http.StatusForbidden)
+	}))
+	defer blocked.Close()
+	// A port with nothing listening.
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dead := "http://" + ln.Addr().String()
+	ln.Close()
+
+	r, err := New(Config{
+		URLs:   good.URL + "," + blocked.URL + "," + dead,
+		Health: HealthConfig{FailureThreshold: 1, Cooldown: time.Minute},
+		Logger: testLogger(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r.Probe(context.Background(), ProbeConfig{URL: "http://probe.example.com/generate_204", Timeout: 5 * time.Second})
+
+	if gotURL != "http://probe.example.com/generate_204" {
+		t.Errorf("proxy got request for %q", gotURL)
+	}
+	stats := r.Stats()
+	if stats[0].Successes != 1 || !stats[0].EjectedUntil.IsZero() {
+		t.Errorf("healthy proxy: %+v", stats[0])
+	}
+	for _, s := range stats[1:] {
+		if s.Failures != 1 || s.EjectedUntil.IsZero() {
+			t.Errorf("failing proxy %s should be ejected: %+v", s.Proxy, s)
+		}
+	}
+	for i := 0; i < 3; i++ {
+		if got := r.Next(); got != good.URL {
+			t.Errorf("got %q, want the healthy proxy", got)
+		}
+	}
+}
+
+func TestStartProbe_StopsWithContext(t *testing.T) {
+	probed := make(chan struct{}, 10)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		probed <- struct{}{}
+	}))
+	defer srv.Close()
+
+	r, err := NewRotator(srv.URL, "round-robin", testLogger())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	r.StartProbe(ctx, ProbeConfig{URL: "http://probe.example.com/", Interval: 10 * time.Millisecond})
+
+	for i := 0; i < 2; i++ {
+		select {
+		case <-probed:
+		case <-time.After(5 * time.Second):
+			t.Fatal("probe did not run")
+		}
+	}
+	cancel()
+	if s := r.Stats()[0]; s.Successes < 1 {
+		t.Errorf("probe successes not recorded: %+v", s)
+	}
+}
@@ -0,0 +1,265 @@
+// Package proxytest provides local HTTP and SOCKS5 proxies for tests, in
+// the spirit of net/http/httptest. Both relay to the real destination and
+// can require a username and password.
+package proxytest
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+// Server is a running test proxy.
+type Server struct {
+	Scheme string // "http" or "socks5"
+	Addr   string // host:port it listens on
+
+	user, pass string
+	ln         net.Listener
+	wg         sync.WaitGroup
+	requests   atomic.Int64
+	rejected   atomic.Int64
+}
+
+// NewHTTP starts an HTTP proxy that handles CONNECT tunnels and plain
+// requests with absolute URLs. When user is not empty, requests without
+// matching Proxy-Authorization get 407 Proxy Authentication Required.
+func NewHTTP(user, pass string) *Server {
+	s := newServer("http", user, pass)
+	srv := &http.Server{Handler: http.HandlerFunc(s.serveHTTP)}
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		_ = srv.Serve(s.ln)
+	}()
+	return s
+}
+
+// NewSOCKS5 starts a SOCKS5 proxy supporting CONNECT. When user is not
+// empty, clients must authenticate with username and password (RFC 1929).
+func NewSOCKS5(user, pass string) *Server {
+	s := newServer("socks5", user, pass)
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		for {
+			conn, err := s.ln.Accept()
+			if err != nil {
+				return
+			}
+			s.wg.Add(1)
+			go func() {
+				defer s.wg.Done()
+				s.serveSOCKS(conn)
+			}()
+		}
+	}()
+	return s
+}
+
+func newServer(scheme, user, pass string) *Server {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		panic(fmt.Sprintf("proxytest: failed to listen: %v", err))
+	}
+	return &Server{Scheme: scheme, Addr: ln.Addr().String(), user: user, pass: pass, ln: ln}
+}
+
+// URL returns the proxy URL with the given credentials ("" for none).
+func (s *Server) URL(user, pass string) string {
+	u := url.URL{Scheme: s.Scheme, Host: s.Addr}
+	if user != "" {
+		u.User = url.UserPassword(user, pass)
+	}
+	return u.String()
+}
+
+// Requests returns the number of requests or tunnels relayed.
+func (s *Server) Requests() int { return int(s.requests.Load()) }
+
+// Rejected returns the number of clients refused for bad credentials.
+func (s *Server) Rejected() int { return int(s.rejected.Load()) }
+
+// Close stops the proxy. Relayed connections in flight are not waited for.
+func (s *Server) Close() {
+	s.ln.Close()
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if s.user != "" {
+		want := "Basic " + base64.StdEncoding.EncodeToString([]byte(s.user+":"+s.pass))
+		if r.Header.Get("Proxy-Authorization") != want {
+			s.rejected.Add(1)
+			w.Header().Set("Proxy-Authenticate", `Basic realm="proxytest"`)
+			http.Error(w, "proxy authentication required", http.StatusProxyAuthRequired)
+			return
+		}
+	}
+	s.requests.Add(1)
+
+	if r.Method == http.MethodConnect {
+		target, err := net.Dial("tcp", r.Host)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		defer target.Close()
+		client, buf, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			return
+		}
+		defer client.Close()
+		_, _ = client.Write([]byte("HTTP/1.1 200 Connection Established\r\n\r\n"))
+		relay(client, buf.Reader, target)
+		return
+	}
+
+	out := r.Clone(r.Context())
+	out.RequestURI = ""
+	out.Header.Del("Proxy-Authorization")
+	out.Header.Del("Proxy-Connection")
+	resp, err := http.DefaultTransport.RoundTrip(out)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	defer resp.Body.Close()
+	for k, v := range resp.Header {
+		w.Header()[k] = v
+	}
+	w.WriteHeader(resp.StatusCode)
+	_, _ = io.Copy(w, resp.Body)
+}
+
+// serveSOCKS handles one SOCKS5 client: greeting, optional
+// username/password authentication and a CONNECT request.
+func (s *Server) serveSOCKS(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+
+	// Greeting: VER NMETHODS METHODS...
+	head := make([]byte, 2)
+	if _, err := io.ReadFull(r, head); err != nil || head[0] != 5 {
+		return
+	}
+	methods := make([]byte, head[1])
+	if _, err := io.ReadFull(r, methods); err != nil {
+		return
+	}
+	method := byte(0x00) // No authentication
+	if s.user != "" {
+		method = 0x02 // Username/password
+	}
+	offered := false
+	for _, m := range methods {
+		offered = offered || m == method
+	}
+	if !offered {
+		s.rejected.Add(1)
+		_, _ = conn.Write([]byte{5, 0xff})
+		return
+	}
+	_, _ = conn.Write([]byte{5, method})
+
+	if method == 0x02 {
+		user, pass, err := readUserPass(r)
+		if err != nil {
+			return
+		}
+		if user != s.user || pass != s.pass {
+			s.rejected.Add(1)
+			_, _ = conn.Write([]byte{1, 1})
+			return
+		}
+		_, _ = conn.Write([]byte{1, 0})
+	}
+
+	// Request: VER CMD RSV ATYP DST.ADDR DST.PORT
+	req := make([]byte, 4)
+	if _, err := io.ReadFull(r, req); err != nil || req[1] != 1 {
+		_, _ = conn.Write([]byte{5, 7, 0, 1, 0, 0, 0, 0, 0, 0}) // Command not supported
+		return
+	}
+	host, err := readAddr(r, req[3])
+	if err != nil {
+		return
+	}
+	portBytes := make([]byte, 2)
+	if _, err := io.ReadFull(r, portBytes); err != nil {
+		return
+	}
+	target, err := net.Dial("tcp", net.JoinHostPort(host, strconv.Itoa(int(binary.BigEndian.Uint16(portBytes)))))
+	if err != nil {
+		_, _ = conn.Write([]byte{5, 5, 0, 1, 0, 0, 0, 0, 0, 0}) // Connection refused
+		return
+	}
+	defer target.Close()
+	s.requests.Add(1)
+	_, _ = conn.Write([]byte{5, 0, 0, 1, 0, 0, 0, 0, 0, 0})
+	relay(conn, r, target)
+}
+
+func readUserPass(r io.Reader) (user, pass string, err error) {
+	b := make([]byte, 2)
+	if _, err := io.ReadFull(r, b); err != nil || b[0] != 1 {
+		return "", "", errors.New("bad auth version")
+	}
+	u := make([]byte, b[1])
+	if _, err := io.ReadFull(r, u); err != nil {
+		return "", "", err
+	}
+	if _, err := io.ReadFull(r, b[:1]); err != nil {
+		return "", "", err
+	}
+	p := make([]byte, b[0])
+	if _, err := io.ReadFull(r, p); err != nil {
+		return "", "", err
+	}
+	return string(u), string(p), nil
+}
+
+func readAddr(r io.Reader, atyp byte) (string, error) {
+	switch atyp {
+	case 1: // IPv4
+		ip := make([]byte, 4)
+		_, err := io.ReadFull(r, ip)
+		return net.IP(ip).String(), err
+	case 4: // IPv6
+		ip := make([]byte, 16)
+		_, err := io.ReadFull(r, ip)
+		return net.IP(ip).String(), err
+	case 3: // Domain name
+		n := make([]byte, 1)
+		if _, err := io.ReadFull(r, n); err != nil {
+			return "", err
+		}
+		name := make([]byte, n[0])
+		_, err := io.ReadFull(r, name)
+		return string(name), err
+	}
+	return "", fmt.Errorf("unknown address type %d", atyp)
+}
+
+// relay copies between client (read through its buffered reader) and
+// target until either side closes.
+func relay(client net.Conn, clientReader io.Reader, target net.Conn) {
+	done := make(chan struct{}, 2)
+	go func() {
+		_, _ = io.Copy(target, clientReader)
+		done <- struct{}{}
+	}()
+	go func() {
+		_, _ = io.Copy(client, target)
+		done <- struct{}{}
+	}()
+	<-done
+}
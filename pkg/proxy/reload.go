@@ -0,0 +1,221 @@
+package proxy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"reflect"
+	"time"
+)
+
+// Loader reads the current proxy list from a source such as a file or a
+// provider endpoint.
+type Loader func(ctx context.Context) ([]Proxy, error)
+
+// StaticLoader loads a fixed comma-separated list (see ParseList).
+func StaticLoader(list string) Loader {
+	return func(context.Context) ([]Proxy, error) {
+		return ParseList(list)
+	}
+}
+
+// Combine loads the lists of all loaders, in order, as one. It fails if any
+// of them fails.
+func Combine(loaders ...Loader) Loader {
+	return func(ctx context.Context) ([]Proxy, error) {
+		var proxies []Proxy
+		for _, load := range loaders {
+			list, err := load(ctx)
+			if err != nil {
+				return nil, err
+			}
+			proxies = append(proxies, list...)
+		}
+		return proxies, nil
+	}
+}
+
+// FileLoader loads the proxy list file at path (see ReadList).
+func FileLoader(path string) Loader {
+	return func(context.Context) ([]Proxy, error) {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		proxies, err := ReadList(f)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		return proxies, nil
+	}
+}
+
+// maxProviderResponse caps the size of a provider's proxy list.
+const maxProviderResponse = 4 << 20 // 4 MB
+
+// ProviderLoader loads the proxy list from a provider endpoint that answers
+// GET with JSON (see DecodeProviderList). An empty list is an error, so a
+// provider glitch never empties the pool. client defaults to one with a 30s
+// timeout.
+func ProviderLoader(endpoint string, client *http.Client) Loader {
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+	return func(ctx context.Context) ([]Proxy, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+		if err != nil {
+			return nil, fmt.Errorf("proxy provider: %w", err)
+		}
+		req.Header.Set("Accept", "application/json")
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("proxy provider: %w", err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("proxy provider: unexpected HTTP status %d", resp.StatusCode)
+		}
+		data, err := io.ReadAll(io.LimitReader(resp.Body, maxProviderResponse))
+		if err != nil {
+			return nil, fmt.Errorf("proxy provider: %w", err)
+		}
+
+		proxies, err := DecodeProviderList(data)
+		if err != nil {
+			return nil, fmt.Errorf("proxy provider: %w", err)
+		}
+		if len(proxies) == 0 {
+			return nil, errors.New("proxy provider: empty proxy list")
+		}
+		return proxies, nil
+	}
+}
+
+// Proxies returns the current pool.
+func (r *ProxyRotator) Proxies() []Proxy {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Proxy(nil), r.proxies...)
+}
+
+// errEmptyPool rejects a reload that would leave no proxies, e.g. from a
+// truncated list file.
+var errEmptyPool = errors.New("empty proxy list — keeping the current pool")
+
+// Replace swaps the pool for proxies in one step. Proxies that stay keep
+// their health, including any ejection; new ones start healthy. Sticky pins
+// to removed proxies are dropped. An empty list is an error, like an empty
+// provider list, so a half-written file never empties the pool. It reports
+// whether the pool changed.
+func (r *ProxyRotator) Replace(proxies []Proxy) (bool, error) {
+	if len(proxies) == 0 {
+		return false, errEmptyPool
+	}
+	pool := make([]Proxy, 0, len(proxies))
+	seen := make(map[string]bool, len(proxies))
+	for _, p := range proxies {
+		if err := validateProxyURL(p.URL); err != nil {
+			return false, fmt.Errorf("invalid proxy URL %q: %w", sanitizeProxyLog(p.URL), err)
+		}
+		if p.Weight <= 0 {
+			p.Weight = 1
+		}
+		if !seen[p.URL] {
+			seen[p.URL] = true
+			pool = append(pool, p)
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if reflect.DeepEqual(pool, r.proxies) {
+		return false, nil
+	}
+
+	health := make(map[string]*health, len(pool))
+	for _, p := range pool {
+		if h, ok := r.health[p.URL]; ok {
+			health[p.URL] = h
+		} else {
+			health[p.URL] = newHealth()
+		}
+	}
+	for host, p := range r.sticky {
+		if !seen[p] {
+			delete(r.sticky, host)
+		}
+	}
+	r.proxies, r.health = pool, health
+	return true, nil
+}
+
+// Reload loads the pool with load and replaces it (see Replace). Added
+// proxies are verified first when StartVerify is running. On error the
+// current pool is kept.
+func (r *ProxyRotator) Reload(ctx context.Context, load Loader) error {
+	proxies, err := load(ctx)
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	known := make(map[string]bool, len(r.health))
+	for p := range r.health {
+		known[p] = true
+	}
+	verifyCfg := r.verifyCfg
+	r.mu.Unlock()
+
+	changed, err := r.Replace(proxies)
+	if err != nil {
+		return err
+	}
+	if !changed {
+		return nil
+	}
+	r.logger.Info("proxy pool reloaded",
+		slog.Int("proxy_count", r.Count()),
+	)
+
+	// Verify added proxies now rather than at the next round of StartVerify.
+	if verifyCfg != nil {
+		var added []Proxy
+		for _, p := range r.Proxies() {
+			if !known[p.URL] {
+				added = append(added, p)
+			}
+		}
+		r.verify(ctx, *verifyCfg, added)
+	}
+	return nil
+}
+
+// StartReload reloads the pool with load every interval until ctx is
+// cancelled, so edits to the list file or the provider's list take effect
+// in a running scraper. Failed reloads are logged and keep the current pool.
+func (r *ProxyRotator) StartReload(ctx context.Context, load Loader, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			if err := r.Reload(ctx, load); err != nil && ctx.Err() == nil {
+				r.logger.Warn("proxy pool reload failed — keeping the current pool",
+					slog.String("error", err.Error()),
+				)
+			}
+		}
+	}()
+}
@@ -0,0 +1,158 @@
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestReplace_KeepsHealthOfRemainingProxies(t *testing.T) {
+	r, clock := newStrategyRotator(t, sp1+","+sp2, "sticky")
+	r.ReportSuccess(sp1, 300*time.Millisecond)
+	r.ReportFailure(sp2, nil) // Ejected: threshold 1
+	pinned := r.ProxyFor("https://ssc.gov.in/")
+
+	changed, err := r.Replace([]Proxy{{URL: sp2}, {URL: sp3, Country: "IN"}})
+	if err != nil || !changed {
+		t.Fatalf("Replace = %v, %v", changed, err)
+	}
+
+	stats := r.Stats()
+	if len(stats) != 2 {
+		t.Fatalf("expected 2 proxies, got %+v", stats)
+	}
+	if stats[0].Failures != 1 || stats[0].EjectedUntil.IsZero() {
+		t.Errorf("p2 should keep its ejection: %+v", stats[0])
+	}
+	if stats[1].Successes != 0 || stats[1].Failures != 0 {
+		t.Errorf("p3 should start fresh: %+v", stats[1])
+	}
+	if got := r.Proxies()[1]; got.Country != "IN" || got.Weight != 1 {
+		t.Errorf("unexpected new proxy %+v", got)
+	}
+	// p1 was pinned and removed; ssc.gov.in moves to the only healthy proxy.
+	if pinned != sp1 {
+		t.Fatalf("expected ssc.gov.in pinned to p1, got %q", pinned)
+	}
+	if got := r.ProxyFor("https://ssc.gov.in/"); got != sp3 {
+		t.Errorf("got %q, want %q", got, sp3)
+	}
+	// p1 is forgotten: reports for it are ignored.
+	r.ReportFailure(sp1, nil)
+	clock.t = clock.t.Add(2 * time.Minute)
+	if got := len(r.Stats()); got != 2 {
+		t.Errorf("removed proxy came back: %d proxies", got)
+	}
+
+	if changed, err := r.Replace(r.Proxies()); err != nil || changed {
+		t.Errorf("same list should be a no-op: %v, %v", changed, err)
+	}
+	if _, err := r.Replace([]Proxy{{URL: "ftp://p1.example.com"}}); err == nil {
+		t.Error("expected an error for an invalid proxy")
+	}
+	if _, err := r.Replace(nil); err == nil {
+		t.Error("expected an error for an empty list")
+	}
+	if got := r.Count(); got != 2 {
+		t.Errorf("failed Replace changed the pool: %d proxies", got)
+	}
+}
+
+func TestStartReload_PicksUpFileChanges(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "proxies.txt")
+	// Replace the file atomically, so a reload never sees it half-written.
+	write := func(content string) {
+		t.Helper()
+		tmp := path + ".tmp"
+		if err := os.WriteFile(tmp, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Rename(tmp, path); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(sp1 + "\n")
+
+	load := FileLoader(path)
+	proxies, err := load(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := New(Config{Proxies: proxies, Logger: testLogger()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.ReportSuccess(sp1, time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	r.StartReload(ctx, load, 5*time.Millisecond)
+
+	write("# broken\nftp://bad.example.com\n")
+	time.Sleep(30 * time.Millisecond)
+	if got := r.Proxies(); len(got) != 1 || got[0].URL != sp1 {
+		t.Fatalf("a bad file should keep the pool, got %+v", got)
+	}
+	write("# truncated\n")
+	time.Sleep(30 * time.Millisecond)
+	if got := r.Proxies(); len(got) != 1 || got[0].URL != sp1 {
+		t.Fatalf("an empty file should keep the pool, got %+v", got)
+	}
+
+	write(sp1 + "\n" + sp2 + " weight=3\n")
+	deadline := time.Now().Add(5 * time.Second)
+	for r.Count() != 2 {
+		if time.Now().After(deadline) {
+			t.Fatal("pool was not reloaded")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if stats := r.Stats(); stats[0].Successes != 1 {
+		t.Errorf("p1 lost its health on reload: %+v", stats[0])
+	}
+}
+
+func TestProviderLoader(t *testing.T) {
+	body := `{"proxies": [{"url": "http://p1.example.com:8080", "country": "IN"}]}`
+	status := http.StatusOK
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(status)
+		fmt.Fprint(w, body)
+	}))
+	defer srv.Close()
+
+	load := ProviderLoader(srv.URL, nil)
+	got, err := load(context.Background())
+	if err != nil || len(got) != 1 || got[0].Country != "IN" {
+		t.Fatalf("got %+v, %v", got, err)
+	}
+
+	body = `[]`
+	if _, err := load(context.Background()); err == nil {
+		t.Error("expected an error for an empty list")
+	}
+	body, status = `[]`, http.StatusServiceUnavailable
+	if _, err := load(context.Background()); err == nil {
+		t.Error("expected an error for HTTP 503")
+	}
+}
+
+func TestCombine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "proxies.txt")
+	if err := os.WriteFile(path, []byte(sp2+" country=IN\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Combine(StaticLoader(sp1), FileLoader(path))(context.Background())
+	if err != nil || len(got) != 2 || got[0].URL != sp1 || got[1].Country != "IN" {
+		t.Errorf("got %+v, %v", got, err)
+	}
+	if _, err := Combine(StaticLoader(sp1), FileLoader(path+".missing"))(context.Background()); err == nil {
+		t.Error("expected an error when one loader fails")
+	}
+}
@@ -0,0 +1,137 @@
+package proxy
+
+import "time"
+
+// selection is what one proxy selection asks for.
+type selection struct {
+	now    time.Time
+	region string // Required egress region; "" = any
+}
+
+// The selection methods below are called with r.mu held. Each skips proxies
+// that are not available (see available) and returns "" when none is.
+
+// available reports whether p may be selected for sel: it is not ejected,
+// its egress is verified if that is required, and it exits in sel.region.
+func (r *ProxyRotator) available(p Proxy, sel selection) bool {
+	h := r.health[p.URL]
+	if h.ejected(sel.now) || (r.requireVerified && !h.egress.verified) {
+		return false
+	}
+	country, region := location(p, h)
+	return inRegion(country, region, sel.region)
+}
+
+// location returns where p exits: its verified egress when known, else its
+// configured country and region.
+func location(p Proxy, h *health) (country, region string) {
+	if h.egress.verified {
+		return h.egress.country, h.egress.region
+	}
+	return p.Country, p.Region
+}
+
+// roundRobinMinShare is the share of the best available proxy's health
+// score a proxy needs to keep its turn in the round-robin cycle.
+const roundRobinMinShare = 0.5
+
+// nextRoundRobin cycles through the proxies in configuration order, skipping
+// those scoring under roundRobinMinShare of the best available one: a slow
+// or flaky proxy sits out while healthier ones take its turns, and rejoins
+// once its score recovers, e.g. from background probes.
+func (r *ProxyRotator) nextRoundRobin(sel selection) string {
+	best := -1.0
+	for _, p := range r.proxies {
+		if r.available(p, sel) {
+			best = max(best, r.health[p.URL].score())
+		}
+	}
+	if best < 0 {
+		return ""
+	}
+	for range r.proxies {
+		idx := r.index.Add(1) - 1
+		p := r.proxies[idx%int64(len(r.proxies))]
+		if r.available(p, sel) && r.health[p.URL].score() >= best*roundRobinMinShare {
+			return p.URL
+		}
+	}
+	return ""
+}
+
+// nextWeighted picks at random in proportion to each proxy's health score,
+// times its configured weight when useWeights is set.
+func (r *ProxyRotator) nextWeighted(sel selection, useWeights bool) string {
+	var total float64
+	available := make([]string, 0, len(r.proxies))
+	shares := make([]float64, 0, len(r.proxies))
+	for _, p := range r.proxies {
+		if !r.available(p, sel) {
+			continue
+		}
+		share := r.health[p.URL].score()
+		if useWeights {
+			share *= p.Weight
+		}
+		available = append(available, p.URL)
+		shares = append(shares, share)
+		total += share
+	}
+	if len(available) == 0 {
+		return ""
+	}
+	if total <= 0 {
+		return available[int(r.rand.Float64()*float64(len(available)))]
+	}
+
+	pick := r.rand.Float64() * total
+	for i, share := range shares {
+		pick -= share
+		if pick < 0 {
+			return available[i]
+		}
+	}
+	return available[len(available)-1]
+}
+
+// nextLeastRecentlyFailed picks the proxy whose last failure is oldest;
+// proxies that never failed come first. Ties go to the proxy used least
+// recently, so load still spreads across healthy proxies.
+func (r *ProxyRotator) nextLeastRecentlyFailed(sel selection) string {
+	var best *health
+	bestURL := ""
+	for _, p := range r.proxies {
+		if !r.available(p, sel) {
+			continue
+		}
+		h := r.health[p.URL]
+		if best == nil || h.lastFailure.Before(best.lastFailure) ||
+			(h.lastFailure.Equal(best.lastFailure) && h.lastUsed.Before(best.lastUsed)) {
+			best, bestURL = h, p.URL
+		}
+	}
+	return bestURL
+}
+
+// nextSticky returns the proxy pinned to host, pinning the next round-robin
+// proxy when host has none or its proxy is no longer available. Without a
+// host it is round-robin.
+func (r *ProxyRotator) nextSticky(sel selection, host string) string {
+	if host == "" {
+		return r.nextRoundRobin(sel)
+	}
+	if pinned, ok := r.sticky[host]; ok {
+		for _, p := range r.proxies {
+			if p.URL == pinned && r.available(p, sel) {
+				return pinned
+			}
+		}
+	}
+	p := r.nextRoundRobin(sel)
+	if p == "" {
+		delete(r.sticky, host)
+		return ""
+	}
+	r.sticky[host] = p
+	return p
+}
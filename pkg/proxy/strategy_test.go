@@ -0,0 +1,182 @@
+package proxy
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// seqRand returns its values in order, repeating the last one.
+type seqRand struct {
+	mu     sync.Mutex
+	values []float64
+}
+
+func (s *seqRand) Float64() float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v := s.values[0]
+	if len(s.values) > 1 {
+		s.values = s.values[1:]
+	}
+	return v
+}
+
+const (
+	sp1 = "http://p1.example.com:8080"
+	sp2 = "http://p2.example.com:8080"
+	sp3 = "http://p3.example.com:8080"
+)
+
+func newStrategyRotator(t *testing.T, urls, strategy string, values ...float64) (*ProxyRotator, *testClock) {
+	t.Helper()
+	r, err := New(Config{
+		URLs:     urls,
+		Strategy: strategy,
+		Health:   HealthConfig{FailureThreshold: 1, Cooldown: time.Minute},
+		Rand:     &seqRand{values: append(values, 0)},
+		Logger:   testLogger(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	clock := &testClock{t: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)}
+	r.now = clock.now
+	return r, clock
+}
+
+func TestWeighted_FollowsWeights(t *testing.T) {
+	// Shares 1 and 3 out of 4: draws below 0.25 pick p1.
+	r, _ := newStrategyRotator(t, sp1+","+sp2+" weight=3", "weighted", 0.2, 0.3, 0.99)
+	for i, want := range []string{sp1, sp2, sp2} {
+		if got := r.Next(); got != want {
+			t.Errorf("draw %d: got %q, want %q", i, got, want)
+		}
+	}
+}
+
+func TestWeighted_ScalesByHealth(t *testing.T) {
+	r, _ := newStrategyRotator(t, sp1+" weight=2,"+sp2, "weighted", 0.6)
+	// 2s latency halves p1's score, so both shares are 1: 0.6 of 2 is p2.
+	r.ReportSuccess(sp1, 2*time.Second)
+	if got := r.Next(); got != sp2 {
+		t.Errorf("got %q, want %q", got, sp2)
+	}
+}
+
+func TestRandom_IgnoresWeights(t *testing.T) {
+	r, _ := newStrategyRotator(t, sp1+","+sp2+" weight=3", "random", 0.3)
+	if got := r.Next(); got != sp1 {
+		t.Errorf("got %q, want %q", got, sp1)
+	}
+}
+
+func TestWeighted_SkipsEjected(t *testing.T) {
+	r, _ := newStrategyRotator(t, sp1+" weight=100,"+sp2, "weighted", 0.0, 0.5, 0.99)
+	r.ReportFailure(sp1, nil)
+	for i := 0; i < 3; i++ {
+		if got := r.Next(); got != sp2 {
+			t.Errorf("draw %d: got %q, want %q", i, got, sp2)
+		}
+	}
+}
+
+func TestLeastRecentlyFailed(t *testing.T) {
+	r, clock := newStrategyRotator(t, sp1+","+sp2+","+sp3, "least-recently-failed")
+	r.healthCfg.FailureThreshold = 10 // Keep failing proxies in the pool.
+
+	r.ReportFailure(sp1, nil)
+	clock.t = clock.t.Add(time.Second)
+	r.ReportFailure(sp2, nil)
+	clock.t = clock.t.Add(time.Second)
+
+	// p3 never failed; the next oldest failure is p1's.
+	if got := r.Next(); got != sp3 {
+		t.Errorf("got %q, want the proxy that never failed", got)
+	}
+	r.ReportFailure(sp3, nil)
+	clock.t = clock.t.Add(time.Second)
+	if got := r.Next(); got != sp1 {
+		t.Errorf("got %q, want the least recently failed %q", got, sp1)
+	}
+}
+
+func TestLeastRecentlyFailed_SpreadsHealthyProxies(t *testing.T) {
+	r, clock := newStrategyRotator(t, sp1+","+sp2, "least-recently-failed")
+	var got []string
+	for i := 0; i < 4; i++ {
+		got = append(got, r.Next())
+		clock.t = clock.t.Add(time.Second)
+	}
+	if want := []string{sp1, sp2, sp1, sp2}; !equalStrings(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestSticky_PinsProxyPerHost(t *testing.T) {
+	r, _ := newStrategyRotator(t, sp1+","+sp2+","+sp3, "sticky")
+
+	ssc := r.ProxyFor("https://ssc.gov.in/page1")
+	for _, u := range []string{"https://ssc.gov.in/page2", "https://SSC.gov.in:443/page3"} {
+		if got := r.ProxyFor(u); got != ssc {
+			t.Errorf("%s: got %q, want pinned %q", u, got, ssc)
+		}
+	}
+	upsc := r.ProxyFor("https://upsc.gov.in/")
+	if upsc == ssc {
+		t.Errorf("second host should get the next proxy, got %q for both", upsc)
+	}
+
+	// An ejected proxy loses its pin; the host moves to another and stays.
+	r.ReportFailure(ssc, nil)
+	moved := r.ProxyFor("https://ssc.gov.in/page4")
+	if moved == ssc || moved == "" {
+		t.Fatalf("got %q after ejecting %q", moved, ssc)
+	}
+	if got := r.ProxyFor("https://ssc.gov.in/page5"); got != moved {
+		t.Errorf("got %q, want new pin %q", got, moved)
+	}
+	if got := r.ProxyFor("https://upsc.gov.in/"); got != upsc {
+		t.Errorf("other host lost its pin: got %q, want %q", got, upsc)
+	}
+}
+
+func TestStrategies_ConcurrencySafe(t *testing.T) {
+	for _, strategy := range []string{"random", "weighted", "least-recently-failed", "sticky", "round-robin"} {
+		t.Run(strategy, func(t *testing.T) {
+			r, err := New(Config{URLs: sp1 + "," + sp2 + " weight=2," + sp3, Strategy: strategy, Logger: testLogger()})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var wg sync.WaitGroup
+			for i := 0; i < 50; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					p := r.ProxyFor("https://ssc.gov.in/")
+					if p != sp1 && p != sp2 && p != sp3 {
+						t.Errorf("unexpected proxy %q", p)
+					}
+					if i%5 == 0 {
+						r.ReportFailure(p, nil)
+					} else {
+						r.ReportSuccess(p, time.Millisecond)
+					}
+				}()
+			}
+			wg.Wait()
+		})
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,206 @@
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/netip"
+	"strings"
+	"sync"
+	"time"
+)
+
+// VerifyConfig configures egress verification: finding out where each proxy
+// really exits by asking an echo endpoint for the caller's IP through the
+// proxy and locating that IP in a local GeoIP database.
+type VerifyConfig struct {
+	// EchoURL answers GET with the caller's IP, as plain text (e.g.
+	// https://api.ipify.org) or JSON with an "ip", "origin" or "query" field.
+	EchoURL  string
+	GeoDB    *GeoDB
+	Interval time.Duration // Time between rounds of StartVerify (default 1h)
+	Timeout  time.Duration // Per-request timeout (default 15s)
+}
+
+const (
+	defaultVerifyInterval = time.Hour
+	defaultVerifyTimeout  = 15 * time.Second
+
+	// maxEchoResponse caps the size of an echo endpoint's answer.
+	maxEchoResponse = 64 << 10
+)
+
+// egress is where a proxy was last seen to exit. Guarded by ProxyRotator.mu.
+type egress struct {
+	ip       netip.Addr
+	country  string
+	region   string
+	verified bool // Located by the last verification
+}
+
+// StartVerify verifies every proxy before returning, then again every
+// cfg.Interval until ctx is cancelled; Reload verifies proxies it adds.
+// With Config.RequireVerified set, proxies are only selected once verified.
+// It does nothing when cfg.EchoURL is empty or cfg.GeoDB is nil.
+func (r *ProxyRotator) StartVerify(ctx context.Context, cfg VerifyConfig) {
+	if cfg.EchoURL == "" || cfg.GeoDB == nil {
+		return
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = defaultVerifyInterval
+	}
+	r.mu.Lock()
+	r.verifyCfg = &cfg
+	r.mu.Unlock()
+
+	r.Verify(ctx, cfg)
+	go func() {
+		ticker := time.NewTicker(cfg.Interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			r.Verify(ctx, cfg)
+		}
+	}()
+}
+
+// Verify locates the egress of every proxy concurrently and returns when
+// all are done. A proxy whose egress cannot be found or located is left
+// unverified; one exiting outside its configured country is logged and
+// from then on selected by where it really exits.
+func (r *ProxyRotator) Verify(ctx context.Context, cfg VerifyConfig) {
+	r.verify(ctx, cfg, r.Proxies())
+}
+
+// verify locates the egress of proxies.
+func (r *ProxyRotator) verify(ctx context.Context, cfg VerifyConfig, proxies []Proxy) {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultVerifyTimeout
+	}
+
+	var wg sync.WaitGroup
+	for _, p := range proxies {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			e, err := locateEgress(ctx, p.URL, cfg)
+			if ctx.Err() != nil {
+				return // Shutting down; keep what we knew.
+			}
+			r.recordEgress(p, e, err)
+		}()
+	}
+	wg.Wait()
+}
+
+// recordEgress stores the outcome of verifying p and logs anything amiss.
+func (r *ProxyRotator) recordEgress(p Proxy, e egress, err error) {
+	r.mu.Lock()
+	h, ok := r.health[p.URL]
+	if ok {
+		h.egress = e
+	}
+	r.mu.Unlock()
+	if !ok {
+		return // Removed by a reload meanwhile.
+	}
+
+	configured := p.Country
+	if p.Region != "" {
+		configured = p.Region
+	}
+	attrs := []any{slog.String("proxy", sanitizeProxyLog(p.URL))}
+	switch {
+	case err != nil:
+		r.logger.Warn("proxy egress verification failed",
+			append(attrs, slog.String("error", err.Error()))...)
+	case !inRegion(e.country, e.region, configured):
+		r.logger.Warn("proxy exits outside its configured location",
+			append(attrs,
+				slog.String("egress_ip", e.ip.String()),
+				slog.String("configured", configured),
+				slog.String("country", e.country),
+				slog.String("region", e.region),
+			)...)
+	default:
+		r.logger.Debug("proxy egress verified",
+			append(attrs,
+				slog.String("egress_ip", e.ip.String()),
+				slog.String("country", e.country),
+				slog.String("region", e.region),
+			)...)
+	}
+}
+
+// locateEgress asks cfg.EchoURL for the IP proxyURL exits from and looks it
+// up in cfg.GeoDB. On failure the returned egress holds what was learnt.
+func locateEgress(ctx context.Context, proxyURL string, cfg VerifyConfig) (egress, error) {
+	ip, err := egressIP(ctx, proxyURL, cfg)
+	if err != nil {
+		return egress{}, err
+	}
+	country, region, ok := cfg.GeoDB.Lookup(ip)
+	if !ok {
+		return egress{ip: ip}, fmt.Errorf("egress IP %s is not in the GeoIP database", ip)
+	}
+	return egress{ip: ip, country: country, region: region, verified: true}, nil
+}
+
+// egressIP fetches cfg.EchoURL through proxyURL and parses the IP it reports.
+func egressIP(ctx context.Context, proxyURL string, cfg VerifyConfig) (netip.Addr, error) {
+	ctx, cancel := context.WithTimeout(ctx, cfg.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, cfg.EchoURL, nil)
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("failed to build echo request: %w", err)
+	}
+	transport := newTransport(proxyURL)
+	defer transport.CloseIdleConnections()
+
+	resp, err := (&http.Client{Transport: transport}).Do(req)
+	if err != nil {
+		return netip.Addr{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return netip.Addr{}, fmt.Errorf("echo endpoint returned HTTP %d", resp.StatusCode)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxEchoResponse))
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("failed to read echo response: %w", err)
+	}
+	return parseEchoIP(body)
+}
+
+// parseEchoIP reads the IP from an echo endpoint's answer: the bare IP, or
+// a JSON object with it under "ip" (ipify), "origin" (httpbin, where a
+// chain of proxies yields "client, proxy") or "query" (ip-api).
+func parseEchoIP(body []byte) (netip.Addr, error) {
+	text := strings.TrimSpace(string(body))
+	if ip, err := netip.ParseAddr(text); err == nil {
+		return ip, nil
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(body, &fields); err == nil {
+		for _, key := range []string{"ip", "origin", "query"} {
+			if s, ok := fields[key].(string); ok {
+				first, _, _ := strings.Cut(s, ",")
+				if ip, err := netip.ParseAddr(strings.TrimSpace(first)); err == nil {
+					return ip, nil
+				}
+			}
+		}
+	}
+	if len(text) > 64 {
+		text = text[:64] + "…"
+	}
+	return netip.Addr{}, fmt.Errorf("echo endpoint answered without an IP: %q", text)
+}
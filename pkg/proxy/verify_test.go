@@ -0,0 +1,175 @@
+package proxy
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/entreya/job-aggregation/pkg/proxy/proxytest"
+)
+
+// loopbackGeoDB places every loopback address in Karnataka, India.
+func loopbackGeoDB(t *testing.T) *GeoDB {
+	t.Helper()
+	db, err := ReadGeoDB(strings.NewReader("127.0.0.0,127.255.255.255,IN,IN-KA\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+// echoServer answers with the caller's IP, as api.ipify.org does.
+func echoServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host, _, _ := net.SplitHostPort(r.RemoteAddr)
+		io.WriteString(w, host)
+	}))
+}
+
+func deadProxy(t *testing.T) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	return "http://" + ln.Addr().String()
+}
+
+func TestVerify_LocatesEgress(t *testing.T) {
+	echo := echoServer()
+	defer echo.Close()
+	inProxy := proxytest.NewHTTP("", "")
+	defer inProxy.Close()
+	mislabelled := proxytest.NewSOCKS5("user", "pass")
+	defer mislabelled.Close()
+	dead := deadProxy(t)
+
+	r, err := New(Config{
+		Proxies: []Proxy{
+			{URL: inProxy.URL("", ""), Country: "IN", Region: "IN-KA"},
+			{URL: mislabelled.URL("user", "pass"), Country: "US"},
+			{URL: dead, Country: "IN"},
+		},
+		RequireVerified: true,
+		Logger:          testLogger(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := r.Next(); got != "" {
+		t.Errorf("before verification got %q, want no proxy", got)
+	}
+
+	r.Verify(context.Background(), VerifyConfig{EchoURL: echo.URL, GeoDB: loopbackGeoDB(t), Timeout: 5 * time.Second})
+
+	stats := r.Stats()
+	for _, s := range stats[:2] {
+		if !s.Verified || s.EgressIP != "127.0.0.1" || s.Country != "IN" || s.Region != "IN-KA" {
+			t.Errorf("verified proxy %s: %+v", s.Proxy, s)
+		}
+	}
+	if stats[2].Verified || stats[2].Country != "IN" {
+		t.Errorf("dead proxy: %+v", stats[2])
+	}
+	if inProxy.Requests() != 1 || mislabelled.Requests() != 1 {
+		t.Errorf("echo requests through proxies: %d and %d, want 1 each", inProxy.Requests(), mislabelled.Requests())
+	}
+
+	// The proxy labelled US really exits in India; the dead one is skipped.
+	seen := map[string]bool{}
+	for i := 0; i < 6; i++ {
+		seen[r.ProxyForRegion("https://recruitment.nic.in/", "IN-KA")] = true
+	}
+	if len(seen) != 2 || seen[dead] || seen[""] {
+		t.Errorf("IN-KA selections %v, want the two verified proxies", seen)
+	}
+	if got := r.ProxyForRegion("https://example.com/", "US"); got != "" {
+		t.Errorf("US selection got %q, want none", got)
+	}
+}
+
+func TestProxyForRegion_ConfiguredLocation(t *testing.T) {
+	r, err := New(Config{
+		URLs:   "http://mh.example.com:8080 region=IN-MH, http://us.example.com:8080 country=US, http://unknown.example.com:8080",
+		Logger: testLogger(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := map[string][]string{
+		"":      {"http://mh.example.com:8080", "http://us.example.com:8080", "http://unknown.example.com:8080"},
+		"IN":    {"http://mh.example.com:8080", "http://unknown.example.com:8080"},
+		"IN-MH": {"http://mh.example.com:8080", "http://unknown.example.com:8080"},
+		"IN-KA": {"http://unknown.example.com:8080"},
+		"US":    {"http://us.example.com:8080", "http://unknown.example.com:8080"},
+	}
+	for region, want := range tests {
+		seen := map[string]bool{}
+		for i := 0; i < 6; i++ {
+			seen[r.ProxyForRegion("https://example.com/", region)] = true
+		}
+		if len(seen) != len(want) {
+			t.Errorf("region %q: got %v, want %v", region, seen, want)
+		}
+		for _, p := range want {
+			if !seen[p] {
+				t.Errorf("region %q: %s never selected (got %v)", region, p, seen)
+			}
+		}
+	}
+}
+
+func TestStartVerify_VerifiesReloadedProxies(t *testing.T) {
+	echo := echoServer()
+	defer echo.Close()
+	first := proxytest.NewHTTP("", "")
+	defer first.Close()
+	second := proxytest.NewHTTP("", "")
+	defer second.Close()
+
+	r, err := New(Config{URLs: first.URL("", ""), RequireVerified: true, Logger: testLogger()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	r.StartVerify(ctx, VerifyConfig{EchoURL: echo.URL, GeoDB: loopbackGeoDB(t), Interval: time.Hour})
+	if got := r.Next(); got != first.URL("", "") {
+		t.Fatalf("got %q, want the verified proxy", got)
+	}
+
+	if err := r.Reload(ctx, StaticLoader(first.URL("", "")+","+second.URL("", ""))); err != nil {
+		t.Fatal(err)
+	}
+	if first.Requests() != 1 || second.Requests() != 1 {
+		t.Errorf("echo requests: %d and %d, want the added proxy verified once", first.Requests(), second.Requests())
+	}
+	if !r.Stats()[1].Verified {
+		t.Errorf("added proxy not verified: %+v", r.Stats()[1])
+	}
+}
+
+func TestParseEchoIP(t *testing.T) {
+	for body, want := range map[string]string{
+		"203.0.113.7\n":                                 "203.0.113.7",
+		`{"ip": "2001:db8::7"}`:                         "2001:db8::7",
+		`{"origin": "203.0.113.7, 10.0.0.1"}`:           "203.0.113.7",
+		`{"status": "success", "query": "203.0.113.7"}`: "203.0.113.7",
+	} {
+		ip, err := parseEchoIP([]byte(body))
+		if err != nil || ip.String() != want {
+			t.Errorf("parseEchoIP(%q) = %v, %v; want %s", body, ip, err, want)
+		}
+	}
+	for _, body := range []string{"", "<html>blocked</html>", `{"ip": "unknown"}`} {
+		if _, err := parseEchoIP([]byte(body)); err == nil {
+			t.Errorf("parseEchoIP(%q): expected an error", body)
+		}
+	}
+}
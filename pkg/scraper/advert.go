@@ -0,0 +1,354 @@
+package scraper
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/entreya/job-aggregation/pkg/dates"
+	"github.com/entreya/job-aggregation/pkg/models"
+	"github.com/ledongthuc/pdf"
+)
+
+// maxAdvertTextLen caps the advertisement text kept per job so a long
+// notification cannot bloat the database shipped to clients.
+const maxAdvertTextLen = 64 << 10 // 64 KB
+
+// Advert is a downloaded recruitment advertisement and the facts found in it.
+type Advert struct {
+	URL   string
+	Text  string
+	Facts AdvertFacts
+}
+
+// AdvertFacts holds the key facts extracted from an advertisement's text.
+// Fields are left zero when the advertisement does not state them.
+type AdvertFacts struct {
+	Vacancies       int
+	PayLevel        string           // e.g. "Level 7"
+	PayScale        string           // e.g. "Rs. 35,400-1,12,400"
+	AgeLimit        string           // e.g. "18-27 years" or "up to 56 years"
+	MinAge, MaxAge  int              // Years, from AgeLimit
+	ClosingDate     string           // YYYY-MM-DD
+	ClosingDateConf dates.Confidence // How ClosingDate was read
+	ClosingDays     int              // "Within 30 days of publication": days after the posting date
+	Qualifications  []string
+	ApplicationMode models.ApplicationMode
+}
+
+var (
+	// vacanciesRegex captures counts such as "No. of Posts: 12" or "Total Vacancies - 45".
+	vacanciesRegex = regexp.MustCompile(`(?i)\b(?:no\.?\s*of\s+(?:posts?|vacanc(?:y|ies))|total\s+(?:no\.?\s*of\s+)?(?:posts?|vacanc(?:y|ies))|vacanc(?:y|ies))\s*[:\-–]?\s*(\d{1,5})\b`)
+
+	// postsCountRegex captures counts written as "45 posts" or "12 vacancies".
+	postsCountRegex = regexp.MustCompile(`(?i)\b(\d{1,5})\s+(?:posts|vacancies)\b`)
+
+	// payLevelRegex captures pay matrix levels such as "Pay Level-7" or "Level 10".
+	payLevelRegex = regexp.MustCompile(`(?i)\blevel\s*[\-–:]?\s*(\d{1,2}[A-Z]?)\b`)
+
+	// ageRangeRegex captures "Age: 18 to 27 years" style ranges.
+	ageRangeRegex = regexp.MustCompile(`(?i)\bage\b[^0-9]{0,60}(\d{2})\s*(?:-|–|to)\s*(\d{2})\s*years`)
+
+	// ageMaxRegex captures upper-bound-only limits such as "age should not exceed 56 years".
+	ageMaxRegex = regexp.MustCompile(`(?i)\bage\b[^0-9]{0,60}(\d{2})\s*years`)
+
+	// payScaleRegex captures rupee ranges such as "Rs. 35,400 - 1,12,400" or "₹25500 to ₹81100".
+	payScaleRegex = regexp.MustCompile(`(?i)(?:rs\.?|₹|inr)\s*(\d[\d,]{3,})\s*(?:-|–|to)\s*(?:(?:rs\.?|₹|inr)\s*)?(\d[\d,]{3,})`)
+
+	// onlineApplicationRegex matches text inviting applications through a portal.
+	onlineApplicationRegex = regexp.MustCompile(`(?i)\b(?:apply|applications?|register)\b[^.\n]{0,40}\bonline\b|\bonline\s+(?:application|registration|mode)\b`)
+
+	// offlineApplicationRegex matches text asking for applications by post or in person.
+	offlineApplicationRegex = regexp.MustCompile(`(?i)\boffline\b|\bby\s+(?:speed\s+|registered\s+)?post\b|\bin\s+person\b|\bsend\s+(?:the(?:ir)?\s+|your\s+)?applications?\b`)
+)
+
+// qualificationPatterns find qualifications in advertisement text, highest
+// first: each match is removed before looking for lower ones, so "post
+// graduate" does not also count as "Graduate".
+var qualificationPatterns = []struct {
+	name string
+	re   *regexp.Regexp
+}{
+	{"Ph.D", regexp.MustCompile(`(?i)\bph\.?\s?d\b`)},
+	{"M.E./M.Tech", regexp.MustCompile(`(?i)\bm\.\s?e\.|\bm\.?\s?tech\b`)},
+	{"M.Sc", regexp.MustCompile(`(?i)\bm\.?\s?sc\b`)},
+	{"MBA", regexp.MustCompile(`(?i)\bmba\b`)},
+	{"Post Graduate", regexp.MustCompile(`(?i)\bpost[\s-]?graduat\w*(\s+degree)?|\bmaster'?s?\s+degree\b`)},
+	{"B.E./B.Tech", regexp.MustCompile(`(?i)\bb\.\s?e\.|\bb\.?\s?tech\b`)},
+	{"B.Sc", regexp.MustCompile(`(?i)\bb\.?\s?sc\b`)},
+	{"B.Com", regexp.MustCompile(`(?i)\bb\.?\s?com\b`)},
+	{"MBBS", regexp.MustCompile(`(?i)\bmbbs\b`)},
+	{"LLB", regexp.MustCompile(`(?i)\bll\.?\s?b\b`)},
+	{"Graduate", regexp.MustCompile(`(?i)\bgraduat\w*|\bbachelor'?s?(\s+degree)?\b`)},
+	{"Diploma", regexp.MustCompile(`(?i)\bdiploma\b`)},
+	{"ITI", regexp.MustCompile(`(?i)\biti\b`)},
+	{"12th", regexp.MustCompile(`(?i)\b12th\b|\bintermediate\b|\bhigher secondary\b|\b10\s?\+\s?2\b|\bclass\s+xii\b`)},
+	{"10th", regexp.MustCompile(`(?i)\b10th\b|\bmatric\w*|\bhigh school\b|\bclass\s+x\b`)},
+}
+
+// IsAdvertURL reports whether rawURL points at a PDF document.
+func IsAdvertURL(rawURL string) bool {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	return strings.HasSuffix(strings.ToLower(parsed.Path), ".pdf")
+}
+
+// FetchAdverts downloads the PDF advertisement linked by each job, using the
+// same proxy-then-direct path as FetchHTML, and extracts its text and key
+// facts. Facts are copied onto the job itself; a last date already taken
+// from the listing is kept. Jobs that do not link to a PDF are skipped.
+//
+// Returns the adverts keyed by job ID. A failed download is logged and
+// leaves the job unchanged.
+func (s *Scraper) FetchAdverts(ctx context.Context, jobs []*models.JobPosting, workers int) map[string]*Advert {
+	if workers <= 0 {
+		workers = 1
+	}
+
+	pending := make(chan *models.JobPosting)
+	var (
+		mu      sync.Mutex
+		adverts = make(map[string]*Advert)
+		failed  int
+		wg      sync.WaitGroup
+	)
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range pending {
+				advert, err := s.FetchAdvert(ctx, job.GetUrl())
+				if err != nil {
+					s.Logger.Warn("advertisement fetch failed",
+						slog.String("job_id", job.GetId()),
+						slog.String("url", job.GetUrl()),
+						slog.String("error", err.Error()),
+					)
+					mu.Lock()
+					failed++
+					mu.Unlock()
+					continue
+				}
+
+				mu.Lock()
+				applyAdvertFacts(job, advert.Facts)
+				adverts[job.GetId()] = advert
+				mu.Unlock()
+			}
+		}()
+	}
+
+feed:
+	for _, job := range jobs {
+		if !IsAdvertURL(job.GetUrl()) {
+			continue
+		}
+		select {
+		case pending <- job:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(pending)
+	wg.Wait()
+
+	s.Logger.Info("advertisements processed",
+		slog.Int("fetched", len(adverts)),
+		slog.Int("failed", failed),
+	)
+	return adverts
+}
+
+// FetchAdvert downloads one PDF advertisement and extracts its facts. Adverts
+// are not tied to a source, so any proxy may be used.
+func (s *Scraper) FetchAdvert(ctx context.Context, pdfURL string) (*Advert, error) {
+	var data []byte
+	err := s.fetchWithFallback(ctx, pdfURL, "", func(attemptCtx context.Context, proxyURL string) error {
+		body, fetchErr := FetchPDF(attemptCtx, pdfURL, proxyURL, s.Timeout)
+		if fetchErr != nil {
+			return fetchErr
+		}
+		data = body
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	text, err := ExtractPDFText(data)
+	if err != nil {
+		return nil, fmt.Errorf("extract text from %s: %w", pdfURL, err)
+	}
+
+	return &Advert{
+		URL:   pdfURL,
+		Text:  truncateUTF8(text, maxAdvertTextLen),
+		Facts: ExtractAdvertFacts(text),
+	}, nil
+}
+
+// ExtractPDFText returns the plain text of a PDF document with whitespace
+// collapsed and one line per text block. Scanned advertisements that carry
+// no text layer produce an error.
+func ExtractPDFText(data []byte) (text string, err error) {
+	// The PDF reader panics on some malformed documents.
+	defer func() {
+		if r := recover(); r != nil {
+			text, err = "", fmt.Errorf("malformed PDF: %v", r)
+		}
+	}()
+
+	reader, err := pdf.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return "", fmt.Errorf("open PDF: %w", err)
+	}
+	plain, err := reader.GetPlainText()
+	if err != nil {
+		return "", fmt.Errorf("read PDF text: %w", err)
+	}
+	raw, err := io.ReadAll(plain)
+	if err != nil {
+		return "", fmt.Errorf("read PDF text: %w", err)
+	}
+
+	lines := make([]string, 0, 64)
+	for _, line := range strings.Split(string(raw), "\n") {
+		if line = strings.Join(strings.Fields(line), " "); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if len(lines) == 0 {
+		return "", fmt.Errorf("PDF has no text layer")
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// ExtractAdvertFacts pulls vacancies, pay level and scale, age limits,
+// closing date, qualifications and application mode out of advertisement
+// text. The first match of each single-valued fact wins.
+func ExtractAdvertFacts(text string) AdvertFacts {
+	var facts AdvertFacts
+
+	if m := vacanciesRegex.FindStringSubmatch(text); m != nil {
+		facts.Vacancies, _ = strconv.Atoi(m[1])
+	} else if m := postsCountRegex.FindStringSubmatch(text); m != nil {
+		facts.Vacancies, _ = strconv.Atoi(m[1])
+	}
+
+	if m := payLevelRegex.FindStringSubmatch(text); m != nil {
+		facts.PayLevel = "Level " + strings.ToUpper(m[1])
+	}
+
+	if m := payScaleRegex.FindStringSubmatch(text); m != nil {
+		facts.PayScale = "Rs. " + m[1] + "-" + m[2]
+	}
+
+	if m := ageRangeRegex.FindStringSubmatch(text); m != nil {
+		facts.AgeLimit = m[1] + "-" + m[2] + " years"
+		facts.MinAge, _ = strconv.Atoi(m[1])
+		facts.MaxAge, _ = strconv.Atoi(m[2])
+	} else if m := ageMaxRegex.FindStringSubmatch(text); m != nil {
+		facts.AgeLimit = "up to " + m[1] + " years"
+		facts.MaxAge, _ = strconv.Atoi(m[1])
+	}
+
+	if r, ok := dates.Closing(text, time.Time{}); ok {
+		if r.Relative {
+			facts.ClosingDays = r.Days
+		} else {
+			facts.ClosingDate, facts.ClosingDateConf = r.Format(), r.Confidence
+		}
+	}
+
+	facts.Qualifications = extractQualifications(text)
+	facts.ApplicationMode = applicationMode(onlineApplicationRegex.MatchString(text), offlineApplicationRegex.MatchString(text))
+	return facts
+}
+
+// extractQualifications lists the qualifications mentioned in text, lowest
+// first. Returns nil when none is mentioned.
+func extractQualifications(text string) []string {
+	var found []string
+	for _, p := range qualificationPatterns {
+		if p.re.MatchString(text) {
+			found = append(found, p.name)
+			text = p.re.ReplaceAllString(text, " ")
+		}
+	}
+	slices.Reverse(found)
+	return found
+}
+
+// applicationMode combines whether online and offline applications are
+// mentioned.
+func applicationMode(online, offline bool) models.ApplicationMode {
+	switch {
+	case online && offline:
+		return models.ApplicationMode_APPLICATION_MODE_BOTH
+	case online:
+		return models.ApplicationMode_APPLICATION_MODE_ONLINE
+	case offline:
+		return models.ApplicationMode_APPLICATION_MODE_OFFLINE
+	}
+	return models.ApplicationMode_APPLICATION_MODE_UNSPECIFIED
+}
+
+// applyAdvertFacts copies facts onto job without overwriting the last date
+// taken from the listing page; a relative deadline counts from the posting
+// date, with low confidence. An application mode already seen on the listing (an "Apply online"
+// link) is combined with the advert's.
+func applyAdvertFacts(job *models.JobPosting, facts AdvertFacts) {
+	job.Vacancies = int32(facts.Vacancies)
+	job.PayLevel = facts.PayLevel
+	job.PayScale = facts.PayScale
+	job.AgeLimit = facts.AgeLimit
+	job.MinAge = int32(facts.MinAge)
+	job.MaxAge = int32(facts.MaxAge)
+	job.Qualifications = facts.Qualifications
+	if job.LastDate == "" {
+		job.LastDate = facts.ClosingDate
+		if job.LastDate != "" {
+			job.LastDateConfidence = facts.ClosingDateConf.String()
+		}
+		if posted, err := time.Parse("2006-01-02", job.Date); err == nil && job.LastDate == "" && facts.ClosingDays > 0 {
+			job.LastDate = posted.AddDate(0, 0, facts.ClosingDays).Format("2006-01-02")
+			job.LastDateConfidence = dates.Low.String()
+		}
+		setTimestamps(job)
+	}
+
+	const (
+		online  = models.ApplicationMode_APPLICATION_MODE_ONLINE
+		offline = models.ApplicationMode_APPLICATION_MODE_OFFLINE
+		both    = models.ApplicationMode_APPLICATION_MODE_BOTH
+	)
+	listed, stated := job.ApplicationMode, facts.ApplicationMode
+	job.ApplicationMode = applicationMode(
+		listed == online || listed == both || stated == online || stated == both,
+		listed == offline || listed == both || stated == offline || stated == both,
+	)
+}
+
+// truncateUTF8 shortens s to at most n bytes without splitting a character.
+func truncateUTF8(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
@@ -0,0 +1,232 @@
+package scraper
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/entreya/job-aggregation/pkg/dates"
+	"github.com/entreya/job-aggregation/pkg/models"
+)
+
+// buildTestPDF renders lines into a minimal single-page PDF, one text block
+// per line, with a correct cross-reference table.
+func buildTestPDF(lines ...string) []byte {
+	var content strings.Builder
+	for i, line := range lines {
+		escaped := strings.NewReplacer(`\`, `\\`, "(", `\(`, ")", `\)`).Replace(line)
+		fmt.Fprintf(&content, "BT /F1 11 Tf 50 %d Td (%s) Tj ET\n", 780-i*16, escaped)
+	}
+
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 595 842] /Resources << /Font << /F1 4 0 R >> >> /Contents 5 0 R >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.String()),
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return buf.Bytes()
+}
+
+var sampleAdvertLines = []string{
+	"National Informatics Centre",
+	"Advt. No. NIC/STA/02/2026",
+	"Recruitment of Scientific/Technical Assistant-A",
+	"Total No. of Posts: 45 (UR-19, OBC-12, SC-7, ST-3, EWS-4)",
+	"Pay: Level 6 in the Pay Matrix (Rs. 35,400 - 1,12,400)",
+	"Age Limit: 18 to 30 years as on the closing date",
+	"Last date for receipt of applications: 02/03/2026",
+}
+
+func TestExtractPDFText(t *testing.T) {
+	text, err := ExtractPDFText(buildTestPDF(sampleAdvertLines...))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, line := range sampleAdvertLines {
+		if !strings.Contains(text, line) {
+			t.Errorf("expected extracted text to contain %q\nfull text:\n%s", line, text)
+		}
+	}
+}
+
+func TestExtractPDFText_RejectsGarbage(t *testing.T) {
+	if _, err := ExtractPDFText([]byte("%PDF-1.4\nnot really a pdf")); err == nil {
+		t.Error("expected error for malformed PDF")
+	}
+	if _, err := ExtractPDFText(buildTestPDF()); err == nil {
+		t.Error("expected error for PDF without text")
+	}
+}
+
+func TestExtractAdvertFacts(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want AdvertFacts
+	}{
+		{
+			name: "sample advert",
+			text: strings.Join(sampleAdvertLines, "\n"),
+			want: AdvertFacts{Vacancies: 45, PayLevel: "Level 6", PayScale: "Rs. 35,400-1,12,400",
+				AgeLimit: "18-30 years", MinAge: 18, MaxAge: 30, ClosingDate: "2026-03-02", ClosingDateConf: dates.High},
+		},
+		{
+			name: "count before noun and upper age bound",
+			text: "Applications are invited for 12 posts of Section Officer (Pay Matrix Level-10A). " +
+				"The age should not exceed 56 years. Applications must reach on or before 15.04.2026.",
+			want: AdvertFacts{Vacancies: 12, PayLevel: "Level 10A", AgeLimit: "up to 56 years", MaxAge: 56,
+				ClosingDate: "2026-04-15", ClosingDateConf: dates.High},
+		},
+		{
+			name: "qualifications and online application",
+			text: "Essential Qualification: B.Tech or Diploma in Electronics; M.Tech desirable. " +
+				"Pay Scale ₹25500 to ₹81100. Age: 21 - 28 years. Candidates must apply online at ssc.gov.in.",
+			want: AdvertFacts{PayScale: "Rs. 25500-81100", AgeLimit: "21-28 years", MinAge: 21, MaxAge: 28,
+				Qualifications:  []string{"Diploma", "B.E./B.Tech", "M.E./M.Tech"},
+				ApplicationMode: models.ApplicationMode_APPLICATION_MODE_ONLINE},
+		},
+		{
+			name: "post graduate is not also graduate, applications by post",
+			text: "Candidates holding a Post Graduate degree should send their applications by speed post.",
+			want: AdvertFacts{Qualifications: []string{"Post Graduate"},
+				ApplicationMode: models.ApplicationMode_APPLICATION_MODE_OFFLINE},
+		},
+		{
+			name: "closing date with month name",
+			text: "Last date for receipt of applications: 15th April, 2026.",
+			want: AdvertFacts{ClosingDate: "2026-04-15", ClosingDateConf: dates.High},
+		},
+		{
+			name: "deadline relative to publication",
+			text: "Applications must reach within 30 days of publication of this advertisement in Employment News.",
+			want: AdvertFacts{ClosingDays: 30},
+		},
+		{
+			name: "nothing stated",
+			text: "Corrigendum: the venue of the interview has changed.",
+			want: AdvertFacts{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExtractAdvertFacts(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ExtractAdvertFacts() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyAdvertFacts_RelativeDeadline(t *testing.T) {
+	job := &models.JobPosting{Date: "2026-03-01"}
+	applyAdvertFacts(job, AdvertFacts{ClosingDays: 30})
+	if job.LastDate != "2026-03-31" || job.GetClosingAt().AsTime().Format("2006-01-02") != "2026-03-31" {
+		t.Errorf("expected deadline 30 days after posting, got %q", job.LastDate)
+	}
+	if job.LastDateConfidence != "low" {
+		t.Errorf("expected a counted deadline to have low confidence, got %q", job.LastDateConfidence)
+	}
+
+	// The listing's own last date wins.
+	job = &models.JobPosting{Date: "2026-03-01", LastDate: "2026-03-20"}
+	applyAdvertFacts(job, AdvertFacts{ClosingDays: 30})
+	if job.LastDate != "2026-03-20" {
+		t.Errorf("listing last date overwritten: %q", job.LastDate)
+	}
+}
+
+func TestIsAdvertURL(t *testing.T) {
+	tests := map[string]bool{
+		"https://recruitment.nic.in/AppAdv.pdf":       true,
+		"https://recruitment.nic.in/STA_2026.PDF?v=2": true,
+		"https://recruitment.nic.in/index_new.php":    false,
+		"https://recruitment.nic.in/pdf/notice.html":  false,
+		"https://recruitment.nic.in/view.php?f=a.pdf": false,
+	}
+	for u, want := range tests {
+		if got := IsAdvertURL(u); got != want {
+			t.Errorf("IsAdvertURL(%q) = %v, want %v", u, got, want)
+		}
+	}
+}
+
+func TestFetchPDF_RejectsHTML(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("<html><body>File not found</body></html>"))
+	}))
+	defer srv.Close()
+
+	_, err := FetchPDF(context.Background(), srv.URL+"/missing.pdf", "", 5*time.Second)
+	if err == nil || !strings.Contains(err.Error(), "not a PDF") {
+		t.Errorf("expected not-a-PDF error, got: %v", err)
+	}
+}
+
+func TestFetchAdverts_EnrichesJobs(t *testing.T) {
+	advert := buildTestPDF(sampleAdvertLines...)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/broken.pdf" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/pdf")
+		_, _ = w.Write(advert)
+	}))
+	defer srv.Close()
+
+	jobs := []*models.JobPosting{
+		{Id: "sta", Url: srv.URL + "/STA_2026.pdf"},
+		{Id: "kept", Url: srv.URL + "/AppAdv.pdf", LastDate: "2026-03-15"},
+		{Id: "page", Url: srv.URL + "/details.php"},
+		{Id: "broken", Url: srv.URL + "/broken.pdf"},
+	}
+
+	s := NewScraper(Config{RetryCfg: RetryConfig{MaxRetries: 0}, Logger: testParserLogger(), Timeout: 5 * time.Second})
+	adverts := s.FetchAdverts(context.Background(), jobs, 2)
+
+	if len(adverts) != 2 {
+		t.Fatalf("expected 2 adverts, got %d", len(adverts))
+	}
+	if _, ok := adverts["page"]; ok {
+		t.Error("non-PDF link should not be fetched")
+	}
+	if !strings.Contains(adverts["sta"].Text, "Scientific/Technical Assistant-A") {
+		t.Errorf("expected advert text to be kept, got %q", adverts["sta"].Text)
+	}
+
+	sta := jobs[0]
+	if sta.Vacancies != 45 || sta.PayLevel != "Level 6" || sta.AgeLimit != "18-30 years" || sta.LastDate != "2026-03-02" ||
+		sta.MaxAge != 30 || sta.PayScale == "" {
+		t.Errorf("job not enriched: %+v", sta)
+	}
+	if got := sta.GetClosingAt().AsTime().Format("2006-01-02"); got != "2026-03-02" {
+		t.Errorf("closing_at not set from the advert's last date, got %s", got)
+	}
+	if jobs[1].LastDate != "2026-03-15" {
+		t.Errorf("listing last date should win over the advert, got %q", jobs[1].LastDate)
+	}
+	if jobs[3].Vacancies != 0 {
+		t.Errorf("failed download should leave the job unchanged, got %+v", jobs[3])
+	}
+}
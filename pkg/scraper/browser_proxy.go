@@ -0,0 +1,148 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/fetch"
+	"github.com/chromedp/chromedp"
+	"github.com/entreya/job-aggregation/pkg/proxy"
+)
+
+// errProxyAuthRejected is returned when the proxy challenges again after
+// Chrome answered with the configured credentials. Its message matches a
+// hard proxy pattern, so the attempt is not retried against the same proxy.
+var errProxyAuthRejected = errors.New("proxy authentication required: credentials rejected")
+
+// browserProxy is how one chromedp attempt reaches its proxy. Chrome drops
+// credentials given in --proxy-server, so they are handled here instead:
+//   - HTTP and HTTPS proxies get the bare server address, and the username
+//     and password are answered to the proxy's 407 challenge through the
+//     DevTools Fetch domain.
+//   - SOCKS5 proxies with credentials, which Chrome cannot authenticate to,
+//     are reached through a local proxy.Forwarder.
+type browserProxy struct {
+	server    string // For chromedp.ProxyServer ("" = direct)
+	username  string // Answered to proxy challenges when set
+	password  string
+	forwarder *proxy.Forwarder
+
+	mu       sync.Mutex
+	answered map[fetch.RequestID]bool // Requests already given credentials
+	rejected bool                     // The proxy challenged a second time
+}
+
+// newBrowserProxy prepares proxyURL ("" = direct) for Chrome. Close the
+// result when the attempt ends.
+func newBrowserProxy(proxyURL string) (*browserProxy, error) {
+	bp := &browserProxy{answered: make(map[fetch.RequestID]bool)}
+	if proxyURL == "" {
+		return bp, nil
+	}
+	server, username, password, err := proxy.SplitCredentials(proxyURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if !proxy.IsSOCKS(proxyURL) {
+		bp.server, bp.username, bp.password = server, username, password
+		return bp, nil
+	}
+	if username == "" {
+		// Chrome speaks unauthenticated SOCKS5 itself and resolves hostnames
+		// through the proxy, so socks5h needs no special treatment.
+		bp.server = strings.Replace(server, "socks5h://", "socks5://", 1)
+		return bp, nil
+	}
+	f, err := proxy.NewForwarder(proxyURL)
+	if err != nil {
+		return nil, err
+	}
+	bp.forwarder = f
+	bp.server = f.URL()
+	return bp, nil
+}
+
+// Close stops the forwarder, if any.
+func (bp *browserProxy) Close() {
+	if bp.forwarder != nil {
+		_ = bp.forwarder.Close()
+	}
+}
+
+// needsAuth reports whether Chrome must answer proxy challenges.
+func (bp *browserProxy) needsAuth() bool {
+	return bp.username != ""
+}
+
+// listen answers proxy authentication challenges in the browser tab of ctx.
+// Call it before the first action runs; run enableAuth() as the first action.
+func (bp *browserProxy) listen(ctx context.Context) {
+	if !bp.needsAuth() {
+		return
+	}
+	chromedp.ListenTarget(ctx, func(ev any) {
+		var action chromedp.Action
+		switch ev := ev.(type) {
+		case *fetch.EventAuthRequired:
+			action = fetch.ContinueWithAuth(ev.RequestID, bp.authResponse(ev))
+		case *fetch.EventRequestPaused:
+			action = fetch.ContinueRequest(ev.RequestID)
+		default:
+			return
+		}
+		// Listeners must not block the event loop, so reply asynchronously.
+		go func() {
+			c := chromedp.FromContext(ctx)
+			if c == nil || c.Target == nil {
+				return
+			}
+			_ = action.Do(cdp.WithExecutor(ctx, c.Target))
+		}()
+	})
+}
+
+// enableAuth turns on the Fetch domain so Chrome pauses on authentication
+// challenges; a no-op when the proxy needs no credentials.
+func (bp *browserProxy) enableAuth() chromedp.Action {
+	if !bp.needsAuth() {
+		return chromedp.ActionFunc(func(context.Context) error { return nil })
+	}
+	return fetch.Enable().WithHandleAuthRequests(true)
+}
+
+// authResponse decides how to answer ev. A proxy challenge is answered with
+// the credentials once per request; a second challenge for the same request
+// means they were refused, so it is cancelled and recorded. Challenges from
+// the target site get Chrome's default handling.
+func (bp *browserProxy) authResponse(ev *fetch.EventAuthRequired) *fetch.AuthChallengeResponse {
+	if ev.AuthChallenge == nil || ev.AuthChallenge.Source != fetch.AuthChallengeSourceProxy {
+		return &fetch.AuthChallengeResponse{Response: fetch.AuthChallengeResponseResponseDefault}
+	}
+
+	bp.mu.Lock()
+	defer bp.mu.Unlock()
+	if bp.answered[ev.RequestID] {
+		bp.rejected = true
+		return &fetch.AuthChallengeResponse{Response: fetch.AuthChallengeResponseResponseCancelAuth}
+	}
+	bp.answered[ev.RequestID] = true
+	return &fetch.AuthChallengeResponse{
+		Response: fetch.AuthChallengeResponseResponseProvideCredentials,
+		Username: bp.username,
+		Password: bp.password,
+	}
+}
+
+// err returns errProxyAuthRejected once the proxy has refused the credentials.
+func (bp *browserProxy) err() error {
+	bp.mu.Lock()
+	defer bp.mu.Unlock()
+	if bp.rejected {
+		return errProxyAuthRejected
+	}
+	return nil
+}
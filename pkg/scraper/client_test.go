@@ -2,11 +2,15 @@ package scraper
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
+
+	"github.com/entreya/job-aggregation/pkg/proxy/proxytest"
 )
 
 // TestFetchHTML_Success verifies that FetchHTML correctly fetches HTML from a
@@ -113,3 +117,76 @@ func TestFetchHTML_InvalidProxyURL(t *testing.T) {
 		t.Errorf("expected 'invalid proxy URL' in error, got: %v", err)
 	}
 }
+
+// TestFetchHTML_AuthenticatedProxies verifies that credentials in the proxy
+// URL are sent to HTTP proxies and SOCKS5 proxies, and that a wrong password
+// fails as a proxy failure without reaching the site.
+func TestFetchHTML_AuthenticatedProxies(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		hits.Add(1)
+		_, _ = w.Write([]byte("<html>ok</html>"))
+	}))
+	defer srv.Close()
+
+	for _, p := range []*proxytest.Server{
+		proxytest.NewHTTP("scraper", "s3cret"),
+		proxytest.NewSOCKS5("scraper", "s3cret"),
+	} {
+		defer p.Close()
+
+		got, err := FetchHTML(context.Background(), srv.URL, p.URL("scraper", "s3cret"), 5*time.Second)
+		if err != nil || got != "<html>ok</html>" {
+			t.Errorf("%s proxy: got %q, %v; want the page", p.Scheme, got, err)
+		}
+		if p.Requests() != 1 {
+			t.Errorf("%s proxy relayed %d requests, want 1", p.Scheme, p.Requests())
+		}
+
+		before := hits.Load()
+		_, err = FetchHTML(context.Background(), srv.URL, p.URL("scraper", "wrong"), 5*time.Second)
+		if !isProxyFailure(err) || isRetryable(err) {
+			t.Errorf("%s proxy, wrong password: got %v, want a non-retryable proxy failure", p.Scheme, err)
+		}
+		if hits.Load() != before || p.Rejected() != 1 {
+			t.Errorf("%s proxy, wrong password: site hit %d times, proxy rejected %d; want 0 and 1",
+				p.Scheme, hits.Load()-before, p.Rejected())
+		}
+	}
+}
+
+// TestFetchHTML_SOCKS5H verifies that socks5h proxies, which resolve the
+// target hostname at the proxy, are supported.
+func TestFetchHTML_SOCKS5H(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("<html>ok</html>"))
+	}))
+	defer srv.Close()
+	p := proxytest.NewSOCKS5("", "")
+	defer p.Close()
+
+	target := strings.Replace(srv.URL, "127.0.0.1", "localhost", 1)
+	proxyURL := strings.Replace(p.URL("", ""), "socks5://", "socks5h://", 1)
+	if _, err := FetchHTML(context.Background(), target, proxyURL, 5*time.Second); err != nil {
+		t.Fatalf("FetchHTML via socks5h: %v", err)
+	}
+	if p.Requests() != 1 {
+		t.Errorf("proxy relayed %d connections, want 1", p.Requests())
+	}
+}
+
+// TestHumanDelayContext_Cancellation verifies that a cancelled context cuts
+// the human delay short and surfaces the context error.
+func TestHumanDelayContext_Cancellation(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := HumanDelayContext(ctx, 2, 3)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed >= time.Second {
+		t.Errorf("expected delay to stop early, took %v", elapsed)
+	}
+}
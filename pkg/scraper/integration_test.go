@@ -66,9 +66,9 @@ func TestIntegration_FullPipeline(t *testing.T) {
 	}
 
 	// Step 2: Parse HTML
-	jobs, err := ParseJobs(htmlContent, logger)
+	jobs, err := NICSource("").Parse(htmlContent, logger)
 	if err != nil {
-		t.Fatalf("ParseJobs failed: %v", err)
+		t.Fatalf("Parse failed: %v", err)
 	}
 
 	if len(jobs) == 0 {
@@ -0,0 +1,284 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/entreya/job-aggregation/pkg/models"
+)
+
+// OrchestratorConfig holds initialization parameters for the Orchestrator.
+type OrchestratorConfig struct {
+	Scraper      *Scraper // Shared scraper (and therefore shared proxy rotator)
+	Workers      int      // Maximum pages fetched concurrently across all sources
+	PerHostLimit int      // Maximum pages fetched concurrently from a single host
+	Logger       *slog.Logger
+}
+
+// Orchestrator runs several sources concurrently through a bounded worker
+// pool. Each listing page is an independent task, queued per host, so a
+// slow, failing or busy source never blocks the others.
+type Orchestrator struct {
+	scraper      *Scraper
+	workers      int
+	perHostLimit int
+	logger       *slog.Logger
+}
+
+// SourceResult is the outcome of scraping one source.
+// Jobs holds whatever was scraped successfully, even when Err is set for
+// some of the source's pages.
+type SourceResult struct {
+	Source      string
+	Jobs        *models.JobList
+	Err         error
+	PagesOK     int
+	PagesFailed int
+	Duration    time.Duration
+}
+
+// OK reports whether at least one page of the source was scraped.
+func (r SourceResult) OK() bool {
+	return r.PagesOK > 0
+}
+
+// pageTask is a single listing page queued for a worker.
+type pageTask struct {
+	sourceIdx int
+	pageIdx   int
+	src       Source
+	pageURL   string
+	host      string
+}
+
+// NewOrchestrator creates an Orchestrator with all dependencies injected.
+// Workers defaults to 4 and PerHostLimit to 1 when unset.
+func NewOrchestrator(cfg OrchestratorConfig) *Orchestrator {
+	if cfg.Workers <= 0 {
+		cfg.Workers = 4
+	}
+	if cfg.PerHostLimit <= 0 {
+		cfg.PerHostLimit = 1
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = slog.Default()
+	}
+
+	return &Orchestrator{
+		scraper:      cfg.Scraper,
+		workers:      cfg.Workers,
+		perHostLimit: cfg.PerHostLimit,
+		logger:       cfg.Logger,
+	}
+}
+
+// Run scrapes every page of every source and returns one result per source,
+// in the same order as sources. Failures are reported per source; Run itself
+// never fails. Once ctx is cancelled, in-flight pages are aborted and pages
+// not yet started are reported as failed with ctx.Err().
+func (o *Orchestrator) Run(ctx context.Context, sources []Source) []SourceResult {
+	results := make([]SourceResult, len(sources))
+	pageJobs := make([][][]*models.JobPosting, len(sources))
+	pageErrs := make([][]error, len(sources))
+	started := make([]time.Time, len(sources))
+	remaining := make([]int, len(sources))
+
+	tasks := make([]pageTask, 0)
+	for i, src := range sources {
+		pages := src.URLs()
+		results[i].Source = src.Name()
+		pageJobs[i] = make([][]*models.JobPosting, len(pages))
+		pageErrs[i] = make([]error, len(pages))
+		remaining[i] = len(pages)
+		for j, pageURL := range pages {
+			tasks = append(tasks, pageTask{sourceIdx: i, pageIdx: j, src: src, pageURL: pageURL, host: hostOf(pageURL)})
+		}
+	}
+
+	o.logger.Info("orchestrator starting",
+		slog.Int("sources", len(sources)),
+		slog.Int("pages", len(tasks)),
+		slog.Int("workers", o.workers),
+		slog.Int("per_host_limit", o.perHostLimit),
+	)
+
+	sched := newHostScheduler(o.perHostLimit, tasks)
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for w := 0; w < o.workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				task, ok := sched.next()
+				if !ok {
+					return
+				}
+
+				mu.Lock()
+				if started[task.sourceIdx].IsZero() {
+					started[task.sourceIdx] = time.Now()
+				}
+				mu.Unlock()
+
+				var jobs []*models.JobPosting
+				err := ctx.Err()
+				if err == nil {
+					jobs, err = o.scraper.ScrapePage(ctx, task.src, task.pageURL)
+				}
+				sched.done(task.host)
+
+				mu.Lock()
+				pageJobs[task.sourceIdx][task.pageIdx] = jobs
+				pageErrs[task.sourceIdx][task.pageIdx] = err
+				remaining[task.sourceIdx]--
+				if remaining[task.sourceIdx] == 0 {
+					results[task.sourceIdx].Duration = time.Since(started[task.sourceIdx])
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	for i := range sources {
+		results[i] = o.collect(results[i], sources[i], pageJobs[i], pageErrs[i])
+	}
+
+	return results
+}
+
+// collect folds per-page outcomes into a SourceResult, preserving page order,
+// and stamps every job with the source name and its date timestamps.
+func (o *Orchestrator) collect(res SourceResult, src Source, pageJobs [][]*models.JobPosting, pageErrs []error) SourceResult {
+	jobs := make([]*models.JobPosting, 0, 64)
+	errs := make([]error, 0)
+
+	for j, pageURL := range src.URLs() {
+		if err := pageErrs[j]; err != nil {
+			res.PagesFailed++
+			errs = append(errs, fmt.Errorf("%s: %w", pageURL, err))
+			continue
+		}
+		res.PagesOK++
+		jobs = append(jobs, pageJobs[j]...)
+	}
+	for _, job := range jobs {
+		job.Source = src.Name()
+		setTimestamps(job)
+		normalizeEntities(job)
+	}
+
+	res.Jobs = &models.JobList{
+		LastUpdated: time.Now().Unix(),
+		Jobs:        jobs,
+	}
+	if len(errs) > 0 {
+		res.Err = fmt.Errorf("source %s: %w", src.Name(), errors.Join(errs...))
+	}
+
+	if res.OK() {
+		o.logger.Info("source scraped",
+			slog.String("source", res.Source),
+			slog.Int("pages_ok", res.PagesOK),
+			slog.Int("pages_failed", res.PagesFailed),
+			slog.Int("jobs_found", len(jobs)),
+			slog.Duration("duration", res.Duration),
+		)
+	} else {
+		o.logger.Warn("source failed",
+			slog.String("source", res.Source),
+			slog.Int("pages_failed", res.PagesFailed),
+			slog.Duration("duration", res.Duration),
+		)
+	}
+
+	return res
+}
+
+// MergeResults merges the jobs of every successful source into one list.
+func MergeResults(results []SourceResult) *models.JobList {
+	lists := make([]*models.JobList, 0, len(results))
+	for _, r := range results {
+		if r.OK() {
+			lists = append(lists, r.Jobs)
+		}
+	}
+	return MergeJobLists(lists...)
+}
+
+// hostScheduler hands tasks to workers so that no host has more than limit
+// in flight. Each host has its own queue: a worker only takes a task whose
+// host has a free slot, so it never sits on a busy host while pages of other
+// hosts wait.
+type hostScheduler struct {
+	limit   int
+	mu      sync.Mutex
+	cond    *sync.Cond
+	hosts   []string // In order of their first task
+	queues  map[string][]pageTask
+	active  map[string]int
+	pending int // Tasks not yet handed out
+}
+
+func newHostScheduler(limit int, tasks []pageTask) *hostScheduler {
+	s := &hostScheduler{
+		limit:   limit,
+		queues:  make(map[string][]pageTask),
+		active:  make(map[string]int),
+		pending: len(tasks),
+	}
+	s.cond = sync.NewCond(&s.mu)
+	for _, t := range tasks {
+		if _, ok := s.queues[t.host]; !ok {
+			s.hosts = append(s.hosts, t.host)
+		}
+		s.queues[t.host] = append(s.queues[t.host], t)
+	}
+	return s
+}
+
+// next returns a task whose host has a free slot, waiting while every host
+// with queued tasks is busy. It returns false once every task has been
+// handed out. Each task must be followed by done.
+func (s *hostScheduler) next() (pageTask, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for s.pending > 0 {
+		for _, host := range s.hosts {
+			if q := s.queues[host]; len(q) > 0 && s.active[host] < s.limit {
+				s.queues[host] = q[1:]
+				s.active[host]++
+				s.pending--
+				return q[0], true
+			}
+		}
+		s.cond.Wait()
+	}
+	return pageTask{}, false
+}
+
+// done frees the slot of a task's host taken by next.
+func (s *hostScheduler) done(host string) {
+	s.mu.Lock()
+	s.active[host]--
+	s.mu.Unlock()
+	s.cond.Broadcast()
+}
+
+// hostOf returns the host (including port) of rawURL, or rawURL itself if
+// it cannot be parsed, so malformed URLs still get a limiter bucket.
+func hostOf(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Host == "" {
+		return rawURL
+	}
+	return parsed.Host
+}
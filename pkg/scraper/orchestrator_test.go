@@ -0,0 +1,207 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func testOrchestratorScraper() *Scraper {
+	return NewScraper(Config{
+		RetryCfg: RetryConfig{MaxRetries: 1, BaseDelay: time.Millisecond},
+		Logger:   testParserLogger(),
+		Timeout:  5 * time.Second,
+	})
+}
+
+// newListingServer serves a small listing page and records the peak number
+// of concurrent in-flight requests.
+func newListingServer(t *testing.T, peak *int32, delay time.Duration) *httptest.Server {
+	t.Helper()
+	var inFlight int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			old := atomic.LoadInt32(peak)
+			if n <= old || atomic.CompareAndSwapInt32(peak, old, n) {
+				break
+			}
+		}
+		time.Sleep(delay)
+		fmt.Fprintf(w, `<html><body><a href="%s.pdf">Post %s</a></body></html>`, r.URL.Path, r.URL.Path)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func httpSource(name, base string, pages ...string) *SiteSource {
+	return &SiteSource{
+		SourceName: name,
+		Pages:      pages,
+		Base:       base,
+		Fetch:      FetchHTTP,
+		Department: name,
+	}
+}
+
+func TestOrchestrator_IsolatesFailingSource(t *testing.T) {
+	var peak int32
+	good := newListingServer(t, &peak, 0)
+	bad := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer bad.Close()
+
+	sources := []Source{
+		httpSource("good", good.URL, good.URL+"/a", good.URL+"/b"),
+		httpSource("bad", bad.URL, bad.URL+"/a"),
+	}
+
+	o := NewOrchestrator(OrchestratorConfig{Scraper: testOrchestratorScraper(), Workers: 2, Logger: testParserLogger()})
+	results := o.Run(context.Background(), sources)
+
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+	if results[0].Source != "good" || !results[0].OK() || results[0].Err != nil {
+		t.Errorf("expected good source to succeed, got %+v", results[0])
+	}
+	if len(results[0].Jobs.Jobs) != 2 {
+		t.Errorf("expected 2 jobs from good source, got %d", len(results[0].Jobs.Jobs))
+	}
+	for _, j := range results[0].Jobs.Jobs {
+		if j.Source != "good" {
+			t.Errorf("expected job stamped with its source, got %q", j.Source)
+		}
+	}
+	if results[1].Source != "bad" || results[1].OK() || results[1].Err == nil {
+		t.Errorf("expected bad source to fail, got %+v", results[1])
+	}
+
+	merged := MergeResults(results)
+	if len(merged.Jobs) != 2 {
+		t.Errorf("expected 2 merged jobs, got %d", len(merged.Jobs))
+	}
+}
+
+func TestOrchestrator_PreservesPageOrder(t *testing.T) {
+	var peak int32
+	srv := newListingServer(t, &peak, 5*time.Millisecond)
+
+	pages := []string{srv.URL + "/1", srv.URL + "/2", srv.URL + "/3", srv.URL + "/4"}
+	o := NewOrchestrator(OrchestratorConfig{Scraper: testOrchestratorScraper(), Workers: 4, PerHostLimit: 4, Logger: testParserLogger()})
+	results := o.Run(context.Background(), []Source{httpSource("ordered", srv.URL, pages...)})
+
+	jobs := results[0].Jobs.Jobs
+	if len(jobs) != 4 {
+		t.Fatalf("expected 4 jobs, got %d", len(jobs))
+	}
+	for i, j := range jobs {
+		want := fmt.Sprintf("Post /%d", i+1)
+		if j.Title != want {
+			t.Errorf("job %d: expected %q, got %q", i, want, j.Title)
+		}
+	}
+}
+
+func TestOrchestrator_RespectsPerHostLimit(t *testing.T) {
+	var peak int32
+	srv := newListingServer(t, &peak, 30*time.Millisecond)
+
+	pages := make([]string, 0, 6)
+	for i := 0; i < 6; i++ {
+		pages = append(pages, fmt.Sprintf("%s/%d", srv.URL, i))
+	}
+
+	o := NewOrchestrator(OrchestratorConfig{Scraper: testOrchestratorScraper(), Workers: 6, PerHostLimit: 2, Logger: testParserLogger()})
+	results := o.Run(context.Background(), []Source{httpSource("limited", srv.URL, pages...)})
+
+	if results[0].PagesOK != 6 {
+		t.Fatalf("expected 6 pages scraped, got %d", results[0].PagesOK)
+	}
+	if got := atomic.LoadInt32(&peak); got > 2 {
+		t.Errorf("expected at most 2 concurrent requests to one host, saw %d", got)
+	}
+}
+
+func TestOrchestrator_RunsHostsConcurrently(t *testing.T) {
+	var peakA, peakB int32
+	a := newListingServer(t, &peakA, 100*time.Millisecond)
+	b := newListingServer(t, &peakB, 100*time.Millisecond)
+
+	o := NewOrchestrator(OrchestratorConfig{Scraper: testOrchestratorScraper(), Workers: 2, PerHostLimit: 1, Logger: testParserLogger()})
+
+	start := time.Now()
+	results := o.Run(context.Background(), []Source{
+		httpSource("a", a.URL, a.URL+"/x"),
+		httpSource("b", b.URL, b.URL+"/y"),
+	})
+	elapsed := time.Since(start)
+
+	if !results[0].OK() || !results[1].OK() {
+		t.Fatalf("expected both sources to succeed: %+v", results)
+	}
+	// Serial execution would take at least 200ms.
+	if elapsed >= 190*time.Millisecond {
+		t.Errorf("expected sources on different hosts to run concurrently, took %v", elapsed)
+	}
+}
+
+func TestOrchestrator_BusyHostDoesNotHoldWorkers(t *testing.T) {
+	var peak int32
+	busy := newListingServer(t, &peak, 100*time.Millisecond)
+	var otherAt atomic.Int64
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		otherAt.Store(time.Now().UnixNano())
+		fmt.Fprint(w, `<html><body><a href="/y.pdf">Post y</a></body></html>`)
+	}))
+	defer other.Close()
+
+	pages := []string{busy.URL + "/1", busy.URL + "/2", busy.URL + "/3", busy.URL + "/4"}
+	o := NewOrchestrator(OrchestratorConfig{Scraper: testOrchestratorScraper(), Workers: 2, PerHostLimit: 1, Logger: testParserLogger()})
+
+	start := time.Now()
+	results := o.Run(context.Background(), []Source{
+		httpSource("busy", busy.URL, pages...),
+		httpSource("other", other.URL, other.URL+"/y"),
+	})
+
+	if !results[0].OK() || !results[1].OK() {
+		t.Fatalf("expected both sources to succeed: %+v", results)
+	}
+	// Queued behind the busy host's pages, it would wait at least 300ms.
+	if waited := time.Duration(otherAt.Load() - start.UnixNano()); waited >= 90*time.Millisecond {
+		t.Errorf("expected the other host's page to be fetched while the busy host was served, waited %v", waited)
+	}
+}
+
+func TestOrchestrator_StopsOnCancellation(t *testing.T) {
+	var peak int32
+	srv := newListingServer(t, &peak, 200*time.Millisecond)
+
+	pages := []string{srv.URL + "/1", srv.URL + "/2", srv.URL + "/3"}
+	o := NewOrchestrator(OrchestratorConfig{Scraper: testOrchestratorScraper(), Workers: 1, Logger: testParserLogger()})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	results := o.Run(ctx, []Source{httpSource("slow", srv.URL, pages...)})
+	elapsed := time.Since(start)
+
+	if results[0].OK() {
+		t.Errorf("expected no page to complete after cancellation, got %d", results[0].PagesOK)
+	}
+	if !errors.Is(results[0].Err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded in source error, got %v", results[0].Err)
+	}
+	if elapsed >= 500*time.Millisecond {
+		t.Errorf("expected run to stop promptly after cancellation, took %v", elapsed)
+	}
+}
@@ -4,9 +4,13 @@ import (
 	"encoding/csv"
 	"encoding/json"
 	"fmt"
+	"io"
 	"log/slog"
 	"os"
 	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/entreya/job-aggregation/pkg/models"
@@ -28,13 +32,115 @@ func DefaultOutputConfig() OutputConfig {
 
 // OutputRecord wraps a JobPosting with a scrape timestamp for output.
 type OutputRecord struct {
-	ID         string `json:"id"`
-	Title      string `json:"title"`
-	Department string `json:"department"`
-	Location   string `json:"location"`
-	URL        string `json:"url"`
-	Date       string `json:"date"`
-	ScrapedAt  string `json:"scraped_at"`
+	ID              string             `json:"id"`
+	Title           string             `json:"title"`
+	Department      string             `json:"department"`
+	Location        string             `json:"location"`
+	URL             string             `json:"url"`
+	Date            string             `json:"date"`
+	LastDate        string             `json:"last_date,omitempty"`
+	AdvertisementNo string             `json:"advertisement_no,omitempty"`
+	Vacancies       int32              `json:"vacancies,omitempty"`
+	Qualifications  []string           `json:"qualifications,omitempty"`
+	PayLevel        string             `json:"pay_level,omitempty"`
+	PayScale        string             `json:"pay_scale,omitempty"`
+	MinAge          int32              `json:"min_age,omitempty"`
+	MaxAge          int32              `json:"max_age,omitempty"`
+	ApplicationMode string             `json:"application_mode,omitempty"` // online, offline or both
+	Source          string             `json:"source,omitempty"`
+	Attachments     []OutputAttachment `json:"attachments,omitempty"`
+	ScrapedAt       string             `json:"scraped_at"`
+}
+
+// OutputAttachment is a document linked from a posting.
+type OutputAttachment struct {
+	Title string `json:"title"`
+	URL   string `json:"url"`
+}
+
+// csvHeader is the column order of data.csv. List columns join their
+// values with "; "; attachments list their URLs.
+var csvHeader = []string{
+	"id", "title", "department", "location", "url", "date", "last_date",
+	"advertisement_no", "vacancies", "qualifications", "pay_level", "pay_scale",
+	"min_age", "max_age", "application_mode", "source", "attachments", "scraped_at",
+}
+
+// outputRecord converts a posting to its output form.
+func outputRecord(j *models.JobPosting, scrapedAt string) OutputRecord {
+	r := OutputRecord{
+		ID:              j.GetId(),
+		Title:           j.GetTitle(),
+		Department:      j.GetDepartment(),
+		Location:        j.GetLocation(),
+		URL:             j.GetUrl(),
+		Date:            j.GetDate(),
+		LastDate:        j.GetLastDate(),
+		AdvertisementNo: j.GetAdvertisementNo(),
+		Vacancies:       j.GetVacancies(),
+		Qualifications:  j.GetQualifications(),
+		PayLevel:        j.GetPayLevel(),
+		PayScale:        j.GetPayScale(),
+		MinAge:          j.GetMinAge(),
+		MaxAge:          j.GetMaxAge(),
+		ApplicationMode: applicationModeName(j.GetApplicationMode()),
+		Source:          j.GetSource(),
+		ScrapedAt:       scrapedAt,
+	}
+	for _, a := range j.GetAttachments() {
+		r.Attachments = append(r.Attachments, OutputAttachment{Title: a.GetTitle(), URL: a.GetUrl()})
+	}
+	return r
+}
+
+// applicationModeName renders an application mode as online, offline or
+// both, or "" when unknown.
+func applicationModeName(mode models.ApplicationMode) string {
+	switch mode {
+	case models.ApplicationMode_APPLICATION_MODE_ONLINE:
+		return "online"
+	case models.ApplicationMode_APPLICATION_MODE_OFFLINE:
+		return "offline"
+	case models.ApplicationMode_APPLICATION_MODE_BOTH:
+		return "both"
+	}
+	return ""
+}
+
+// csvRow renders r in csvHeader order.
+func (r OutputRecord) csvRow() []string {
+	urls := make([]string, len(r.Attachments))
+	for i, a := range r.Attachments {
+		urls[i] = a.URL
+	}
+	return []string{
+		r.ID,
+		r.Title,
+		r.Department,
+		r.Location,
+		r.URL,
+		r.Date,
+		r.LastDate,
+		r.AdvertisementNo,
+		csvInt(r.Vacancies),
+		strings.Join(r.Qualifications, "; "),
+		r.PayLevel,
+		r.PayScale,
+		csvInt(r.MinAge),
+		csvInt(r.MaxAge),
+		r.ApplicationMode,
+		r.Source,
+		strings.Join(urls, "; "),
+		r.ScrapedAt,
+	}
+}
+
+// csvInt renders n, leaving unknown (zero) values blank.
+func csvInt(n int32) string {
+	if n == 0 {
+		return ""
+	}
+	return strconv.Itoa(int(n))
 }
 
 // AppendResults writes job postings to the output file WITHOUT overwriting
@@ -61,15 +167,7 @@ func AppendResults(jobs []*models.JobPosting, cfg OutputConfig, logger *slog.Log
 	// Convert to output records
 	records := make([]OutputRecord, 0, len(jobs))
 	for _, j := range jobs {
-		records = append(records, OutputRecord{
-			ID:         j.GetId(),
-			Title:      j.GetTitle(),
-			Department: j.GetDepartment(),
-			Location:   j.GetLocation(),
-			URL:        j.GetUrl(),
-			Date:       j.GetDate(),
-			ScrapedAt:  scrapedAt,
-		})
+		records = append(records, outputRecord(j, scrapedAt))
 	}
 
 	switch cfg.Format {
@@ -124,6 +222,8 @@ func appendJSON(records []OutputRecord, dir string, logger *slog.Logger) error {
 }
 
 // appendCSV opens the CSV file in append mode, writing headers only if the file is new.
+// A file written with different columns is moved aside first, so rows never
+// end up under the wrong header.
 func appendCSV(records []OutputRecord, dir string, logger *slog.Logger) error {
 	filePath := filepath.Join(dir, "data.csv")
 
@@ -131,6 +231,18 @@ func appendCSV(records []OutputRecord, dir string, logger *slog.Logger) error {
 	isNew := false
 	if _, err := os.Stat(filePath); os.IsNotExist(err) {
 		isNew = true
+	} else if moved, err := rotateStaleCSV(filePath); err != nil {
+		logger.Error("failed to move aside CSV with old columns",
+			slog.String("file", filePath),
+			slog.String("error", err.Error()),
+		)
+		return err
+	} else if moved != "" {
+		logger.Warn("CSV columns changed — moved existing file aside",
+			slog.String("file", filePath),
+			slog.String("moved_to", moved),
+		)
+		isNew = true
 	}
 
 	file, err := os.OpenFile(filePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
@@ -148,23 +260,13 @@ func appendCSV(records []OutputRecord, dir string, logger *slog.Logger) error {
 
 	// Write header row only for new files
 	if isNew {
-		header := []string{"id", "title", "department", "location", "url", "date", "scraped_at"}
-		if err := writer.Write(header); err != nil {
+		if err := writer.Write(csvHeader); err != nil {
 			return fmt.Errorf("write CSV header: %w", err)
 		}
 	}
 
 	for _, r := range records {
-		row := []string{
-			r.ID,
-			r.Title,
-			r.Department,
-			r.Location,
-			r.URL,
-			r.Date,
-			r.ScrapedAt,
-		}
-		if err := writer.Write(row); err != nil {
+		if err := writer.Write(r.csvRow()); err != nil {
 			logger.Error("failed to write CSV row",
 				slog.String("id", r.ID),
 				slog.String("error", err.Error()),
@@ -180,3 +282,24 @@ func appendCSV(records []OutputRecord, dir string, logger *slog.Logger) error {
 	)
 	return nil
 }
+
+// rotateStaleCSV renames filePath to data-<timestamp>.csv when its header is
+// not csvHeader, returning the new name, or "" when the file is current.
+func rotateStaleCSV(filePath string) (string, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return "", fmt.Errorf("open CSV %s: %w", filePath, err)
+	}
+	header, err := csv.NewReader(file).Read()
+	file.Close()
+	if err == io.EOF || slices.Equal(header, csvHeader) {
+		return "", nil
+	}
+
+	ext := filepath.Ext(filePath)
+	moved := strings.TrimSuffix(filePath, ext) + "-" + time.Now().UTC().Format("20060102T150405Z") + ext
+	if err := os.Rename(filePath, moved); err != nil {
+		return "", fmt.Errorf("move aside CSV %s: %w", filePath, err)
+	}
+	return moved, nil
+}
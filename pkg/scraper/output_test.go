@@ -6,6 +6,7 @@ import (
 	"log/slog"
 	"os"
 	"path/filepath"
+	"slices"
 	"testing"
 
 	"github.com/entreya/job-aggregation/pkg/models"
@@ -26,12 +27,21 @@ func testJobs() []*models.JobPosting {
 			Date:       "2026-02-26",
 		},
 		{
-			Id:         "test-id-2",
-			Title:      "Junior Engineer",
-			Department: "NIC",
-			Location:   "Delhi",
-			Url:        "https://recruitment.nic.in/vacancy.php?id=2",
-			Date:       "2026-02-26",
+			Id:              "test-id-2",
+			Title:           "Junior Engineer",
+			Department:      "NIC",
+			Location:        "Delhi",
+			Url:             "https://recruitment.nic.in/vacancy.php?id=2",
+			Date:            "2026-02-26",
+			LastDate:        "2026-03-31",
+			Vacancies:       12,
+			Qualifications:  []string{"Diploma", "B.E./B.Tech"},
+			MaxAge:          27,
+			ApplicationMode: models.ApplicationMode_APPLICATION_MODE_ONLINE,
+			Source:          "nic",
+			Attachments: []*models.Attachment{
+				{Title: "Syllabus", Url: "https://recruitment.nic.in/syllabus.pdf"},
+			},
 		},
 	}
 }
@@ -57,7 +67,12 @@ func TestAppendResults_JSON_CreatesFile(t *testing.T) {
 	}
 
 	if len(records) != 2 {
-		t.Errorf("expected 2 records, got %d", len(records))
+		t.Fatalf("expected 2 records, got %d", len(records))
+	}
+	je := records[1]
+	if je.LastDate != "2026-03-31" || je.MaxAge != 27 || je.ApplicationMode != "online" || len(je.Qualifications) != 2 ||
+		len(je.Attachments) != 1 || je.Attachments[0].Title != "Syllabus" {
+		t.Errorf("structured fields not written: %+v", je)
 	}
 }
 
@@ -149,14 +164,66 @@ func TestAppendResults_CSV_CreatesFile(t *testing.T) {
 	}
 
 	// Check header
-	expectedHeader := []string{"id", "title", "department", "location", "url", "date", "scraped_at"}
-	for i, h := range expectedHeader {
-		if rows[0][i] != h {
-			t.Errorf("header column %d: expected %q, got %q", i, h, rows[0][i])
+	expectedHeader := []string{"id", "title", "department", "location", "url", "date", "last_date",
+		"advertisement_no", "vacancies", "qualifications", "pay_level", "pay_scale",
+		"min_age", "max_age", "application_mode", "source", "attachments", "scraped_at"}
+	if !slices.Equal(rows[0], expectedHeader) {
+		t.Errorf("header: expected %q, got %q", expectedHeader, rows[0])
+	}
+
+	// Structured fields land in their columns; unknown numbers stay blank.
+	col := func(row []string, name string) string { return row[slices.Index(expectedHeader, name)] }
+	je := rows[2]
+	for name, want := range map[string]string{
+		"last_date":        "2026-03-31",
+		"vacancies":        "12",
+		"qualifications":   "Diploma; B.E./B.Tech",
+		"min_age":          "",
+		"max_age":          "27",
+		"application_mode": "online",
+		"source":           "nic",
+		"attachments":      "https://recruitment.nic.in/syllabus.pdf",
+	} {
+		if got := col(je, name); got != want {
+			t.Errorf("%s: expected %q, got %q", name, want, got)
 		}
 	}
 }
 
+func TestAppendResults_CSV_MovesAsideOldColumns(t *testing.T) {
+	dir := t.TempDir()
+	cfg := OutputConfig{Dir: dir, Format: "csv"}
+	old := "id,title,department,location,url,date,scraped_at\nx,Clerk,NIC,Delhi,u,2026-01-01,t\n"
+	if err := os.WriteFile(filepath.Join(dir, "data.csv"), []byte(old), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := AppendResults(testJobs(), cfg, testOutputLogger()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	moved, _ := filepath.Glob(filepath.Join(dir, "data-*.csv"))
+	if len(moved) != 1 {
+		t.Fatalf("expected old file moved aside, got %v", moved)
+	}
+	if data, _ := os.ReadFile(moved[0]); string(data) != old {
+		t.Errorf("moved file altered: %q", data)
+	}
+
+	file, err := os.Open(filepath.Join(dir, "data.csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	rows, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		t.Fatalf("failed to parse CSV: %v", err)
+	}
+	if len(rows) != 3 || rows[0][6] != "last_date" {
+		t.Errorf("expected fresh file with new header and 2 rows, got %q", rows)
+	}
+}
+
 func TestAppendResults_CSV_AppendsWithoutDuplicateHeader(t *testing.T) {
 	dir := t.TempDir()
 	cfg := OutputConfig{Dir: dir, Format: "csv"}
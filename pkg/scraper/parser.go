@@ -1,84 +1,32 @@
 package scraper
 
 import (
-	"crypto/sha256"
-	"encoding/hex"
-	"log/slog"
 	"regexp"
 	"strings"
+	"time"
 	"unicode"
 
-	"github.com/PuerkitoBio/goquery"
+	"github.com/entreya/job-aggregation/pkg/entity"
+	"github.com/entreya/job-aggregation/pkg/jobid"
 	"github.com/entreya/job-aggregation/pkg/models"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 const (
-	// baseURL is the root URL for resolving relative links.
-	baseURL = "https://recruitment.nic.in/"
+	// nicBaseURL is the root URL for resolving relative links on the NIC portal.
+	nicBaseURL = "https://recruitment.nic.in/"
 )
 
+// ParseOptions carries the per-source context a parser needs.
+type ParseOptions struct {
+	BaseURL    string // Root URL for resolving relative links
+	Department string // Department assigned to every posting
+	Location   string // Location assigned to every posting
+}
+
 // controlCharRegex matches non-printable control characters (excluding \n, \r, \t).
 var controlCharRegex = regexp.MustCompile(`[\x00-\x08\x0B\x0C\x0E-\x1F\x7F]`)
 
-// ParseJobs extracts job postings from raw HTML content.
-// It parses all <a href> links within the page, resolves relative URLs,
-// sanitizes text, and generates stable IDs from URL hashes.
-//
-// Rows with empty title or URL are skipped and logged.
-func ParseJobs(htmlContent string, logger *slog.Logger) ([]*models.JobPosting, error) {
-	doc, err := goquery.NewDocumentFromReader(strings.NewReader(htmlContent))
-	if err != nil {
-		return nil, err
-	}
-
-	// Pre-allocate with a reasonable capacity
-	jobs := make([]*models.JobPosting, 0, 64)
-	skipped := 0
-
-	doc.Find("a[href]").Each(func(i int, sel *goquery.Selection) {
-		link, exists := sel.Attr("href")
-		if !exists {
-			return
-		}
-
-		text := SanitizeString(sel.Text())
-		link = strings.TrimSpace(link)
-
-		// Skip entries with empty title or link
-		if link == "" || text == "" {
-			skipped++
-			logger.Debug("skipped row: empty title or URL",
-				slog.Int("row_index", i),
-				slog.String("raw_link", link),
-				slog.String("raw_text", text),
-			)
-			return
-		}
-
-		// Resolve relative URLs
-		link = resolveURL(link)
-
-		job := &models.JobPosting{
-			Id:         GenerateID(link),
-			Title:      text,
-			Department: "NIC",
-			Location:   "All India",
-			Url:        link,
-		}
-
-		jobs = append(jobs, job)
-	})
-
-	if skipped > 0 {
-		logger.Info("rows skipped during parsing",
-			slog.Int("skipped_count", skipped),
-			slog.Int("parsed_count", len(jobs)),
-		)
-	}
-
-	return jobs, nil
-}
-
 // SanitizeString cleans a scraped string by:
 //   - Trimming leading/trailing whitespace
 //   - Removing non-printable control characters
@@ -94,19 +42,53 @@ func SanitizeString(s string) string {
 	return strings.TrimSpace(s)
 }
 
-// GenerateID creates a stable, unique ID by SHA256-hashing the URL.
-// This ensures IDs are safe for use as DB primary keys regardless of
-// URL special characters.
+// GenerateID creates a stable, unique ID by SHA256-hashing the canonical
+// form of the URL (see jobid.CanonicalURL), so http/https, "www.", trailing
+// slashes and tracking parameters do not create new jobs. IDs are safe for
+// use as DB primary keys regardless of URL special characters.
 func GenerateID(rawURL string) string {
-	hash := sha256.Sum256([]byte(rawURL))
-	return hex.EncodeToString(hash[:16]) // First 16 bytes = 32 hex chars (sufficient uniqueness)
+	return jobid.FromURL(rawURL)
+}
+
+// Fingerprint returns the content fingerprint used to link duplicate
+// postings across URLs and sources (see jobid.Fingerprint). The closing date
+// is preferred over the posting date, which boards often state differently.
+func Fingerprint(job *models.JobPosting) string {
+	date := job.GetLastDate()
+	if date == "" {
+		date = job.GetDate()
+	}
+	return jobid.Fingerprint(job.GetTitle(), job.GetDepartment(), date)
+}
+
+// setTimestamps fills job's PostedAt and ClosingAt from its YYYY-MM-DD Date
+// and LastDate, leaving them unset when a date is missing or malformed.
+func setTimestamps(job *models.JobPosting) {
+	job.PostedAt = dateTimestamp(job.GetDate())
+	job.ClosingAt = dateTimestamp(job.GetLastDate())
+}
+
+// dateTimestamp returns midnight UTC of a YYYY-MM-DD date, or nil.
+func dateTimestamp(date string) *timestamppb.Timestamp {
+	t, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return nil
+	}
+	return timestamppb.New(t)
 }
 
 // resolveURL converts relative URLs to absolute URLs using baseURL.
-func resolveURL(link string) string {
+// An empty baseURL leaves relative links untouched.
+func resolveURL(baseURL, link string) string {
 	if strings.HasPrefix(link, "http://") || strings.HasPrefix(link, "https://") {
 		return link
 	}
+	if baseURL == "" {
+		return link
+	}
+	if !strings.HasSuffix(baseURL, "/") {
+		baseURL += "/"
+	}
 
 	base := strings.TrimSuffix(baseURL, "/")
 	if strings.HasPrefix(link, "/") {
@@ -115,3 +97,25 @@ func resolveURL(link string) string {
 
 	return baseURL + link
 }
+
+// normalizeEntities rewrites job's department and location to their
+// canonical names and sets their entity IDs (see pkg/entity). Names not in
+// the dataset are kept as scraped, without an ID. A missing location, or one
+// stated only as all of India, gives way to a more specific one named in the
+// title. Migration 9 in pkg/db applied the same rules to existing jobs.
+func normalizeEntities(job *models.JobPosting) {
+	catalog := entity.Default()
+	if e, ok := catalog.Department(job.GetDepartment()); ok {
+		job.Department, job.DepartmentId = e.Name, e.ID
+	}
+
+	loc, ok := catalog.Location(job.GetLocation())
+	if job.GetLocation() == "" || (ok && loc.Depth() == 0) {
+		if inTitle, found := catalog.Location(job.GetTitle()); found && inTitle.Depth() > 0 {
+			loc, ok = inTitle, true
+		}
+	}
+	if ok {
+		job.Location, job.LocationId = loc.Name, loc.ID
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"log/slog"
 	"os"
 	"testing"
+
+	"github.com/entreya/job-aggregation/pkg/models"
 )
 
 func testParserLogger() *slog.Logger {
@@ -19,84 +21,16 @@ func loadFixture(t *testing.T, path string) string {
 	return string(data)
 }
 
-func TestParseJobs_ValidHTML(t *testing.T) {
-	html := loadFixture(t, "testdata/sample.html")
-	jobs, err := ParseJobs(html, testParserLogger())
-	if err != nil {
-		t.Fatalf("unexpected error: %v", err)
-	}
-
-	// The fixture has 6 links total:
-	// 3 job links with text (rows 1, 2, 3)
-	// 1 empty href (row 4) → skipped
-	// 1 whitespace title (row 5) → parsed with sanitization
-	// 1 empty text (row 6) → skipped
-	// 2 navigation links (Contact, About) → parsed
-	// Total parsed: 3 + 1 + 2 = 6
-
-	if len(jobs) < 5 {
-		t.Errorf("expected at least 5 parsed jobs, got %d", len(jobs))
-	}
-}
-
-func TestParseJobs_SkipsEmptyRows(t *testing.T) {
-	html := `<html><body>
-		<a href="">Empty link</a>
-		<a href="http://example.com"></a>
-		<a href="http://example.com">Valid</a>
-	</body></html>`
-
-	jobs, err := ParseJobs(html, testParserLogger())
-	if err != nil {
-		t.Fatalf("unexpected error: %v", err)
-	}
-
-	if len(jobs) != 1 {
-		t.Errorf("expected 1 job (2 should be skipped), got %d", len(jobs))
-	}
-}
-
-func TestParseJobs_ResolvesRelativeURLs(t *testing.T) {
-	html := `<html><body>
-		<a href="vacancy.php?id=1">Job A</a>
-		<a href="/root/vacancy.php?id=2">Job B</a>
-	</body></html>`
-
-	jobs, err := ParseJobs(html, testParserLogger())
-	if err != nil {
-		t.Fatalf("unexpected error: %v", err)
+func TestResolveURL(t *testing.T) {
+	tests := []struct{ link, want string }{
+		{"vacancy.php?id=1", "https://recruitment.nic.in/vacancy.php?id=1"},
+		{"/root/vacancy.php?id=2", "https://recruitment.nic.in/root/vacancy.php?id=2"},
+		{"https://recruitment.nic.in/vacancy.php?id=100", "https://recruitment.nic.in/vacancy.php?id=100"},
 	}
-
-	if len(jobs) != 2 {
-		t.Fatalf("expected 2 jobs, got %d", len(jobs))
-	}
-
-	// Relative URL (no leading slash)
-	if jobs[0].Url != "https://recruitment.nic.in/vacancy.php?id=1" {
-		t.Errorf("expected resolved URL, got %q", jobs[0].Url)
-	}
-
-	// Root-relative URL (leading slash)
-	if jobs[1].Url != "https://recruitment.nic.in/root/vacancy.php?id=2" {
-		t.Errorf("expected root-resolved URL, got %q", jobs[1].Url)
-	}
-}
-
-func TestParseJobs_PreservesAbsoluteURLs(t *testing.T) {
-	html := `<html><body>
-		<a href="https://recruitment.nic.in/vacancy.php?id=100">Absolute Job</a>
-	</body></html>`
-
-	jobs, err := ParseJobs(html, testParserLogger())
-	if err != nil {
-		t.Fatalf("unexpected error: %v", err)
-	}
-
-	if len(jobs) != 1 {
-		t.Fatalf("expected 1 job, got %d", len(jobs))
-	}
-	if jobs[0].Url != "https://recruitment.nic.in/vacancy.php?id=100" {
-		t.Errorf("absolute URL should be preserved, got %q", jobs[0].Url)
+	for _, tt := range tests {
+		if got := resolveURL(nicBaseURL, tt.link); got != tt.want {
+			t.Errorf("resolveURL(%q) = %q, want %q", tt.link, got, tt.want)
+		}
 	}
 }
 
@@ -148,24 +82,42 @@ func TestGenerateID_UniquenessForDifferentURLs(t *testing.T) {
 	}
 }
 
-func TestParseJobs_EmptyHTML(t *testing.T) {
-	jobs, err := ParseJobs("<html><body></body></html>", testParserLogger())
-	if err != nil {
-		t.Fatalf("unexpected error: %v", err)
-	}
-	if len(jobs) != 0 {
-		t.Errorf("expected 0 jobs for empty HTML, got %d", len(jobs))
+func TestGenerateID_IgnoresURLSpelling(t *testing.T) {
+	id := GenerateID("https://recruitment.nic.in/vacancy.php?id=42")
+	for _, u := range []string{
+		"http://recruitment.nic.in/vacancy.php?id=42",
+		"https://recruitment.nic.in/vacancy.php?id=42&utm_source=telegram",
+		"https://recruitment.nic.in/vacancy.php/?id=42#apply",
+	} {
+		if got := GenerateID(u); got != id {
+			t.Errorf("GenerateID(%q) = %q, want %q", u, got, id)
+		}
 	}
 }
 
-func TestParseJobs_MalformedHTML(t *testing.T) {
-	// goquery is tolerant of malformed HTML
-	html := "<a href='test.php'>Unclosed"
-	jobs, err := ParseJobs(html, testParserLogger())
-	if err != nil {
-		t.Fatalf("unexpected error: %v", err)
+func TestNormalizeEntities(t *testing.T) {
+	tests := []struct {
+		title, department, location string
+		wantDept, wantDeptID        string
+		wantLoc, wantLocID          string
+	}{
+		{"Scientist-B", "NIC", "All India", "National Informatics Centre", "meity/nic", "All India", "in"},
+		{"Scientist-B at NIC State Centre, Patna", "NIC", "All India", "National Informatics Centre", "meity/nic", "Patna", "in/br/patna"},
+		{"Clerk", "M/o Finance", "", "Ministry of Finance", "mof", "", ""},
+		{"Clerk, Bombay office", "Municipal Corporation", "", "Municipal Corporation", "", "Mumbai", "in/mh/mumbai"},
+		// A stated location is not overridden by an unrelated title.
+		{"Engineer for Delhi projects", "CPWD", "Various", "CPWD", "", "Various", ""},
 	}
-	if len(jobs) != 1 {
-		t.Errorf("expected 1 job from malformed HTML, got %d", len(jobs))
+	for _, tt := range tests {
+		t.Run(tt.title, func(t *testing.T) {
+			job := &models.JobPosting{Title: tt.title, Department: tt.department, Location: tt.location}
+			normalizeEntities(job)
+			if job.Department != tt.wantDept || job.DepartmentId != tt.wantDeptID ||
+				job.Location != tt.wantLoc || job.LocationId != tt.wantLocID {
+				t.Errorf("got %q (%q), %q (%q); want %q (%q), %q (%q)",
+					job.Department, job.DepartmentId, job.Location, job.LocationId,
+					tt.wantDept, tt.wantDeptID, tt.wantLoc, tt.wantLocID)
+			}
+		})
 	}
 }
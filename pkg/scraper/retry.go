@@ -1,6 +1,7 @@
 package scraper
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"log/slog"
@@ -39,6 +40,58 @@ func (e *RetryableError) Unwrap() error {
 	return e.Err
 }
 
+// hardProxyPatterns match errors where the proxy refuses the connection at
+// the protocol level:
+//
+//	ERR_TUNNEL_CONNECTION_FAILED — proxy refused the CONNECT tunnel for HTTPS.
+//	ERR_PROXY_CONNECTION_FAILED  — proxy TCP connection itself was rejected.
+//	407 Proxy Authentication Required — proxy rejected the credentials.
+//	username/password authentication failed — SOCKS5 proxy rejected them.
+var hardProxyPatterns = []string{
+	"err_tunnel_connection_failed",
+	"tunnel connection failed",
+	"err_proxy_connection_failed",
+	"proxy connection failed",
+	"proxy authentication required",
+	"username/password authentication failed",
+	"access denied",
+}
+
+// proxyNetworkPatterns match network failures on the way through a proxy:
+// net/http errors and Chrome's net::ERR_* codes. Browser crashes are left
+// out; they are not the proxy's fault.
+var proxyNetworkPatterns = []string{
+	"timeout",
+	"timed_out",
+	"deadline exceeded",
+	"connection refused",
+	"connection reset",
+	"err_connection",
+	"err_socks_connection",
+	"eof",
+	"broken pipe",
+	"no such host",
+	"tls handshake",
+}
+
+// isProxyFailure reports whether err suggests the proxy, rather than the
+// target site or the browser, is at fault: a hard proxy failure or a network
+// failure. Caller cancellation and HTTP errors from the site are not.
+func isProxyFailure(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	errMsg := strings.ToLower(err.Error())
+	for _, patterns := range [][]string{hardProxyPatterns, proxyNetworkPatterns} {
+		for _, pattern := range patterns {
+			if strings.Contains(errMsg, pattern) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // isRetryable determines if an error warrants a retry.
 // Retries on: network/timeout errors, chromedp context errors, empty responses.
 // Does NOT retry on: nil errors, context cancellation by caller, hard proxy failures.
@@ -49,24 +102,15 @@ func isRetryable(err error) bool {
 
 	errMsg := strings.ToLower(err.Error())
 
-	// Non-retryable: intentional context cancellation
-	if errors.Is(err, errors.New("context canceled")) {
+	// Non-retryable: intentional context cancellation by the caller
+	if errors.Is(err, context.Canceled) {
 		return false
 	}
 
 	// Non-retryable: hard proxy failures.
 	// These are structural errors — the proxy is refusing the connection at the
 	// protocol level. Retrying against the same proxy will never succeed.
-	//   ERR_TUNNEL_CONNECTION_FAILED — proxy refused the CONNECT tunnel for HTTPS.
-	//   ERR_PROXY_CONNECTION_FAILED  — proxy TCP connection itself was rejected.
-	nonRetryablePatterns := []string{
-		"err_tunnel_connection_failed",
-		"tunnel connection failed",
-		"err_proxy_connection_failed",
-		"proxy connection failed",
-		"access denied",
-	}
-	for _, pattern := range nonRetryablePatterns {
+	for _, pattern := range hardProxyPatterns {
 		if strings.Contains(errMsg, pattern) {
 			return false
 		}
@@ -100,19 +144,30 @@ func isRetryable(err error) bool {
 }
 
 // WithRetry executes fn with exponential backoff retry logic.
+// It is WithRetryContext without cancellation.
+func WithRetry(cfg RetryConfig, url string, proxy string, logger *slog.Logger, fn func(attempt int) error) error {
+	return WithRetryContext(context.Background(), cfg, url, proxy, logger, fn)
+}
+
+// WithRetryContext executes fn with exponential backoff retry logic.
 // It logs each attempt with structured context (URL, proxy, attempt number, error).
 //
 // The fn receives the current attempt number (0-indexed).
-// If fn returns nil, WithRetry returns immediately.
-// If fn returns a non-retryable error, WithRetry returns immediately with the error.
+// If fn returns nil, WithRetryContext returns immediately.
+// If fn returns a non-retryable error, WithRetryContext returns immediately with the error.
+// If ctx is cancelled before an attempt or during backoff, returns ctx.Err() wrapped.
 // If all retries are exhausted, returns the last error.
-func WithRetry(cfg RetryConfig, url string, proxy string, logger *slog.Logger, fn func(attempt int) error) error {
+func WithRetryContext(ctx context.Context, cfg RetryConfig, url string, proxy string, logger *slog.Logger, fn func(attempt int) error) error {
 	if cfg.MaxRetries <= 0 {
 		cfg.MaxRetries = 1
 	}
 
 	var lastErr error
 	for attempt := 0; attempt < cfg.MaxRetries; attempt++ {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return fmt.Errorf("retry aborted for %s before attempt %d: %w", url, attempt+1, ctxErr)
+		}
+
 		err := fn(attempt)
 		if err == nil {
 			if attempt > 0 {
@@ -135,8 +190,8 @@ func WithRetry(cfg RetryConfig, url string, proxy string, logger *slog.Logger, f
 			slog.String("error", err.Error()),
 		)
 
-		// Do not retry if the error is non-retryable
-		if !isRetryable(err) {
+		// Do not retry if the error is non-retryable or the caller gave up
+		if !isRetryable(err) || ctx.Err() != nil {
 			logger.Error("non-retryable error — aborting",
 				slog.String("url", url),
 				slog.String("error", err.Error()),
@@ -158,9 +213,24 @@ func WithRetry(cfg RetryConfig, url string, proxy string, logger *slog.Logger, f
 				slog.Duration("backoff", backoff),
 				slog.Int("next_attempt", attempt+2),
 			)
-			time.Sleep(backoff)
+			if sleepErr := sleepContext(ctx, backoff); sleepErr != nil {
+				return fmt.Errorf("retry aborted for %s during backoff: %w", url, sleepErr)
+			}
 		}
 	}
 
 	return fmt.Errorf("all %d retry attempts exhausted for %s: %w", cfg.MaxRetries, url, lastErr)
 }
+
+// sleepContext pauses for d, returning early with ctx.Err() if ctx is done first.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -1,7 +1,9 @@
 package scraper
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"log/slog"
 	"os"
 	"sync/atomic"
@@ -173,3 +175,77 @@ func TestWithRetry_ZeroMaxRetries_DefaultsToOne(t *testing.T) {
 		t.Errorf("expected 1 call with zero MaxRetries, got %d", calls)
 	}
 }
+
+func TestWithRetryContext_StopsDuringBackoff(t *testing.T) {
+	cfg := RetryConfig{MaxRetries: 3, BaseDelay: 5 * time.Second}
+	var calls int32
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := WithRetryContext(ctx, cfg, "http://test.com", "proxy1", testRetryLogger(), func(attempt int) error {
+		atomic.AddInt32(&calls, 1)
+		return errors.New("connection timeout")
+	})
+	elapsed := time.Since(start)
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got: %v", err)
+	}
+	if atomic.LoadInt32(&calls) != 1 {
+		t.Errorf("expected 1 call before cancellation, got %d", calls)
+	}
+	if elapsed >= time.Second {
+		t.Errorf("expected backoff to be interrupted, took %v", elapsed)
+	}
+}
+
+func TestWithRetryContext_CancelledBeforeFirstAttempt(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var calls int32
+	err := WithRetryContext(ctx, DefaultRetryConfig(), "http://test.com", "", testRetryLogger(), func(attempt int) error {
+		atomic.AddInt32(&calls, 1)
+		return nil
+	})
+
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got: %v", err)
+	}
+	if atomic.LoadInt32(&calls) != 0 {
+		t.Errorf("expected no calls with a cancelled context, got %d", calls)
+	}
+}
+
+func TestIsRetryable_ContextCanceled(t *testing.T) {
+	if isRetryable(fmt.Errorf("chromedp navigation failed: %w", context.Canceled)) {
+		t.Error("expected wrapped context.Canceled to be non-retryable")
+	}
+}
+
+func TestIsProxyFailure(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{errors.New("page load error net::ERR_TUNNEL_CONNECTION_FAILED"), true},
+		{errors.New("page load error net::ERR_CONNECTION_RESET"), true},
+		{errors.New("page load error net::ERR_TIMED_OUT"), true},
+		{errors.New("HTTP GET failed: proxyconnect tcp: dial tcp 10.0.0.1:8080: connect: connection refused"), true},
+		{errors.New("unexpected HTTP status: 407 407 Proxy Authentication Required"), true},
+		{errors.New("HTTP GET failed: socks connect tcp 127.0.0.1:1080->example.com:80: username/password authentication failed"), true},
+		{errProxyAuthRejected, true},
+		{fmt.Errorf("HTTP GET failed: %w", context.DeadlineExceeded), true},
+		{errors.New("unexpected HTTP status: 404 404 Not Found"), false},
+		{errors.New("chromedp navigation failed: page crashed"), false},
+		{fmt.Errorf("HTTP GET failed: %w", context.Canceled), false},
+		{nil, false},
+	}
+	for _, tt := range tests {
+		if got := isProxyFailure(tt.err); got != tt.want {
+			t.Errorf("isProxyFailure(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
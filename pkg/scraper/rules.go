@@ -0,0 +1,445 @@
+package scraper
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/andybalholm/cascadia"
+	"github.com/entreya/job-aggregation/pkg/dates"
+	"github.com/entreya/job-aggregation/pkg/models"
+	"github.com/entreya/job-aggregation/pkg/proxy"
+	"gopkg.in/yaml.v3"
+)
+
+// Field names understood by the rule-based extractor.
+const (
+	FieldTitle           = "title"
+	FieldURL             = "url"
+	FieldDate            = "date"
+	FieldLastDate        = "last_date"
+	FieldAdvertisementNo = "advertisement_no"
+	FieldDepartment      = "department"
+	FieldLocation        = "location"
+)
+
+// knownFields lists every field a rule may define, and whether it holds a date.
+var knownFields = map[string]bool{
+	FieldTitle:           false,
+	FieldURL:             false,
+	FieldDate:            true,
+	FieldLastDate:        true,
+	FieldAdvertisementNo: false,
+	FieldDepartment:      false,
+	FieldLocation:        false,
+}
+
+// RulesFile is the top-level layout of an extraction rules file.
+//
+// Example (YAML):
+//
+//	sources:
+//	  - name: nic
+//	    urls: ["https://recruitment.nic.in/index_new.php"]
+//	    base_url: https://recruitment.nic.in/
+//	    row: "table tr:has(td a[href])"
+//	    fields:
+//	      title: {selector: "td:nth-child(2)"}
+//	      url:   {selector: "a[href]", attr: href}
+//	      date:  {selector: "td:nth-child(4)", date_formats: ["02.01.2006"]}
+type RulesFile struct {
+	Sources []SourceRules `json:"sources" yaml:"sources"`
+}
+
+// SourceRules declares how to scrape one job board without Go code.
+type SourceRules struct {
+	Name       string               `json:"name" yaml:"name"`
+	URLs       []string             `json:"urls" yaml:"urls"`
+	BaseURL    string               `json:"base_url" yaml:"base_url"`
+	Fetch      FetchStrategy        `json:"fetch" yaml:"fetch"`
+	Department string               `json:"department" yaml:"department"`       // Default when no department field matches
+	Location   string               `json:"location" yaml:"location"`           // Default when no location field matches
+	Egress     string               `json:"egress_region" yaml:"egress_region"` // Required proxy egress region, e.g. IN or IN-MH
+	Row        string               `json:"row" yaml:"row"`                     // Selector for one posting container
+	Fields     map[string]FieldRule `json:"fields" yaml:"fields"`
+}
+
+// FieldRule extracts one value from a posting container.
+type FieldRule struct {
+	Selector    string   `json:"selector" yaml:"selector"`         // Relative to the row; empty = the row itself
+	Attr        string   `json:"attr" yaml:"attr"`                 // Attribute to read; empty = element text
+	Regex       string   `json:"regex" yaml:"regex"`               // Keeps the first capture group (or whole match)
+	DateFormats []string `json:"date_formats" yaml:"date_formats"` // Go layouts; only for date fields
+}
+
+// RuleSource is a Source driven by validated, compiled SourceRules.
+type RuleSource struct {
+	rules  SourceRules
+	row    cascadia.Selector
+	fields map[string]compiledField
+}
+
+// compiledField is a FieldRule with its selector and regex pre-compiled.
+type compiledField struct {
+	rule     FieldRule
+	selector cascadia.Selector // nil when the rule targets the row itself
+	regex    *regexp.Regexp
+}
+
+// LoadRules reads an extraction rules file and compiles its sources.
+// The format is chosen by extension: .yaml/.yml or .json.
+func LoadRules(path string) ([]*RuleSource, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read rules file %s: %w", path, err)
+	}
+
+	var format string
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		format = "yaml"
+	case ".json":
+		format = "json"
+	default:
+		return nil, fmt.Errorf("rules file %s: unsupported extension (expected .yaml, .yml or .json)", path)
+	}
+
+	sources, err := ParseRules(data, format)
+	if err != nil {
+		return nil, fmt.Errorf("rules file %s: %w", path, err)
+	}
+	return sources, nil
+}
+
+// ParseRules decodes rules in the given format ("yaml" or "json"), validates
+// every source and compiles it. Unknown keys are rejected so typos surface
+// immediately. All validation problems are reported together.
+func ParseRules(data []byte, format string) ([]*RuleSource, error) {
+	var file RulesFile
+
+	switch format {
+	case "yaml":
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(&file); err != nil {
+			return nil, fmt.Errorf("decode YAML: %w", err)
+		}
+	case "json":
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&file); err != nil {
+			return nil, fmt.Errorf("decode JSON: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported rules format %q", format)
+	}
+
+	if len(file.Sources) == 0 {
+		return nil, fmt.Errorf("no sources defined")
+	}
+
+	sources := make([]*RuleSource, 0, len(file.Sources))
+	errs := make([]error, 0)
+	names := make(map[string]bool)
+
+	for i, rules := range file.Sources {
+		src, err := CompileRules(rules)
+		if err != nil {
+			label := rules.Name
+			if label == "" {
+				label = fmt.Sprintf("#%d", i+1)
+			}
+			errs = append(errs, fmt.Errorf("source %s: %w", label, err))
+			continue
+		}
+		if names[src.Name()] {
+			errs = append(errs, fmt.Errorf("source %s: duplicate name", src.Name()))
+			continue
+		}
+		names[src.Name()] = true
+		sources = append(sources, src)
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return sources, nil
+}
+
+// CompileRules validates a single SourceRules and compiles its selectors and
+// regular expressions. Every problem found is reported, not just the first.
+func CompileRules(rules SourceRules) (*RuleSource, error) {
+	errs := make([]error, 0)
+	rules.Name = strings.ToLower(strings.TrimSpace(rules.Name))
+
+	if rules.Name == "" {
+		errs = append(errs, errors.New("name is required"))
+	}
+	if len(rules.URLs) == 0 {
+		errs = append(errs, errors.New("at least one URL is required"))
+	}
+	for _, u := range rules.URLs {
+		if err := validateAbsoluteURL(u); err != nil {
+			errs = append(errs, fmt.Errorf("url %q: %w", u, err))
+		}
+	}
+	if rules.BaseURL != "" {
+		if err := validateAbsoluteURL(rules.BaseURL); err != nil {
+			errs = append(errs, fmt.Errorf("base_url %q: %w", rules.BaseURL, err))
+		}
+	}
+	switch rules.Fetch {
+	case "", FetchBrowser, FetchHTTP:
+	default:
+		errs = append(errs, fmt.Errorf("fetch %q: expected %q or %q", rules.Fetch, FetchBrowser, FetchHTTP))
+	}
+	if region, err := proxy.ParseRegion(rules.Egress); err != nil {
+		errs = append(errs, fmt.Errorf("egress_region: %w", err))
+	} else {
+		rules.Egress = region
+	}
+
+	src := &RuleSource{rules: rules, fields: make(map[string]compiledField)}
+
+	if strings.TrimSpace(rules.Row) == "" {
+		errs = append(errs, errors.New("row selector is required"))
+	} else if sel, err := cascadia.Compile(rules.Row); err != nil {
+		errs = append(errs, fmt.Errorf("row: invalid selector %q: %w", rules.Row, err))
+	} else {
+		src.row = sel
+	}
+
+	for _, required := range []string{FieldTitle, FieldURL} {
+		if _, ok := rules.Fields[required]; !ok {
+			errs = append(errs, fmt.Errorf("field %q is required", required))
+		}
+	}
+
+	// Sorted so error messages come out in a stable order.
+	fieldNames := make([]string, 0, len(rules.Fields))
+	for name := range rules.Fields {
+		fieldNames = append(fieldNames, name)
+	}
+	sort.Strings(fieldNames)
+
+	for _, name := range fieldNames {
+		rule := rules.Fields[name]
+		isDate, known := knownFields[name]
+		if !known {
+			errs = append(errs, fmt.Errorf("field %q: unknown field (expected one of %s)", name, knownFieldList()))
+			continue
+		}
+
+		cf := compiledField{rule: rule}
+		if rule.Selector != "" {
+			sel, err := cascadia.Compile(rule.Selector)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("field %q: invalid selector %q: %w", name, rule.Selector, err))
+			} else {
+				cf.selector = sel
+			}
+		}
+		if rule.Regex != "" {
+			re, err := regexp.Compile(rule.Regex)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("field %q: invalid regex %q: %w", name, rule.Regex, err))
+			} else {
+				cf.regex = re
+			}
+		}
+		if len(rule.DateFormats) > 0 && !isDate {
+			errs = append(errs, fmt.Errorf("field %q: date_formats is only allowed on %q and %q", name, FieldDate, FieldLastDate))
+		}
+		src.fields[name] = cf
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return src, nil
+}
+
+// Name implements Source.
+func (s *RuleSource) Name() string { return s.rules.Name }
+
+// URLs implements Source.
+func (s *RuleSource) URLs() []string { return s.rules.URLs }
+
+// BaseURL implements Source.
+func (s *RuleSource) BaseURL() string { return s.rules.BaseURL }
+
+// Strategy implements Source. Defaults to FetchBrowser when unset.
+func (s *RuleSource) Strategy() FetchStrategy {
+	if s.rules.Fetch == "" {
+		return FetchBrowser
+	}
+	return s.rules.Fetch
+}
+
+// EgressRegion implements Source.
+func (s *RuleSource) EgressRegion() string { return s.rules.Egress }
+
+// Parse implements Source by applying the compiled rules to every row.
+// Rows without a title or a navigable URL are skipped and logged.
+func (s *RuleSource) Parse(htmlContent string, logger *slog.Logger) ([]*models.JobPosting, error) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(htmlContent))
+	if err != nil {
+		return nil, err
+	}
+
+	jobs := make([]*models.JobPosting, 0, 64)
+	seen := make(map[string]bool)
+	skipped := 0
+
+	doc.FindMatcher(s.row).Each(func(i int, row *goquery.Selection) {
+		title := cleanTitle(s.extract(row, FieldTitle))
+		link := s.extract(row, FieldURL)
+		if title == "" || !isNavigableLink(link) {
+			skipped++
+			logger.Debug("skipped row: empty title or URL",
+				slog.String("source", s.Name()),
+				slog.Int("row_index", i),
+			)
+			return
+		}
+
+		link = resolveURL(s.rules.BaseURL, link)
+		id := GenerateID(link)
+		if seen[id] {
+			return
+		}
+		seen[id] = true
+
+		last := s.extractDate(row, FieldLastDate, logger)
+		job := &models.JobPosting{
+			Id:              id,
+			Title:           title,
+			Department:      s.rules.Department,
+			Location:        s.rules.Location,
+			Url:             link,
+			Date:            s.extractDate(row, FieldDate, logger).Format(),
+			LastDate:        last.Format(),
+			AdvertisementNo: s.extract(row, FieldAdvertisementNo),
+
+			LastDateConfidence: dateConfidence(last),
+		}
+		if dept := s.extract(row, FieldDepartment); dept != "" {
+			job.Department = dept
+		}
+		if loc := s.extract(row, FieldLocation); loc != "" {
+			job.Location = loc
+		}
+
+		jobs = append(jobs, job)
+	})
+
+	if skipped > 0 {
+		logger.Info("rows skipped during parsing",
+			slog.String("source", s.Name()),
+			slog.Int("skipped_count", skipped),
+			slog.Int("parsed_count", len(jobs)),
+		)
+	}
+
+	return jobs, nil
+}
+
+// extract returns the sanitized value of a field within row, or "" if the
+// field is not defined or does not match.
+func (s *RuleSource) extract(row *goquery.Selection, name string) string {
+	cf, ok := s.fields[name]
+	if !ok {
+		return ""
+	}
+
+	target := row
+	if cf.selector != nil {
+		target = row.FindMatcher(cf.selector).First()
+		if target.Length() == 0 {
+			return ""
+		}
+	}
+
+	var value string
+	if cf.rule.Attr != "" {
+		value, _ = target.Attr(cf.rule.Attr)
+	} else {
+		value = target.Text()
+	}
+	value = SanitizeString(value)
+
+	if cf.regex != nil {
+		m := cf.regex.FindStringSubmatch(value)
+		switch {
+		case m == nil:
+			return ""
+		case len(m) > 1:
+			value = m[1]
+		default:
+			value = m[0]
+		}
+	}
+
+	return strings.TrimSpace(value)
+}
+
+// extractDate extracts a date field using the field's layouts, read with
+// high confidence, falling back to the formats ParseTable accepts. The
+// result has no date when none was found.
+func (s *RuleSource) extractDate(row *goquery.Selection, name string, logger *slog.Logger) dates.Result {
+	raw := s.extract(row, name)
+	if raw == "" {
+		return dates.Result{}
+	}
+
+	for _, layout := range s.fields[name].rule.DateFormats {
+		if t, err := time.Parse(layout, raw); err == nil {
+			return dates.Result{Time: t, Confidence: dates.High}
+		}
+	}
+	if r, _ := dates.Parse(raw, time.Time{}); r.Format() != "" {
+		return r
+	}
+
+	logger.Debug("unparseable date",
+		slog.String("source", s.Name()),
+		slog.String("field", name),
+		slog.String("raw", raw),
+	)
+	return dates.Result{}
+}
+
+// validateAbsoluteURL checks that rawURL is an absolute http(s) URL.
+func validateAbsoluteURL(rawURL string) error {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return fmt.Errorf("expected an http or https URL")
+	}
+	if parsed.Host == "" {
+		return fmt.Errorf("missing host")
+	}
+	return nil
+}
+
+// knownFieldList renders the supported field names for error messages.
+func knownFieldList() string {
+	names := make([]string, 0, len(knownFields))
+	for name := range knownFields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
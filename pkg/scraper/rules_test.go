@@ -0,0 +1,224 @@
+package scraper
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const validRulesYAML = `
+sources:
+  - name: NIC
+    urls: ["https://recruitment.nic.in/index_new.php"]
+    base_url: https://recruitment.nic.in/
+    fetch: http
+    egress_region: in-dl
+    department: NIC
+    location: All India
+    row: "table.table tr:has(td a[href])"
+    fields:
+      title:
+        selector: "td:nth-child(2)"
+      url:
+        selector: "a[href]"
+        attr: href
+      advertisement_no:
+        selector: "td:nth-child(3)"
+      date:
+        selector: "td:nth-child(4)"
+        date_formats: ["02.01.2006"]
+      last_date:
+        selector: "td:nth-child(5)"
+        regex: '(\d{2}/\d{2}/\d{4})'
+        date_formats: ["02/01/2006"]
+`
+
+func TestParseRules_YAML_ExtractsFields(t *testing.T) {
+	sources, err := ParseRules([]byte(validRulesYAML), "yaml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(sources) != 1 {
+		t.Fatalf("expected 1 source, got %d", len(sources))
+	}
+
+	src := sources[0]
+	if src.Name() != "nic" {
+		t.Errorf("expected lowercased name 'nic', got %q", src.Name())
+	}
+	if src.Strategy() != FetchHTTP {
+		t.Errorf("expected fetch strategy %q, got %q", FetchHTTP, src.Strategy())
+	}
+	if src.EgressRegion() != "IN-DL" {
+		t.Errorf("expected normalised egress region IN-DL, got %q", src.EgressRegion())
+	}
+
+	jobs, err := src.Parse(loadFixture(t, "testdata/nic_listing.html"), testParserLogger())
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	// Rows 1, 2 and 4 match the row selector; row 4 only has a javascript: link.
+	if len(jobs) != 2 {
+		t.Fatalf("expected 2 jobs, got %d", len(jobs))
+	}
+
+	first := jobs[0]
+	if first.Url != "https://recruitment.nic.in/AppAdv.pdf" {
+		t.Errorf("unexpected URL %q", first.Url)
+	}
+	if first.AdvertisementNo != "NIC/SO/01/2026" {
+		t.Errorf("unexpected advertisement number %q", first.AdvertisementNo)
+	}
+	if first.Date != "2026-02-12" || first.LastDate != "2026-03-15" {
+		t.Errorf("expected dates 2026-02-12/2026-03-15, got %q/%q", first.Date, first.LastDate)
+	}
+	if first.Department != "NIC" || first.Location != "All India" {
+		t.Errorf("expected defaults, got %q/%q", first.Department, first.Location)
+	}
+
+	// Row 2's last date (2.3.2026) matches neither the regex nor the layout.
+	if jobs[1].LastDate != "" {
+		t.Errorf("expected empty last date for non-matching value, got %q", jobs[1].LastDate)
+	}
+}
+
+func TestParseRules_JSON(t *testing.T) {
+	data := `{"sources":[{"name":"board","urls":["https://board.example.in/"],"row":"li",
+		"fields":{"title":{"selector":"a"},"url":{"selector":"a","attr":"href"},
+		"location":{"selector":".loc"}}}]}`
+
+	sources, err := ParseRules([]byte(data), "json")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	html := `<ul><li><a href="https://board.example.in/1.pdf">Clerk</a><span class="loc">Pune</span></li></ul>`
+	jobs, err := sources[0].Parse(html, testParserLogger())
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if len(jobs) != 1 || jobs[0].Location != "Pune" {
+		t.Fatalf("expected 1 job located in Pune, got %+v", jobs)
+	}
+}
+
+func TestParseRules_ReportsAllValidationErrors(t *testing.T) {
+	data := `
+sources:
+  - name: broken
+    urls: ["ftp://files.example.in/"]
+    fetch: carrier-pigeon
+    egress_region: India
+    row: "tr[["
+    fields:
+      title: {selector: "td:nth-child("}
+      salary: {selector: "td"}
+      url: {attr: href, regex: "(unclosed"}
+      department: {date_formats: ["2006"]}
+`
+	_, err := ParseRules([]byte(data), "yaml")
+	if err == nil {
+		t.Fatal("expected validation error")
+	}
+
+	msg := err.Error()
+	for _, want := range []string{
+		"source broken",
+		`url "ftp://files.example.in/"`,
+		`fetch "carrier-pigeon"`,
+		`egress_region: invalid region "INDIA"`,
+		`row: invalid selector "tr[["`,
+		`field "title": invalid selector`,
+		`field "salary": unknown field`,
+		`field "url": invalid regex`,
+		`field "department": date_formats is only allowed`,
+	} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("expected error to mention %q\nfull error:\n%s", want, msg)
+		}
+	}
+}
+
+func TestParseRules_RequiresTitleAndURL(t *testing.T) {
+	data := `{"sources":[{"name":"x","urls":["https://x.example.in/"],"row":"li","fields":{}}]}`
+	_, err := ParseRules([]byte(data), "json")
+	if err == nil {
+		t.Fatal("expected error for missing required fields")
+	}
+	if !strings.Contains(err.Error(), `field "title" is required`) || !strings.Contains(err.Error(), `field "url" is required`) {
+		t.Errorf("expected both required fields to be reported, got: %v", err)
+	}
+}
+
+func TestParseRules_RejectsUnknownKeys(t *testing.T) {
+	data := `
+sources:
+  - name: typo
+    urls: ["https://x.example.in/"]
+    rows: "li"
+`
+	if _, err := ParseRules([]byte(data), "yaml"); err == nil {
+		t.Error("expected error for misspelled key 'rows'")
+	}
+}
+
+func TestParseRules_RejectsDuplicateNames(t *testing.T) {
+	data := `{"sources":[
+		{"name":"dup","urls":["https://a.example.in/"],"row":"li","fields":{"title":{},"url":{"attr":"href"}}},
+		{"name":"DUP","urls":["https://b.example.in/"],"row":"li","fields":{"title":{},"url":{"attr":"href"}}}]}`
+	_, err := ParseRules([]byte(data), "json")
+	if err == nil || !strings.Contains(err.Error(), "duplicate name") {
+		t.Errorf("expected duplicate name error, got: %v", err)
+	}
+}
+
+func TestLoadRules_ByExtension(t *testing.T) {
+	dir := t.TempDir()
+
+	yamlPath := filepath.Join(dir, "rules.yml")
+	if err := os.WriteFile(yamlPath, []byte(validRulesYAML), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadRules(yamlPath); err != nil {
+		t.Errorf("unexpected error loading .yml: %v", err)
+	}
+
+	txtPath := filepath.Join(dir, "rules.txt")
+	if err := os.WriteFile(txtPath, []byte(validRulesYAML), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadRules(txtPath); err == nil {
+		t.Error("expected error for unsupported extension")
+	}
+}
+
+func TestLoadRules_ExampleConfigIsValid(t *testing.T) {
+	sources, err := LoadRules(filepath.Join("..", "..", "config", "sources.example.yaml"))
+	if err != nil {
+		t.Fatalf("example config failed validation: %v", err)
+	}
+	if len(sources) == 0 {
+		t.Error("expected example config to define sources")
+	}
+}
+
+func TestRegistry_OverrideReplacesBuiltin(t *testing.T) {
+	sources, err := ParseRules([]byte(validRulesYAML), "yaml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	r := DefaultRegistry()
+	if err := r.Override(sources[0]); err != nil {
+		t.Fatalf("Override failed: %v", err)
+	}
+
+	got, _ := r.Get("nic")
+	if _, ok := got.(*RuleSource); !ok {
+		t.Errorf("expected nic to be replaced by the rule source, got %T", got)
+	}
+	if all := r.All(); len(all) != 4 || all[0].Name() != "nic" {
+		t.Errorf("expected override to keep position and count, got %v", sourceNames(all))
+	}
+}
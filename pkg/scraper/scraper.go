@@ -2,6 +2,7 @@ package scraper
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"strings"
@@ -20,16 +21,20 @@ type Scraper struct {
 	Logger     *slog.Logger
 	ChromePath string // Custom browser path (empty = auto-detect)
 	Timeout    time.Duration
+	// StrictEgress stops region-bound sources from connecting directly
+	// once proxies are configured; see directAllowed.
+	StrictEgress bool
 }
 
 // Config holds initialization parameters for the Scraper.
 type Config struct {
-	TargetURL  string
-	Rotator    *proxy.ProxyRotator
-	RetryCfg   RetryConfig
-	Logger     *slog.Logger
-	ChromePath string
-	Timeout    time.Duration
+	TargetURL    string
+	Rotator      *proxy.ProxyRotator
+	RetryCfg     RetryConfig
+	Logger       *slog.Logger
+	ChromePath   string
+	Timeout      time.Duration
+	StrictEgress bool
 }
 
 // NewScraper creates a Scraper with all dependencies injected.
@@ -42,147 +47,353 @@ func NewScraper(cfg Config) *Scraper {
 	}
 
 	return &Scraper{
-		TargetURL:  cfg.TargetURL,
-		Rotator:    cfg.Rotator,
-		RetryCfg:   cfg.RetryCfg,
-		Logger:     cfg.Logger,
-		ChromePath: cfg.ChromePath,
-		Timeout:    cfg.Timeout,
+		TargetURL:    cfg.TargetURL,
+		Rotator:      cfg.Rotator,
+		RetryCfg:     cfg.RetryCfg,
+		Logger:       cfg.Logger,
+		ChromePath:   cfg.ChromePath,
+		Timeout:      cfg.Timeout,
+		StrictEgress: cfg.StrictEgress,
 	}
 }
 
-// Scrape fetches job postings from the target URL using chromedp with
-// proxy rotation, retry logic, and anti-bot countermeasures.
+// Scrape fetches job postings from the configured TargetURL using the NIC
+// parser. It is ScrapeContext without cancellation.
 func (s *Scraper) Scrape() (*models.JobList, error) {
+	return s.ScrapeContext(context.Background())
+}
+
+// ScrapeContext fetches job postings from the configured TargetURL using the
+// NIC parser. Cancelling ctx aborts retries, delays and the browser session.
+// Multi-source runs should use ScrapeSource or the Orchestrator.
+func (s *Scraper) ScrapeContext(ctx context.Context) (*models.JobList, error) {
+	return s.ScrapeSource(ctx, NICSource(s.TargetURL))
+}
+
+// ScrapeSource fetches and parses every listing page of src.
+// A page that fails is logged and skipped; an error is returned only when
+// no page of the source could be scraped.
+func (s *Scraper) ScrapeSource(ctx context.Context, src Source) (*models.JobList, error) {
+	pages := src.URLs()
+	jobs := make([]*models.JobPosting, 0, 64)
+
+	var lastErr error
+	succeeded := 0
+	for _, pageURL := range pages {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, fmt.Errorf("source %s: %w", src.Name(), ctxErr)
+		}
+
+		pageJobs, err := s.ScrapePage(ctx, src, pageURL)
+		if err != nil {
+			lastErr = err
+			s.Logger.Warn("page scrape failed",
+				slog.String("source", src.Name()),
+				slog.String("url", pageURL),
+				slog.String("error", err.Error()),
+			)
+			continue
+		}
+		succeeded++
+		jobs = append(jobs, pageJobs...)
+	}
+
+	if succeeded == 0 && lastErr != nil {
+		return nil, fmt.Errorf("source %s: %w", src.Name(), lastErr)
+	}
+
+	jobList := &models.JobList{
+		LastUpdated: time.Now().Unix(),
+		Jobs:        jobs,
+	}
+
+	s.Logger.Info("scrape complete",
+		slog.String("source", src.Name()),
+		slog.Int("pages_scraped", succeeded),
+		slog.Int("jobs_found", len(jobs)),
+	)
+
+	return jobList, nil
+}
+
+// ScrapePage fetches a single listing page of src and parses it.
+func (s *Scraper) ScrapePage(ctx context.Context, src Source, pageURL string) ([]*models.JobPosting, error) {
+	htmlContent, err := s.fetchPage(ctx, pageURL, src.Strategy(), src.EgressRegion())
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse HTML into structured job data — applies to every fetch path.
+	jobs, err := src.Parse(htmlContent, s.Logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse HTML from %s: %w", pageURL, err)
+	}
+	return jobs, nil
+}
+
+// fetchPage downloads pageURL using the given strategy, through proxies that
+// exit in region ("" = any).
+func (s *Scraper) fetchPage(ctx context.Context, pageURL string, strategy FetchStrategy, region string) (string, error) {
+	if strategy == FetchHTTP {
+		return s.fetchHTTP(ctx, pageURL, region)
+	}
+	return s.fetchBrowser(ctx, pageURL, region)
+}
+
+// fetchHTTP downloads pageURL with net/http only: retried via the rotating
+// proxy first, then a single direct attempt as a last resort.
+func (s *Scraper) fetchHTTP(ctx context.Context, pageURL, region string) (string, error) {
+	var htmlContent string
+	err := s.fetchWithFallback(ctx, pageURL, region, func(attemptCtx context.Context, proxyURL string) error {
+		html, fetchErr := FetchHTML(attemptCtx, pageURL, proxyURL, s.Timeout)
+		if fetchErr != nil {
+			return fetchErr
+		}
+		htmlContent = html
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return htmlContent, nil
+}
+
+// errNoRegionProxy is returned, with StrictEgress, for a source that
+// requires an egress region when no proxy exiting there is available.
+var errNoRegionProxy = errors.New("no proxy available in the required egress region")
+
+// directAllowed reports whether a page of a source requiring region ("" =
+// any) may be fetched without a proxy. Direct egress is never verified, so
+// with StrictEgress and proxies configured a region-bound source only goes
+// through proxies exiting there. Otherwise it falls back to direct like any
+// other source, for runners that are themselves in the region.
+func (s *Scraper) directAllowed(region string) bool {
+	return region == "" || !s.StrictEgress || s.Rotator == nil || !s.Rotator.HasProxies()
+}
+
+// fetchWithFallback runs fetch with retries through the rotating proxy,
+// restricted to proxies exiting in region ("" = any), and, if every attempt
+// fails and directAllowed, once more over a direct connection. Each call of
+// fetch gets its own timeout-bound context and the proxy to use ("" =
+// direct).
+func (s *Scraper) fetchWithFallback(ctx context.Context, targetURL, region string, fetch func(ctx context.Context, proxyURL string) error) error {
+	var usedProxy string
+
+	if s.Rotator != nil {
+		usedProxy = s.Rotator.ProxyForRegion(targetURL, region)
+	}
+
+	err := WithRetryContext(ctx, s.RetryCfg, targetURL, maskProxy(usedProxy), s.Logger, func(attempt int) error {
+		if attempt > 0 && s.Rotator != nil {
+			usedProxy = s.Rotator.ProxyForRegion(targetURL, region)
+		}
+		if usedProxy == "" && !s.directAllowed(region) {
+			return fmt.Errorf("%s: %w", region, errNoRegionProxy)
+		}
+
+		attemptCtx, cancel := context.WithTimeout(ctx, s.Timeout)
+		defer cancel()
+
+		start := time.Now()
+		err := fetch(attemptCtx, usedProxy)
+		s.reportProxy(ctx, usedProxy, start, err)
+		return err
+	})
+	if err == nil {
+		return nil
+	}
+	if usedProxy == "" || ctx.Err() != nil || !s.directAllowed(region) {
+		return err
+	}
+
+	s.Logger.Warn("HTTP via proxy failed — attempting direct HTTP (no proxy)",
+		slog.String("url", targetURL),
+		slog.String("proxy_error", err.Error()),
+	)
+
+	directCtx, cancel := context.WithTimeout(ctx, s.Timeout)
+	defer cancel()
+
+	if err := fetch(directCtx, ""); err != nil {
+		return fmt.Errorf("all HTTP paths exhausted — proxy failed, direct failed: %w", err)
+	}
+	return nil
+}
+
+// reportProxy tells the rotator how a request through proxyURL that started
+// at start went, so proxies that keep failing are ejected and fast ones
+// favoured. Errors that are not the proxy's fault (see isProxyFailure) are
+// not held against it, nor are failures once the caller has cancelled ctx.
+// Direct connections are not reported.
+func (s *Scraper) reportProxy(ctx context.Context, proxyURL string, start time.Time, err error) {
+	if s.Rotator == nil || proxyURL == "" || (err != nil && ctx.Err() != nil) {
+		return
+	}
+	switch {
+	case err == nil:
+		s.Rotator.ReportSuccess(proxyURL, time.Since(start))
+	case isProxyFailure(err):
+		s.Rotator.ReportFailure(proxyURL, err)
+	}
+}
+
+// fetchBrowser downloads pageURL using chromedp with proxy rotation, retry
+// logic, and anti-bot countermeasures, falling back to plain HTTP. Only
+// proxies exiting in region ("" = any) are used, and a direct connection
+// only when directAllowed.
+func (s *Scraper) fetchBrowser(ctx context.Context, pageURL, region string) (string, error) {
 	var htmlContent string
 	var usedProxy string
 
 	// Resolve the initial proxy once so the fallback can use the same one.
 	// The rotator advances on each call, so we capture it before the retry loop.
 	if s.Rotator != nil {
-		usedProxy = s.Rotator.ProxyServerAddr()
+		usedProxy = s.Rotator.ProxyForRegion(pageURL, region)
 	}
 
 	// Wrap the entire chromedp operation in the retry loop.
 	// Pass usedProxy to WithRetry so failure logs show the correct proxy.
-	err := WithRetry(s.RetryCfg, s.TargetURL, usedProxy, s.Logger, func(attempt int) error {
+	err := WithRetryContext(ctx, s.RetryCfg, pageURL, maskProxy(usedProxy), s.Logger, func(attempt int) error {
 		// Re-resolve proxy on each retry to allow rotation across attempts.
 		proxyURL := usedProxy
 		if s.Rotator != nil {
-			proxyURL = s.Rotator.ProxyServerAddr()
+			proxyURL = s.Rotator.ProxyForRegion(pageURL, region)
 			usedProxy = proxyURL
 		}
+		if proxyURL == "" && !s.directAllowed(region) {
+			return fmt.Errorf("%s: %w", region, errNoRegionProxy)
+		}
 
 		s.Logger.Info("scrape attempt starting",
-			slog.String("url", s.TargetURL),
+			slog.String("url", pageURL),
 			slog.String("proxy_used", maskProxy(proxyURL)),
 			slog.Int("attempt", attempt+1),
 		)
 
 		// Human-like delay before request (1–3 seconds)
-		HumanDelay(1, 3)
+		if delayErr := HumanDelayContext(ctx, 1, 3); delayErr != nil {
+			return delayErr
+		}
+
+		bp, err := newBrowserProxy(proxyURL)
+		if err != nil {
+			return fmt.Errorf("failed to set up proxy for chromedp: %w", err)
+		}
+		defer bp.Close()
 
-		// Build chromedp allocator with anti-bot options
-		opts := ChromedpAllocatorOpts(proxyURL, s.ChromePath)
+		// Build chromedp allocator with anti-bot options.
+		// Deriving from ctx means cancellation also kills the browser process.
+		opts := ChromedpAllocatorOpts(bp.server, s.ChromePath)
 
-		allocCtx, allocCancel := chromedp.NewExecAllocator(context.Background(), opts...)
+		allocCtx, allocCancel := chromedp.NewExecAllocator(ctx, opts...)
 		defer allocCancel()
 
-		ctx, ctxCancel := chromedp.NewContext(allocCtx)
-		defer ctxCancel()
+		browserCtx, browserCancel := chromedp.NewContext(allocCtx)
+		defer browserCancel()
 
 		// Operation timeout
-		ctx, timeoutCancel := context.WithTimeout(ctx, s.Timeout)
+		browserCtx, timeoutCancel := context.WithTimeout(browserCtx, s.Timeout)
 		defer timeoutCancel()
 
+		// Answer the proxy's credential challenge, if it has credentials.
+		bp.listen(browserCtx)
+
 		var html string
-		runErr := chromedp.Run(ctx,
-			chromedp.Navigate(s.TargetURL),
+		start := time.Now()
+		runErr := chromedp.Run(browserCtx,
+			bp.enableAuth(),
+			chromedp.Navigate(pageURL),
 			chromedp.WaitVisible("body", chromedp.ByQuery),
 			// Small human-like delay before extraction
 			chromedp.Sleep(time.Duration(1)*time.Second),
 			chromedp.OuterHTML("html", &html),
 		)
+		if authErr := bp.err(); authErr != nil {
+			runErr = authErr
+		}
+		s.reportProxy(ctx, proxyURL, start, runErr)
 		if runErr != nil {
 			return fmt.Errorf("chromedp navigation failed: %w", runErr)
 		}
 
 		// Validate we got meaningful HTML
 		if strings.TrimSpace(html) == "" || len(html) < 100 {
-			return fmt.Errorf("empty response from %s", s.TargetURL)
+			return fmt.Errorf("empty response from %s", pageURL)
 		}
 
 		htmlContent = html
 		return nil
 	})
 
+	if err != nil && ctx.Err() != nil {
+		// The caller gave up — falling back would only delay shutdown.
+		return "", fmt.Errorf("scrape of %s cancelled: %w", pageURL, err)
+	}
+
+	if err != nil && usedProxy == "" && !s.directAllowed(region) {
+		return "", fmt.Errorf("scrape of %s failed: %w", pageURL, err)
+	}
+
 	if err != nil {
 		// Tier 2: HTTP via proxy.
 		// Handles cases where the proxy supports plain HTTP but not CONNECT tunnels.
 		s.Logger.Warn("chromedp failed — attempting HTTP fallback via proxy",
-			slog.String("url", s.TargetURL),
+			slog.String("url", pageURL),
 			slog.String("proxy", maskProxy(usedProxy)),
 			slog.String("reason", err.Error()),
 		)
 
-		tier2Ctx, tier2Cancel := context.WithTimeout(context.Background(), s.Timeout)
+		tier2Ctx, tier2Cancel := context.WithTimeout(ctx, s.Timeout)
 		defer tier2Cancel()
 
-		htmlContent, err = FetchHTML(tier2Ctx, s.TargetURL, usedProxy, s.Timeout)
+		start := time.Now()
+		htmlContent, err = FetchHTML(tier2Ctx, pageURL, usedProxy, s.Timeout)
+		s.reportProxy(ctx, usedProxy, start, err)
+		if err != nil && !s.directAllowed(region) {
+			return "", fmt.Errorf(
+				"all scrape paths exhausted — chromedp: proxy failed, HTTP: proxy failed, no direct attempt outside region %s: %w",
+				region, err,
+			)
+		}
 		if err != nil {
 			// Tier 3: HTTP direct — no proxy.
 			// Last resort for when the proxy is fundamentally broken (CONNECT refused,
 			// access denied, wrong type). The target site is server-rendered so a plain
 			// HTTP GET works without a browser or proxy.
 			s.Logger.Warn("HTTP via proxy failed — attempting direct HTTP (no proxy)",
-				slog.String("url", s.TargetURL),
+				slog.String("url", pageURL),
 				slog.String("proxy_error", err.Error()),
 			)
 
-			tier3Ctx, tier3Cancel := context.WithTimeout(context.Background(), s.Timeout)
+			tier3Ctx, tier3Cancel := context.WithTimeout(ctx, s.Timeout)
 			defer tier3Cancel()
 
-			htmlContent, err = FetchHTML(tier3Ctx, s.TargetURL, "", s.Timeout)
+			htmlContent, err = FetchHTML(tier3Ctx, pageURL, "", s.Timeout)
 			if err != nil {
-				return nil, fmt.Errorf(
+				return "", fmt.Errorf(
 					"all scrape paths exhausted — chromedp: proxy failed, HTTP: proxy failed, HTTP: direct failed: %w",
 					err,
 				)
 			}
 
 			s.Logger.Info("direct HTTP fallback succeeded",
-				slog.String("url", s.TargetURL),
+				slog.String("url", pageURL),
 				slog.Int("html_length", len(htmlContent)),
 			)
 		} else {
 			s.Logger.Info("HTTP via proxy fallback succeeded",
-				slog.String("url", s.TargetURL),
+				slog.String("url", pageURL),
 				slog.String("proxy", maskProxy(usedProxy)),
 				slog.Int("html_length", len(htmlContent)),
 			)
 		}
 	} else {
 		s.Logger.Info("page loaded successfully via chromedp",
-			slog.String("url", s.TargetURL),
+			slog.String("url", pageURL),
 			slog.String("proxy_used", maskProxy(usedProxy)),
 			slog.Int("html_length", len(htmlContent)),
 		)
 	}
 
-	// Parse HTML into structured job data — applies to both chromedp success and HTTP fallback.
-	jobs, err := ParseJobs(htmlContent, s.Logger)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse HTML: %w", err)
-	}
-
-	jobList := &models.JobList{
-		LastUpdated: time.Now().Unix(),
-		Jobs:        jobs,
-	}
-
-	s.Logger.Info("scrape complete",
-		slog.Int("jobs_found", len(jobs)),
-	)
-
-	return jobList, nil
+	return htmlContent, nil
 }
@@ -0,0 +1,158 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/entreya/job-aggregation/pkg/proxy"
+	"github.com/entreya/job-aggregation/pkg/proxy/proxytest"
+)
+
+func TestFetchHTTP_ReportsProxyHealth(t *testing.T) {
+	site := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `<html><body><a href="/a.pdf">Post</a></body></html>`)
+	}))
+	defer site.Close()
+
+	// A port with nothing listening stands in for a dead proxy.
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dead := "http://" + ln.Addr().String()
+	ln.Close()
+
+	rotator, err := proxy.New(proxy.Config{
+		URLs:   dead,
+		Health: proxy.HealthConfig{FailureThreshold: 2, Cooldown: time.Hour},
+		Logger: testParserLogger(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewScraper(Config{
+		Rotator:  rotator,
+		RetryCfg: RetryConfig{MaxRetries: 2, BaseDelay: time.Millisecond},
+		Logger:   testParserLogger(),
+		Timeout:  5 * time.Second,
+	})
+
+	// Both proxied attempts fail, then the direct fallback succeeds.
+	if _, err := s.fetchHTTP(context.Background(), site.URL, ""); err != nil {
+		t.Fatalf("direct fallback should succeed: %v", err)
+	}
+	stats := rotator.Stats()[0]
+	if stats.Failures != 2 || stats.EjectedUntil.IsZero() {
+		t.Errorf("dead proxy should be ejected: %+v", stats)
+	}
+
+	// The next fetch goes direct without trying the ejected proxy.
+	if _, err := s.fetchHTTP(context.Background(), site.URL, ""); err != nil {
+		t.Fatal(err)
+	}
+	if got := rotator.Stats()[0].Failures; got != 2 {
+		t.Errorf("ejected proxy was used again: %d failures", got)
+	}
+}
+
+func TestFetchHTTP_UsesProxiesInSourceRegion(t *testing.T) {
+	site := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `<html><body>ok</body></html>`)
+	}))
+	defer site.Close()
+	inProxy := proxytest.NewHTTP("", "")
+	defer inProxy.Close()
+	usProxy := proxytest.NewHTTP("", "")
+	defer usProxy.Close()
+
+	rotator, err := proxy.New(proxy.Config{
+		Proxies: []proxy.Proxy{
+			{URL: usProxy.URL("", ""), Country: "US"},
+			{URL: inProxy.URL("", ""), Country: "IN", Region: "IN-MH"},
+		},
+		Logger: testParserLogger(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewScraper(Config{Rotator: rotator, Logger: testParserLogger(), Timeout: 5 * time.Second})
+
+	for i := 0; i < 3; i++ {
+		if _, err := s.fetchHTTP(context.Background(), site.URL, "IN"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if inProxy.Requests() != 3 || usProxy.Requests() != 0 {
+		t.Errorf("IN proxy got %d requests, US proxy %d; want 3 and 0", inProxy.Requests(), usProxy.Requests())
+	}
+}
+
+func TestFetchHTTP_StrictEgressNeverGoesDirectForRegionBoundSource(t *testing.T) {
+	var hits atomic.Int32
+	site := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		hits.Add(1)
+		fmt.Fprint(w, `<html><body>ok</body></html>`)
+	}))
+	defer site.Close()
+	usProxy := proxytest.NewHTTP("", "")
+	defer usProxy.Close()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dead := "http://" + ln.Addr().String()
+	ln.Close()
+
+	rotator, err := proxy.New(proxy.Config{
+		Proxies: []proxy.Proxy{
+			{URL: usProxy.URL("", ""), Country: "US"},
+			{URL: dead, Country: "IN"},
+		},
+		Logger: testParserLogger(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewScraper(Config{
+		Rotator:      rotator,
+		RetryCfg:     RetryConfig{MaxRetries: 2, BaseDelay: time.Millisecond},
+		Logger:       testParserLogger(),
+		Timeout:      5 * time.Second,
+		StrictEgress: true,
+	})
+
+	// The only IN proxy fails: no direct attempt follows.
+	if _, err := s.fetchHTTP(context.Background(), site.URL, "IN"); err == nil {
+		t.Fatal("expected the fetch to fail")
+	}
+	// No proxy exits in Delhi at all.
+	if _, err := s.fetchHTTP(context.Background(), site.URL, "IN-DL"); !errors.Is(err, errNoRegionProxy) {
+		t.Errorf("expected errNoRegionProxy, got %v", err)
+	}
+	if hits.Load() != 0 || usProxy.Requests() != 0 {
+		t.Errorf("expected no request outside the region, site saw %d (%d via the US proxy)", hits.Load(), usProxy.Requests())
+	}
+
+	// A source without a region still falls back to direct.
+	if _, err := s.fetchHTTP(context.Background(), site.URL, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	// Without StrictEgress, so does a region-bound one.
+	s.StrictEgress = false
+	viaUS := usProxy.Requests()
+	if _, err := s.fetchHTTP(context.Background(), site.URL, "IN-DL"); err != nil {
+		t.Fatal(err)
+	}
+	if usProxy.Requests() != viaUS {
+		t.Error("expected a direct request, not one via the US proxy")
+	}
+}
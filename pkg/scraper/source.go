@@ -0,0 +1,298 @@
+package scraper
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+
+	"github.com/entreya/job-aggregation/pkg/models"
+)
+
+// FetchStrategy defines how a source's pages are downloaded.
+type FetchStrategy string
+
+const (
+	// FetchBrowser loads pages in headless Chrome, falling back to plain
+	// HTTP (via proxy, then direct) when the browser path fails.
+	FetchBrowser FetchStrategy = "browser"
+	// FetchHTTP skips the browser entirely and uses net/http only.
+	// Suitable for server-rendered pages that need no JavaScript.
+	FetchHTTP FetchStrategy = "http"
+)
+
+// Source describes a single job board: where its listing pages live, how
+// to download them, and how to turn the downloaded HTML into job postings.
+type Source interface {
+	// Name is the unique, lowercase identifier of the source (e.g. "nic").
+	Name() string
+	// URLs lists the listing pages to scrape for this source.
+	URLs() []string
+	// BaseURL is used to resolve relative links found on the source's pages.
+	BaseURL() string
+	// Strategy selects the fetch path used for the source's pages.
+	Strategy() FetchStrategy
+	// EgressRegion is where requests to the source must leave from, a
+	// country code such as "IN" or a region code such as "IN-MH"; proxies
+	// exiting elsewhere are not used for it. "" allows any proxy.
+	EgressRegion() string
+	// Parse extracts job postings from one downloaded listing page.
+	Parse(htmlContent string, logger *slog.Logger) ([]*models.JobPosting, error)
+}
+
+// ParseFunc parses a listing page using the given options.
+type ParseFunc func(htmlContent string, opts ParseOptions, logger *slog.Logger) ([]*models.JobPosting, error)
+
+// SiteSource is a Source backed by static configuration. It covers boards
+// whose listings can be handled by one of the package parsers.
+type SiteSource struct {
+	SourceName string
+	Pages      []string
+	Base       string
+	Fetch      FetchStrategy
+	Department string // Default department for postings from this source
+	Location   string // Default location for postings from this source
+	Egress     string // Required proxy egress region (see Source.EgressRegion)
+	Parser     ParseFunc
+}
+
+// Name implements Source.
+func (s *SiteSource) Name() string { return s.SourceName }
+
+// URLs implements Source.
+func (s *SiteSource) URLs() []string { return s.Pages }
+
+// BaseURL implements Source.
+func (s *SiteSource) BaseURL() string { return s.Base }
+
+// Strategy implements Source. Defaults to FetchBrowser when unset.
+func (s *SiteSource) Strategy() FetchStrategy {
+	if s.Fetch == "" {
+		return FetchBrowser
+	}
+	return s.Fetch
+}
+
+// EgressRegion implements Source.
+func (s *SiteSource) EgressRegion() string { return s.Egress }
+
+// Parse implements Source. Defaults to ParseTable when no parser is set.
+func (s *SiteSource) Parse(htmlContent string, logger *slog.Logger) ([]*models.JobPosting, error) {
+	parse := s.Parser
+	if parse == nil {
+		parse = ParseTable
+	}
+	return parse(htmlContent, ParseOptions{
+		BaseURL:    s.Base,
+		Department: s.Department,
+		Location:   s.Location,
+	}, logger)
+}
+
+// NICSource returns the source for the National Informatics Centre
+// recruitment portal. pageURL overrides the default listing page when non-empty.
+func NICSource(pageURL string) *SiteSource {
+	if pageURL == "" {
+		pageURL = "https://recruitment.nic.in/index_new.php"
+	}
+	return &SiteSource{
+		SourceName: "nic",
+		Pages:      []string{pageURL},
+		Base:       nicBaseURL,
+		Fetch:      FetchBrowser,
+		Department: "NIC",
+		Location:   "All India",
+		Egress:     "IN",
+		Parser:     ParseTable,
+	}
+}
+
+// SSCSource returns the source for the Staff Selection Commission.
+func SSCSource() *SiteSource {
+	return &SiteSource{
+		SourceName: "ssc",
+		Pages:      []string{"https://ssc.gov.in/home/notice-board"},
+		Base:       "https://ssc.gov.in/",
+		Fetch:      FetchBrowser,
+		Department: "Staff Selection Commission",
+		Location:   "All India",
+		Egress:     "IN",
+		Parser:     ParseTable,
+	}
+}
+
+// UPSCSource returns the source for the Union Public Service Commission.
+func UPSCSource() *SiteSource {
+	return &SiteSource{
+		SourceName: "upsc",
+		Pages:      []string{"https://upsc.gov.in/recruitment/recruitment-advertisement"},
+		Base:       "https://upsc.gov.in/",
+		Fetch:      FetchBrowser,
+		Department: "Union Public Service Commission",
+		Location:   "All India",
+		Egress:     "IN",
+		Parser:     ParseTable,
+	}
+}
+
+// IBPSSource returns the source for the Institute of Banking Personnel Selection.
+func IBPSSource() *SiteSource {
+	return &SiteSource{
+		SourceName: "ibps",
+		Pages:      []string{"https://www.ibps.in/index.php/recruitment/"},
+		Base:       "https://www.ibps.in/",
+		Fetch:      FetchBrowser,
+		Department: "Institute of Banking Personnel Selection",
+		Location:   "All India",
+		Egress:     "IN",
+		Parser:     ParseTable,
+	}
+}
+
+// Registry holds the set of known sources, keyed by name.
+// Registration order is preserved so scrape runs are deterministic.
+// Safe for concurrent use.
+type Registry struct {
+	mu      sync.RWMutex
+	sources map[string]Source
+	order   []string
+}
+
+// NewRegistry creates an empty source registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		sources: make(map[string]Source),
+	}
+}
+
+// DefaultRegistry returns a registry pre-populated with the built-in sources.
+func DefaultRegistry() *Registry {
+	r := NewRegistry()
+	for _, src := range []Source{NICSource(""), SSCSource(), UPSCSource(), IBPSSource()} {
+		// Built-in names are unique, so registration cannot fail.
+		_ = r.Register(src)
+	}
+	return r
+}
+
+// Register adds a source to the registry.
+// Returns an error if the source is invalid or its name is already taken.
+func (r *Registry) Register(src Source) error {
+	if src == nil {
+		return fmt.Errorf("source is nil")
+	}
+
+	name := strings.ToLower(strings.TrimSpace(src.Name()))
+	if name == "" {
+		return fmt.Errorf("source has an empty name")
+	}
+	if len(src.URLs()) == 0 {
+		return fmt.Errorf("source %q has no URLs", name)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.sources[name]; exists {
+		return fmt.Errorf("source %q is already registered", name)
+	}
+	r.sources[name] = src
+	r.order = append(r.order, name)
+	return nil
+}
+
+// Override registers src, replacing any source already registered under the
+// same name while keeping its position. Used for config-defined sources, which
+// may redefine a built-in board whose markup has changed.
+func (r *Registry) Override(src Source) error {
+	if src == nil {
+		return fmt.Errorf("source is nil")
+	}
+
+	name := strings.ToLower(strings.TrimSpace(src.Name()))
+	r.mu.Lock()
+	_, exists := r.sources[name]
+	if exists {
+		r.sources[name] = src
+	}
+	r.mu.Unlock()
+
+	if exists {
+		return nil
+	}
+	return r.Register(src)
+}
+
+// Get returns the source registered under name.
+func (r *Registry) Get(name string) (Source, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	src, ok := r.sources[strings.ToLower(strings.TrimSpace(name))]
+	return src, ok
+}
+
+// All returns every registered source in registration order.
+func (r *Registry) All() []Source {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	out := make([]Source, 0, len(r.order))
+	for _, name := range r.order {
+		out = append(out, r.sources[name])
+	}
+	return out
+}
+
+// Select resolves a comma-separated list of source names, e.g. "nic,ssc".
+// An empty list selects every registered source.
+// Returns an error naming the first unknown source.
+func (r *Registry) Select(names string) ([]Source, error) {
+	if strings.TrimSpace(names) == "" {
+		return r.All(), nil
+	}
+
+	out := make([]Source, 0)
+	seen := make(map[string]bool)
+	for _, name := range strings.Split(names, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" || seen[name] {
+			continue
+		}
+		src, ok := r.Get(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown source %q", name)
+		}
+		seen[name] = true
+		out = append(out, src)
+	}
+	return out, nil
+}
+
+// MergeJobLists combines several job lists into one, dropping postings whose
+// ID has already been seen. The earliest occurrence wins, so callers control
+// precedence through argument order. Nil lists are ignored.
+func MergeJobLists(lists ...*models.JobList) *models.JobList {
+	merged := &models.JobList{
+		Jobs: make([]*models.JobPosting, 0),
+	}
+	seen := make(map[string]bool)
+
+	for _, list := range lists {
+		if list == nil {
+			continue
+		}
+		if list.LastUpdated > merged.LastUpdated {
+			merged.LastUpdated = list.LastUpdated
+		}
+		for _, j := range list.Jobs {
+			if seen[j.GetId()] {
+				continue
+			}
+			seen[j.GetId()] = true
+			merged.Jobs = append(merged.Jobs, j)
+		}
+	}
+
+	return merged
+}
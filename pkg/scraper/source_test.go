@@ -0,0 +1,140 @@
+package scraper
+
+import (
+	"testing"
+
+	"github.com/entreya/job-aggregation/pkg/models"
+)
+
+func TestDefaultRegistry_ContainsBuiltinSources(t *testing.T) {
+	r := DefaultRegistry()
+
+	for _, name := range []string{"nic", "ssc", "upsc", "ibps"} {
+		src, ok := r.Get(name)
+		if !ok {
+			t.Errorf("expected built-in source %q to be registered", name)
+			continue
+		}
+		if src.BaseURL() == "" {
+			t.Errorf("source %q: expected a base URL", name)
+		}
+		if len(src.URLs()) == 0 {
+			t.Errorf("source %q: expected at least one URL", name)
+		}
+		if src.EgressRegion() != "IN" {
+			t.Errorf("source %q: egress region %q, want IN", name, src.EgressRegion())
+		}
+	}
+
+	if got := len(r.All()); got != 4 {
+		t.Errorf("expected 4 sources, got %d", got)
+	}
+}
+
+func TestRegistry_RejectsDuplicateName(t *testing.T) {
+	r := NewRegistry()
+	if err := r.Register(SSCSource()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := r.Register(SSCSource()); err == nil {
+		t.Error("expected error when registering a duplicate source name")
+	}
+}
+
+func TestRegistry_RejectsInvalidSource(t *testing.T) {
+	r := NewRegistry()
+
+	if err := r.Register(nil); err == nil {
+		t.Error("expected error for nil source")
+	}
+	if err := r.Register(&SiteSource{Pages: []string{"https://example.com"}}); err == nil {
+		t.Error("expected error for source without a name")
+	}
+	if err := r.Register(&SiteSource{SourceName: "empty"}); err == nil {
+		t.Error("expected error for source without URLs")
+	}
+}
+
+func TestRegistry_Select(t *testing.T) {
+	r := DefaultRegistry()
+
+	all, err := r.Select("")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(all) != 4 {
+		t.Errorf("empty selection should return all sources, got %d", len(all))
+	}
+
+	picked, err := r.Select(" UPSC , nic,upsc")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(picked) != 2 || picked[0].Name() != "upsc" || picked[1].Name() != "nic" {
+		t.Errorf("expected [upsc nic], got %v", sourceNames(picked))
+	}
+
+	if _, err := r.Select("nic,unknown"); err == nil {
+		t.Error("expected error for unknown source name")
+	}
+}
+
+func TestSiteSource_ParseUsesOwnBaseURL(t *testing.T) {
+	src := &SiteSource{
+		SourceName: "example",
+		Pages:      []string{"https://jobs.example.gov.in/list"},
+		Base:       "https://jobs.example.gov.in",
+		Department: "Example Board",
+		Location:   "Delhi",
+	}
+
+	html := `<html><body><a href="notice.pdf">Notice</a></body></html>`
+	jobs, err := src.Parse(html, testParserLogger())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(jobs) != 1 {
+		t.Fatalf("expected 1 job, got %d", len(jobs))
+	}
+
+	if jobs[0].Url != "https://jobs.example.gov.in/notice.pdf" {
+		t.Errorf("expected URL resolved against source base, got %q", jobs[0].Url)
+	}
+	if jobs[0].Department != "Example Board" || jobs[0].Location != "Delhi" {
+		t.Errorf("expected source defaults, got department=%q location=%q", jobs[0].Department, jobs[0].Location)
+	}
+	if src.Strategy() != FetchBrowser {
+		t.Errorf("expected default strategy %q, got %q", FetchBrowser, src.Strategy())
+	}
+}
+
+func TestMergeJobLists_DedupesByID(t *testing.T) {
+	a := &models.JobList{LastUpdated: 100, Jobs: []*models.JobPosting{
+		{Id: "1", Title: "From A"},
+		{Id: "2", Title: "From A"},
+	}}
+	b := &models.JobList{LastUpdated: 200, Jobs: []*models.JobPosting{
+		{Id: "2", Title: "From B"},
+		{Id: "3", Title: "From B"},
+	}}
+
+	merged := MergeJobLists(a, nil, b)
+
+	if len(merged.Jobs) != 3 {
+		t.Fatalf("expected 3 merged jobs, got %d", len(merged.Jobs))
+	}
+	if merged.Jobs[1].Title != "From A" {
+		t.Errorf("expected first occurrence to win, got %q", merged.Jobs[1].Title)
+	}
+	if merged.LastUpdated != 200 {
+		t.Errorf("expected latest LastUpdated 200, got %d", merged.LastUpdated)
+	}
+}
+
+func sourceNames(sources []Source) []string {
+	names := make([]string, 0, len(sources))
+	for _, s := range sources {
+		names = append(names, s.Name())
+	}
+	return names
+}
@@ -0,0 +1,379 @@
+package scraper
+
+import (
+	"log/slog"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/entreya/job-aggregation/pkg/dates"
+	"github.com/entreya/job-aggregation/pkg/models"
+)
+
+// column identifies what a listing table column holds.
+type column int
+
+const (
+	colUnknown column = iota
+	colTitle
+	colPostedDate
+	colLastDate
+	colAdvtNo
+	colSerial
+)
+
+// boilerplateSelector matches page chrome whose links are never job postings.
+const boilerplateSelector = "nav, header, footer, .nav, .navbar, .menu, .header, .footer, #header, #footer, #menu"
+
+var (
+	// advtNoRegex captures an advertisement number such as "Advt. No. 01/2026".
+	advtNoRegex = regexp.MustCompile(`(?i)\b(?:advt|advertisement)\.?\s*no\.?\s*[:\-]?\s*([A-Za-z0-9][A-Za-z0-9./\-()]*[A-Za-z0-9)])`)
+
+	// genericLinkTextRegex matches link texts that carry no title information.
+	genericLinkTextRegex = regexp.MustCompile(`(?i)^(click here.*|view( details)?|details|download|apply( online| now)?|more|read more|link|pdf|here)$`)
+
+	// trailingClickHereRegex strips "Click here to view details." style suffixes from titles.
+	trailingClickHereRegex = regexp.MustCompile(`(?i)\s*\(?click here\b.*$`)
+
+	// applyOnlineRegex matches link texts pointing at an online application portal.
+	applyOnlineRegex = regexp.MustCompile(`(?i)\bapply\s*(?:online|now)\b|\bonline\s+(?:application|registration)\b`)
+
+	// navLinkTextRegex matches common site-navigation link texts.
+	navLinkTextRegex = regexp.MustCompile(`(?i)^(home|contact( us)?|about( us)?|sitemap|site map|disclaimer|help|faq s?|faqs|feedback|privacy policy|terms( (and|&) conditions)?|screen reader access|skip to main content|login|archives?)$`)
+)
+
+// ParseTable extracts job postings from listing pages that present vacancies
+// as table rows (NIC and most central recruitment boards). For every row with
+// a link it pulls the post title, posting date, last date to apply and
+// advertisement number from the surrounding cells, using the table header to
+// identify columns where one exists.
+//
+// Links in navigation, header and footer blocks are ignored. Pages without
+// any usable table rows fall back to link extraction over the main content.
+func ParseTable(htmlContent string, opts ParseOptions, logger *slog.Logger) ([]*models.JobPosting, error) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(htmlContent))
+	if err != nil {
+		return nil, err
+	}
+
+	// Drop page chrome up front so neither mode can pick up its links.
+	doc.Find(boilerplateSelector).Remove()
+
+	jobs := make([]*models.JobPosting, 0, 64)
+	seen := make(map[string]bool)
+	skipped := 0
+
+	doc.Find("table").Each(func(_ int, table *goquery.Selection) {
+		// Nested tables are handled when their own turn comes.
+		rows := table.Find("tr").FilterFunction(func(_ int, tr *goquery.Selection) bool {
+			return tr.Closest("table").IsSelection(table)
+		})
+		columns := headerColumns(rows)
+
+		rows.Each(func(i int, tr *goquery.Selection) {
+			cells := tr.ChildrenFiltered("td")
+			if cells.Length() == 0 || tr.Find("a[href]").Length() == 0 {
+				return
+			}
+
+			job := parseRow(cells, columns, opts)
+			if job == nil {
+				skipped++
+				logger.Debug("skipped row: no usable title or URL",
+					slog.Int("row_index", i),
+					slog.String("raw_text", SanitizeString(tr.Text())),
+				)
+				return
+			}
+			if seen[job.Id] {
+				return
+			}
+			seen[job.Id] = true
+			jobs = append(jobs, job)
+		})
+	})
+
+	if len(jobs) == 0 {
+		logger.Info("no table rows found — falling back to content links")
+		return parseContentLinks(doc, opts, logger), nil
+	}
+
+	if skipped > 0 {
+		logger.Info("rows skipped during parsing",
+			slog.Int("skipped_count", skipped),
+			slog.Int("parsed_count", len(jobs)),
+		)
+	}
+
+	return jobs, nil
+}
+
+// headerColumns classifies columns using the first row made of <th> cells.
+// Returns nil when the table has no header row.
+func headerColumns(rows *goquery.Selection) []column {
+	var columns []column
+	rows.EachWithBreak(func(_ int, tr *goquery.Selection) bool {
+		headers := tr.ChildrenFiltered("th")
+		if headers.Length() == 0 {
+			return true
+		}
+		columns = make([]column, 0, headers.Length())
+		headers.Each(func(_ int, th *goquery.Selection) {
+			columns = append(columns, classifyHeader(SanitizeString(th.Text())))
+		})
+		return false
+	})
+	return columns
+}
+
+// classifyHeader maps header text such as "Last Date" to a column kind.
+func classifyHeader(text string) column {
+	t := strings.ToLower(text)
+	switch {
+	case strings.Contains(t, "last date"), strings.Contains(t, "closing"), strings.Contains(t, "end date"):
+		return colLastDate
+	case strings.Contains(t, "advt"), strings.Contains(t, "advertisement no"):
+		return colAdvtNo
+	case strings.Contains(t, "date"), strings.Contains(t, "published"), strings.Contains(t, "posted"):
+		return colPostedDate
+	case strings.Contains(t, "s.no"), strings.Contains(t, "sl."), strings.Contains(t, "sr."), t == "#", t == "no.", t == "s. no.":
+		return colSerial
+	case strings.Contains(t, "title"), strings.Contains(t, "post"), strings.Contains(t, "subject"),
+		strings.Contains(t, "description"), strings.Contains(t, "vacanc"), strings.Contains(t, "job"),
+		strings.Contains(t, "recruitment"), strings.Contains(t, "notice"):
+		return colTitle
+	default:
+		return colUnknown
+	}
+}
+
+// parseRow builds a posting from one table row, or returns nil if the row
+// has no usable title or link.
+func parseRow(cells *goquery.Selection, columns []column, opts ParseOptions) *models.JobPosting {
+	var title, link, posted, last, lastText, advtNo string
+	var lastRead dates.Result
+	var titleCell *goquery.Selection
+
+	cells.Each(func(i int, td *goquery.Selection) {
+		text := SanitizeString(td.Text())
+		kind := colUnknown
+		if i < len(columns) {
+			kind = columns[i]
+		}
+
+		switch kind {
+		case colTitle:
+			if titleCell == nil {
+				titleCell = td
+			}
+		case colPostedDate:
+			if posted == "" {
+				posted = normalizeDate(text)
+			}
+		case colLastDate:
+			if lastText == "" {
+				lastText = text
+			}
+		case colAdvtNo:
+			if advtNo == "" {
+				advtNo = text
+			}
+		}
+	})
+
+	// Link: prefer the title cell, otherwise the first link in the row.
+	anchor := cells.Find("a[href]").First()
+	if titleCell != nil && titleCell.Find("a[href]").Length() > 0 {
+		anchor = titleCell.Find("a[href]").First()
+	}
+	href, _ := anchor.Attr("href")
+	link = strings.TrimSpace(href)
+	if !isNavigableLink(link) {
+		return nil
+	}
+
+	// Title: the title column, else a descriptive link text, else the longest
+	// cell that is neither a date nor a serial number.
+	switch {
+	case titleCell != nil:
+		title = SanitizeString(titleCell.Text())
+	case !genericLinkTextRegex.MatchString(SanitizeString(anchor.Text())):
+		title = SanitizeString(anchor.Text())
+	default:
+		cells.Each(func(_ int, td *goquery.Selection) {
+			text := SanitizeString(td.Text())
+			if isDateOrSerial(text) || genericLinkTextRegex.MatchString(text) {
+				return
+			}
+			if len(text) > len(title) {
+				title = text
+			}
+		})
+	}
+	title = cleanTitle(title)
+	if title == "" || navLinkTextRegex.MatchString(title) {
+		return nil
+	}
+
+	// Fall back to scanning the whole row for anything the columns did not give us.
+	cellTexts := make([]string, 0, cells.Length())
+	cells.Each(func(_ int, td *goquery.Selection) {
+		cellTexts = append(cellTexts, SanitizeString(td.Text()))
+	})
+	rowText := strings.Join(cellTexts, " ")
+	// Deadlines such as "within 30 days of publication" count from the posting date.
+	ref, _ := time.Parse("2006-01-02", posted)
+	if r, ok := dates.Parse(lastText, ref); ok {
+		last, lastRead = r.Format(), r
+	}
+	if last == "" {
+		if r, ok := dates.Closing(rowText, ref); ok {
+			last, lastRead = r.Format(), r
+		}
+	}
+	if posted == "" && columns == nil {
+		for _, r := range dates.ParseAll(rowText, time.Time{}) {
+			if d := r.Format(); d != "" && d != last {
+				posted = d
+				break
+			}
+		}
+	}
+	if advtNo == "" {
+		if m := advtNoRegex.FindStringSubmatch(rowText); m != nil {
+			advtNo = m[1]
+			// "(Advt. No. YP-07/2026)" — drop the closing bracket of the enclosing text.
+			if strings.Count(advtNo, ")") > strings.Count(advtNo, "(") {
+				advtNo = strings.TrimSuffix(advtNo, ")")
+			}
+		}
+	}
+
+	link = resolveURL(opts.BaseURL, link)
+
+	job := &models.JobPosting{
+		Id:              GenerateID(link),
+		Title:           title,
+		Department:      opts.Department,
+		Location:        opts.Location,
+		Url:             link,
+		Date:            posted,
+		LastDate:        last,
+		AdvertisementNo: advtNo,
+
+		LastDateConfidence: dateConfidence(lastRead),
+	}
+	addRowLinks(job, cells, opts)
+	return job
+}
+
+// addRowLinks records the row's other links (corrigenda, application forms)
+// as attachments, and an "Apply online" link as the application mode.
+func addRowLinks(job *models.JobPosting, cells *goquery.Selection, opts ParseOptions) {
+	seen := map[string]bool{job.Url: true}
+	cells.Find("a[href]").Each(func(_ int, a *goquery.Selection) {
+		href, _ := a.Attr("href")
+		href = strings.TrimSpace(href)
+		if !isNavigableLink(href) {
+			return
+		}
+		text := SanitizeString(a.Text())
+		if applyOnlineRegex.MatchString(text) {
+			job.ApplicationMode = models.ApplicationMode_APPLICATION_MODE_ONLINE
+		}
+
+		link := resolveURL(opts.BaseURL, href)
+		if seen[link] {
+			return
+		}
+		seen[link] = true
+		if text == "" {
+			text = path.Base(link)
+		}
+		job.Attachments = append(job.Attachments, &models.Attachment{Title: text, Url: link})
+	})
+}
+
+// parseContentLinks is the fallback for pages without listing tables: every
+// link outside the page chrome whose text is neither navigation nor generic.
+func parseContentLinks(doc *goquery.Document, opts ParseOptions, logger *slog.Logger) []*models.JobPosting {
+	jobs := make([]*models.JobPosting, 0, 16)
+	seen := make(map[string]bool)
+
+	doc.Find("a[href]").Each(func(_ int, sel *goquery.Selection) {
+		href, _ := sel.Attr("href")
+		href = strings.TrimSpace(href)
+		text := cleanTitle(SanitizeString(sel.Text()))
+
+		if !isNavigableLink(href) || text == "" ||
+			navLinkTextRegex.MatchString(text) || genericLinkTextRegex.MatchString(text) {
+			return
+		}
+
+		link := resolveURL(opts.BaseURL, href)
+		id := GenerateID(link)
+		if seen[id] {
+			return
+		}
+		seen[id] = true
+
+		jobs = append(jobs, &models.JobPosting{
+			Id:         id,
+			Title:      text,
+			Department: opts.Department,
+			Location:   opts.Location,
+			Url:        link,
+		})
+	})
+
+	logger.Debug("content links parsed", slog.Int("parsed_count", len(jobs)))
+	return jobs
+}
+
+// isNavigableLink reports whether href points at a document rather than an
+// in-page anchor, script handler or mail address.
+func isNavigableLink(href string) bool {
+	lower := strings.ToLower(href)
+	return href != "" && !strings.HasPrefix(href, "#") &&
+		!strings.HasPrefix(lower, "javascript:") && !strings.HasPrefix(lower, "mailto:")
+}
+
+// cleanTitle removes trailing "Click here..." prompts from a sanitized title.
+func cleanTitle(title string) string {
+	return strings.TrimSpace(trailingClickHereRegex.ReplaceAllString(title, ""))
+}
+
+// isDateOrSerial reports whether text is only a date or a row number.
+func isDateOrSerial(text string) bool {
+	if text == "" {
+		return true
+	}
+	if r, ok := dates.Parse(text, time.Time{}); ok && r.Match == text {
+		return true
+	}
+	for _, r := range text {
+		if (r < '0' || r > '9') && r != '.' {
+			return false
+		}
+	}
+	return true
+}
+
+// dateConfidence is how r was read, as stored with a job's last date, or ""
+// when r holds no date.
+func dateConfidence(r dates.Result) string {
+	if r.Format() == "" {
+		return ""
+	}
+	return r.Confidence.String()
+}
+
+// normalizeDate converts the first date in s, in any format package dates
+// reads, to YYYY-MM-DD. Returns "" if s holds no date or only a deadline
+// relative to an unknown date.
+func normalizeDate(s string) string {
+	r, _ := dates.Parse(s, time.Time{})
+	return r.Format()
+}
@@ -0,0 +1,225 @@
+package scraper
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/entreya/job-aggregation/pkg/models"
+)
+
+func nicParseOptions() ParseOptions {
+	return ParseOptions{BaseURL: nicBaseURL, Department: "NIC", Location: "All India"}
+}
+
+func TestParseTable_NICListing(t *testing.T) {
+	html := loadFixture(t, "testdata/nic_listing.html")
+	jobs, err := ParseTable(html, nicParseOptions(), testParserLogger())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Rows 1 and 2 of the first table plus the walk-in row of the second.
+	// Row 3 has no link and row 4 only a javascript: link.
+	if len(jobs) != 3 {
+		for _, j := range jobs {
+			t.Logf("parsed: %q → %s", j.Title, j.Url)
+		}
+		t.Fatalf("expected 3 jobs, got %d", len(jobs))
+	}
+
+	so := jobs[0]
+	if so.Title != "Invite applications for the post of Section Officer on Deputation basis to depute at various NIC State Centres." {
+		t.Errorf("row 1: unexpected title %q", so.Title)
+	}
+	if so.Url != "https://recruitment.nic.in/AppAdv.pdf" {
+		t.Errorf("row 1: unexpected URL %q", so.Url)
+	}
+	if so.Date != "2026-02-12" || so.LastDate != "2026-03-15" {
+		t.Errorf("row 1: expected dates 2026-02-12/2026-03-15, got %q/%q", so.Date, so.LastDate)
+	}
+	if so.AdvertisementNo != "NIC/SO/01/2026" {
+		t.Errorf("row 1: unexpected advertisement number %q", so.AdvertisementNo)
+	}
+	var attachments []string
+	for _, a := range so.Attachments {
+		attachments = append(attachments, a.Title+" "+a.Url)
+	}
+	wantAttachments := []string{
+		"Corrigendum https://recruitment.nic.in/Corrigendum_SO.pdf",
+		"Apply Online https://recruitment.nic.in/apply/so",
+	}
+	if !reflect.DeepEqual(attachments, wantAttachments) {
+		t.Errorf("row 1: got attachments %q, want %q", attachments, wantAttachments)
+	}
+	if so.ApplicationMode != models.ApplicationMode_APPLICATION_MODE_ONLINE {
+		t.Errorf("row 1: expected online application from the Apply Online link, got %v", so.ApplicationMode)
+	}
+
+	sta := jobs[1]
+	if sta.Title != "Recruitment of Scientific/Technical Assistant-A, Group-B posts in NIC" {
+		t.Errorf("row 2: expected 'Click here' suffix to be stripped, got %q", sta.Title)
+	}
+	if sta.Date != "2026-02-01" || sta.LastDate != "2026-03-02" {
+		t.Errorf("row 2: expected dates 2026-02-01/2026-03-02, got %q/%q", sta.Date, sta.LastDate)
+	}
+	if len(sta.Attachments) != 0 {
+		t.Errorf("row 2: the repeated posting link is not an attachment, got %v", sta.Attachments)
+	}
+
+	yp := jobs[2]
+	if yp.AdvertisementNo != "YP-07/2026" {
+		t.Errorf("headerless row: unexpected advertisement number %q", yp.AdvertisementNo)
+	}
+	if yp.LastDate != "2026-02-28" || yp.Date != "2026-02-10" {
+		t.Errorf("headerless row: expected dates 2026-02-10/2026-02-28, got %q/%q", yp.Date, yp.LastDate)
+	}
+	if yp.Department != "NIC" || yp.Location != "All India" {
+		t.Errorf("expected source defaults, got %q/%q", yp.Department, yp.Location)
+	}
+}
+
+func TestParseTable_DropsNavigationAndFooterLinks(t *testing.T) {
+	html := loadFixture(t, "testdata/nic_listing.html")
+	jobs, err := ParseTable(html, nicParseOptions(), testParserLogger())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	banned := map[string]bool{
+		"https://recruitment.nic.in/index.php":      true,
+		"https://recruitment.nic.in/about.php":      true,
+		"https://recruitment.nic.in/contact.php":    true,
+		"https://recruitment.nic.in/disclaimer.php": true,
+		"https://www.nic.in":                        true,
+	}
+	for _, j := range jobs {
+		if banned[j.Url] {
+			t.Errorf("navigation/footer link parsed as a job: %q (%s)", j.Title, j.Url)
+		}
+	}
+}
+
+func TestParseTable_FallsBackToContentLinks(t *testing.T) {
+	html := `<html><body>
+		<nav><a href="/home">Home</a></nav>
+		<ul>
+			<li><a href="notice1.pdf">Recruitment of Drivers 2026</a></li>
+			<li><a href="notice1.pdf">Recruitment of Drivers 2026</a></li>
+			<li><a href="sitemap.php">Sitemap</a></li>
+			<li><a href="more.php">Read more</a></li>
+		</ul>
+		<footer><a href="/policy">Privacy Policy</a></footer>
+	</body></html>`
+
+	jobs, err := ParseTable(html, nicParseOptions(), testParserLogger())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(jobs) != 1 {
+		t.Fatalf("expected 1 job from content links, got %d", len(jobs))
+	}
+	if jobs[0].Url != "https://recruitment.nic.in/notice1.pdf" {
+		t.Errorf("unexpected URL %q", jobs[0].Url)
+	}
+}
+
+func TestParseTable_WrittenAndRelativeDates(t *testing.T) {
+	html := `<table>
+		<tr><th>Post</th><th>Published</th><th>Last Date</th></tr>
+		<tr><td><a href="steno.pdf">Recruitment of Stenographer</a></td><td>1st March, 2026</td><td>Within 21 days of publication in Employment News</td></tr>
+		<tr><td><a href="mts.pdf">Recruitment of Multi Tasking Staff</a></td><td>०५.०३.२०२६</td><td>20-Mar-26</td></tr>
+	</table>`
+
+	jobs, err := ParseTable(html, nicParseOptions(), testParserLogger())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(jobs) != 2 {
+		t.Fatalf("expected 2 jobs, got %d", len(jobs))
+	}
+	want := [][3]string{{"2026-03-01", "2026-03-22", "low"}, {"2026-03-05", "2026-03-20", "medium"}}
+	for i, j := range jobs {
+		if j.Date != want[i][0] || j.LastDate != want[i][1] || j.LastDateConfidence != want[i][2] {
+			t.Errorf("row %d: dates %q, %q (%s); want %q, %q (%s)", i+1,
+				j.Date, j.LastDate, j.LastDateConfidence, want[i][0], want[i][1], want[i][2])
+		}
+	}
+}
+
+func TestParseTable_SkipsHeaderOnlyTables(t *testing.T) {
+	html := `<table><tr><th>Post</th><th>Last Date</th></tr></table>`
+	jobs, err := ParseTable(html, nicParseOptions(), testParserLogger())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(jobs) != 0 {
+		t.Errorf("expected 0 jobs, got %d", len(jobs))
+	}
+}
+
+func TestNormalizeDate(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"15.03.2026", "2026-03-15"},
+		{"15/03/2026", "2026-03-15"},
+		{"5-3-2026", "2026-03-05"},
+		{"Last date: 01.12.2026 (5 PM)", "2026-12-01"},
+		{"15th March, 2026", "2026-03-15"},
+		{"15-Mar-26", "2026-03-15"},
+		{"within 30 days of publication", ""},
+		{"32.01.2026", ""},
+		{"15.13.2026", ""},
+		{"no date", ""},
+	}
+
+	for _, tt := range tests {
+		if got := normalizeDate(tt.in); got != tt.want {
+			t.Errorf("normalizeDate(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestClassifyHeader(t *testing.T) {
+	tests := []struct {
+		header string
+		want   column
+	}{
+		{"Last Date", colLastDate},
+		{"Closing Date of Application", colLastDate},
+		{"Advt. No.", colAdvtNo},
+		{"Date of Publication", colPostedDate},
+		{"S.No.", colSerial},
+		{"Name of the Post", colTitle},
+		{"Remarks", colUnknown},
+	}
+
+	for _, tt := range tests {
+		if got := classifyHeader(tt.header); got != tt.want {
+			t.Errorf("classifyHeader(%q) = %v, want %v", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestParseTable_EmptyHTML(t *testing.T) {
+	jobs, err := ParseTable("<html><body></body></html>", nicParseOptions(), testParserLogger())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(jobs) != 0 {
+		t.Errorf("expected 0 jobs for empty HTML, got %d", len(jobs))
+	}
+}
+
+func TestParseTable_MalformedHTML(t *testing.T) {
+	// goquery is tolerant of malformed HTML
+	html := "<table><tr><td><a href='test.php'>Unclosed"
+	jobs, err := ParseTable(html, nicParseOptions(), testParserLogger())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(jobs) != 1 || jobs[0].Url != "https://recruitment.nic.in/test.php" {
+		t.Errorf("expected 1 job from malformed HTML, got %+v", jobs)
+	}
+}